@@ -0,0 +1,69 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Redacted replaces secret values wherever this package reports config values.
+const Redacted = "***"
+
+// SecretMarker is implemented by Getters that know which of their keys hold secrets.
+type SecretMarker interface {
+	IsSecret(key string) bool
+}
+
+// IsSecret : Return g.IsSecret(key) if g is a SecretMarker, otherwise false.
+func IsSecret(g Getter, key string) bool {
+	if sm, ok := g.(SecretMarker); ok {
+		return sm.IsSecret(key)
+	}
+	return false
+}
+
+// WithAuditLog : Start a background goroutine that, every interval until ctx is done, enumerates g and passes
+// a snapshot of all its values to sink, for an audit trail of effective config over time. This is separate from
+// change notification: the sink gets a full snapshot on every tick, changed or not.
+//
+// g must be a Lister, and interval positive. redact is required, and is called for each key; values of keys for which it returns true,
+// or which g marks as secret (see SecretMarker), are replaced with Redacted so secrets never reach the sink. The returned Getter is g itself.
+func WithAuditLog(ctx context.Context, g Getter, sink func(snapshot map[string]string, at time.Time), interval time.Duration, redact func(key string) bool) (Getter, error) {
+	lister, ok := g.(Lister)
+	if !ok {
+		return nil, errors.New("config: audit log requires a Getter that implements Lister")
+	}
+	if redact == nil {
+		return nil, errors.New("config: audit log requires a redaction predicate")
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("config: audit log interval must be positive, got %v", interval)
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case at := <-ticker.C:
+				sink(snapshot(g, lister.Keys(), redact), at)
+			}
+		}
+	}()
+	return g, nil
+}
+
+// snapshot collects the values of keys from g, redacting as it goes.
+func snapshot(g Getter, keys []string, redact func(key string) bool) map[string]string {
+	rval := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if redact(key) || IsSecret(g, key) {
+			rval[key] = Redacted
+		} else {
+			rval[key] = g.Get(key)
+		}
+	}
+	return rval
+}
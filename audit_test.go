@@ -0,0 +1,63 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithAuditLog(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	snapshots := make(chan map[string]string, 1)
+	sink := func(s map[string]string, at time.Time) {
+		select {
+		case snapshots <- s:
+		default:
+		}
+	}
+	isSecret := func(key string) bool { return strings.HasSuffix(key, "_PASSWORD") }
+	_, err := WithAuditLog(ctx, Map{"HOST": "db", "DB_PASSWORD": "hunter2"}, sink, time.Millisecond, isSecret)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	select {
+	case s := <-snapshots:
+		if s["HOST"] != "db" || s["DB_PASSWORD"] != Redacted {
+			t.Errorf("Unexpected snapshot %v", s)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for a snapshot")
+	}
+}
+
+func TestWithAuditLogRequirements(t *testing.T) {
+	ctx := context.Background()
+	sink := func(map[string]string, time.Time) {}
+	if _, err := WithAuditLog(ctx, WithURLDecode(Map{}), sink, time.Second, func(string) bool { return false }); err == nil {
+		t.Error("Expected an error for a Getter that isn't a Lister")
+	}
+	if _, err := WithAuditLog(ctx, Map{}, sink, time.Second, nil); err == nil {
+		t.Error("Expected an error for a nil redaction predicate")
+	}
+	if _, err := WithAuditLog(ctx, Map{}, sink, 0, func(string) bool { return false }); err == nil {
+		t.Error("Expected an error for a zero interval")
+	}
+}
+
+type markedSecrets struct {
+	Map
+}
+
+func (markedSecrets) IsSecret(key string) bool {
+	return key == "TOKEN"
+}
+
+func TestSnapshotHonorsSecretMarker(t *testing.T) {
+	g := markedSecrets{Map{"TOKEN": "t", "HOST": "h"}}
+	s := snapshot(g, g.Keys(), func(string) bool { return false })
+	if s["TOKEN"] != Redacted || s["HOST"] != "h" {
+		t.Errorf("Unexpected snapshot %v", s)
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+)
+
+// Builder assembles a Getter from several sources, such as a base config file, an optional local override file and
+// the environment. See NewBuilder.
+type Builder struct {
+	sources []builderSource
+}
+
+type builderSource struct {
+	name     string
+	optional bool
+	open     func() (Getter, error)
+}
+
+// BuildWarning describes an optional source that Build left out because it failed.
+type BuildWarning struct {
+	// Source names the source: the path of a file, or the name given to AddGetter.
+	Source string
+	Err    error
+}
+
+func (w BuildWarning) String() string {
+	return fmt.Sprintf("%s: %v", w.Source, w.Err)
+}
+
+// NewBuilder : Return an empty Builder. Sources are added with the Add methods, which can be chained, and Build
+// layers them so that sources added later override those added earlier:
+//
+//	g, warnings, err := config.NewBuilder().
+//		AddJSONFile("config.json").
+//		AddJSONFileOptional("config.local.json").
+//		AddGetter("environment", config.Environment()).
+//		Build()
+//	for _, w := range warnings {
+//		log.Printf("config: skipped %s", w)
+//	}
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+func (b *Builder) add(name string, optional bool, open func() (Getter, error)) *Builder {
+	b.sources = append(b.sources, builderSource{name: name, optional: optional, open: open})
+	return b
+}
+
+// AddFile : Add the config file at path, read with NewFileGetter. Build fails if it can't be read.
+func (b *Builder) AddFile(path string, opts ...FileOption) *Builder {
+	return b.add(path, false, func() (Getter, error) { return NewFileGetter(path, opts...) })
+}
+
+// AddFileOptional : Like AddFile, but if the file can't be read (or is missing) Build leaves it out with a warning.
+func (b *Builder) AddFileOptional(path string, opts ...FileOption) *Builder {
+	return b.add(path, true, func() (Getter, error) { return NewFileGetter(path, opts...) })
+}
+
+// AddJSONFile : Add the file at path, parsed as JSON (see FlattenValue) whatever its extension. Build fails if it
+// can't be read or parsed.
+func (b *Builder) AddJSONFile(path string) *Builder {
+	return b.add(path, false, func() (Getter, error) { return readFile(path, "json", fileOptions{}) })
+}
+
+// AddJSONFileOptional : Like AddJSONFile, but if the file can't be read (or is missing) Build leaves it out with a
+// warning.
+func (b *Builder) AddJSONFileOptional(path string) *Builder {
+	return b.add(path, true, func() (Getter, error) { return readFile(path, "json", fileOptions{}) })
+}
+
+// AddYAMLFile : Add the file at path, parsed as YAML whatever its extension (import yamlconfig to register the
+// format). Build fails if it can't be read or parsed.
+func (b *Builder) AddYAMLFile(path string) *Builder {
+	return b.add(path, false, func() (Getter, error) { return readFile(path, "yaml", fileOptions{}) })
+}
+
+// AddYAMLFileOptional : Like AddYAMLFile, but if the file can't be read Build leaves it out with a warning.
+func (b *Builder) AddYAMLFileOptional(path string) *Builder {
+	return b.add(path, true, func() (Getter, error) { return readFile(path, "yaml", fileOptions{}) })
+}
+
+// AddGetter : Add a Getter that's already been set up, such as Environment(). name identifies it in errors.
+func (b *Builder) AddGetter(name string, g Getter) *Builder {
+	return b.add(name, false, func() (Getter, error) { return g, nil })
+}
+
+// Build : Open each source, in the order they were added, and return a Getter over them in which later sources
+// override earlier ones for the keys they have a non-empty value for (see Merge and LastNonEmpty). An optional source
+// that fails is left out, and reported in the returned warnings along with its error; any other failure is returned
+// as an error naming the source, and no Getter is built.
+func (b *Builder) Build() (Getter, []BuildWarning, error) {
+	var warnings []BuildWarning
+	getters := make([]Getter, 0, len(b.sources))
+	for _, source := range b.sources {
+		g, err := source.open()
+		switch {
+		case err == nil:
+			getters = append(getters, g)
+		case source.optional:
+			warnings = append(warnings, BuildWarning{Source: source.name, Err: err})
+		default:
+			return nil, warnings, fmt.Errorf("config: building %s: %w", source.name, err)
+		}
+	}
+	return Merge(LastNonEmpty, getters...), warnings, nil
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	base := writeConfigFile(t, "config.json", `{"db": {"host": "db1", "port": 5432}, "hosts": ["a", "b"]}`)
+	local := writeConfigFile(t, "config.local", `{"db": {"host": "localhost"}}`)
+	extra := writeConfigFile(t, "extra.env", "LOG_LEVEL=debug\n")
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	g, warnings, err := NewBuilder().
+		AddJSONFile(base).
+		AddJSONFileOptional(local).
+		AddJSONFileOptional(missing).
+		AddFileOptional(extra).
+		AddGetter("overrides", Map{"db.port": "6543", "db.host": ""}).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := g.Get("db.host"); v != "localhost" {
+		t.Errorf("Expected the later file to override, got '%s'", v)
+	}
+	if v := g.Get("db.port"); v != "6543" {
+		t.Errorf("Expected the overrides to win, got '%s'", v)
+	}
+	if v := g.GetStrings("hosts"); len(v) != 2 || v[1] != "b" {
+		t.Errorf("Expected the base file's list, got %q", v)
+	}
+	if v := g.Get("LOG_LEVEL"); v != "debug" {
+		t.Errorf("Expected 'debug' from the env file, got '%s'", v)
+	}
+	if len(warnings) != 1 || warnings[0].Source != missing || !errors.Is(warnings[0].Err, fs.ErrNotExist) {
+		t.Errorf("Expected a warning for the missing optional file, got %v", warnings)
+	}
+}
+
+func TestBuilderRequiredSource(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	if _, _, err := NewBuilder().AddJSONFile(missing).Build(); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected a missing required file to fail the build, got %v", err)
+	}
+	bad := writeConfigFile(t, "bad.json", "{")
+	g, warnings, err := NewBuilder().AddJSONFileOptional(bad).Build()
+	if err != nil || g == nil || len(warnings) != 1 {
+		t.Errorf("Expected a malformed optional file to be skipped with a warning, got %v, %v", warnings, err)
+	}
+	if _, _, err := NewBuilder().AddFile(bad).Build(); err == nil {
+		t.Error("Expected a malformed required file to fail the build")
+	}
+	yaml := writeConfigFile(t, "config.yaml", "a: 1\n")
+	if _, _, err := NewBuilder().AddYAMLFile(yaml).Build(); err == nil || !strings.Contains(err.Error(), "yamlconfig") {
+		t.Errorf("Expected an error naming the package that registers YAML, got %v", err)
+	}
+}
@@ -0,0 +1,251 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Cached : Wrap a Getter so that its lookups are remembered, which is mostly useful for
+// getters that are expensive to query (remote backends and the like).
+//
+// Found values are cached for ttl. Empty (not found) results are cached for negativeTTL, which is
+// typically much shorter, so that a key transitioning from missing to present becomes visible
+// within negativeTTL rather than the full ttl, while misses still don't hit the backend on every call.
+// A negativeTTL of zero disables caching of misses.
+//
+// The wrapped Getter is called without holding the cache's lock, and concurrent lookups of the same key share one
+// call, so a slow backend only holds up the callers waiting on that key. If the wrapped Getter is an ErrorGetter or
+// a ContextGetter, GetErr and GetContext go through to it; its failures are returned and not cached, while an
+// error wrapping ErrKeyNotSet is cached as a miss.
+func Cached(g Getter, ttl time.Duration, negativeTTL time.Duration) Getter {
+	return &CachedGetter{
+		g:           g,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		values:      newCacheTable[string](),
+		lists:       newCacheTable[[]string](),
+		now:         time.Now,
+	}
+}
+
+// CachedGetter is the Getter returned by Cached().
+type CachedGetter struct {
+	g           Getter
+	ttl         time.Duration
+	negativeTTL time.Duration
+	lock        sync.Mutex
+	values      cacheTable[string]
+	lists       cacheTable[[]string]
+	generation  uint64
+	now         func() time.Time
+}
+
+// cacheTable holds the cached results of one kind of lookup, and the lookups in flight.
+type cacheTable[T any] struct {
+	entries  map[string]cacheEntry[T]
+	inFlight map[string]*cacheCall[T]
+}
+
+func newCacheTable[T any]() cacheTable[T] {
+	return cacheTable[T]{entries: make(map[string]cacheEntry[T]), inFlight: make(map[string]*cacheCall[T])}
+}
+
+type cacheEntry[T any] struct {
+	val     T
+	expires time.Time
+}
+
+// cacheCall is a lookup in flight, which callers asking for the same key wait on instead of calling the backend.
+type cacheCall[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+// cacheLookup returns the cached result for key from t, or fetches it, sharing the fetch with concurrent lookups of
+// the same key. Results that miss are cached for negativeTTL; errors wrapping ErrKeyNotSet count as misses and other
+// errors aren't cached. A fetch that started before an Invalidate doesn't store its result, which may be stale.
+func cacheLookup[T any](ctx context.Context, c *CachedGetter, t *cacheTable[T], key string,
+	fetch func(context.Context) (T, error), miss func(T) bool) (T, error) {
+	for {
+		c.lock.Lock()
+		if entry, ok := t.entries[key]; ok && c.now().Before(entry.expires) {
+			c.lock.Unlock()
+			return entry.val, nil
+		}
+		call, waiting := t.inFlight[key]
+		if !waiting {
+			call = &cacheCall[T]{done: make(chan struct{})}
+			t.inFlight[key] = call
+		}
+		generation := c.generation
+		c.lock.Unlock()
+
+		if waiting {
+			select {
+			case <-call.done:
+			case <-ctx.Done():
+				var zero T
+				return zero, ctx.Err()
+			}
+			// Don't pass on a cancellation that belonged to the caller that made the fetch.
+			if isContextErr(call.err) && ctx.Err() == nil {
+				continue
+			}
+			return call.val, call.err
+		}
+
+		call.val, call.err = fetch(ctx)
+		if errors.Is(call.err, ErrKeyNotSet) {
+			var zero T
+			call.val, call.err = zero, nil
+		}
+		c.lock.Lock()
+		if call.err == nil && c.generation == generation {
+			if ttl := c.ttlFor(miss(call.val)); ttl > 0 {
+				t.entries[key] = cacheEntry[T]{val: call.val, expires: c.now().Add(ttl)}
+			}
+		}
+		if t.inFlight[key] == call {
+			delete(t.inFlight, key)
+		}
+		c.lock.Unlock()
+		close(call.done)
+		return call.val, call.err
+	}
+}
+
+func isContextErr(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func isEmptyString(val string) bool {
+	return val == ""
+}
+
+// GetContext : Return the cached value for key, going to the wrapped Getter (see GetContext) if the cache entry is
+// absent or expired. An unset key is "" and a nil error.
+func (c *CachedGetter) GetContext(ctx context.Context, key string) (string, error) {
+	return cacheLookup(ctx, c, &c.values, key, func(ctx context.Context) (string, error) {
+		return GetContext(ctx, c.g, key)
+	}, isEmptyString)
+}
+
+// GetErr : Like GetContext, without a deadline.
+func (c *CachedGetter) GetErr(key string) (string, error) {
+	return c.GetContext(context.Background(), key)
+}
+
+// Get : Return the cached value for key, going to the wrapped Getter if the cache entry is absent or expired.
+func (c *CachedGetter) Get(key string) string {
+	val, _ := c.GetErr(key)
+	return val
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (c *CachedGetter) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(c, key, dflt)
+}
+
+// GetStrings : Return the wrapped Getter's GetStrings(), cached under the same TTL rules as Get().
+func (c *CachedGetter) GetStrings(key string) []string {
+	vals, _ := cacheLookup(context.Background(), c, &c.lists, key, func(context.Context) ([]string, error) {
+		return append([]string(nil), c.g.GetStrings(key)...), nil
+	}, isEmptyList)
+	return append([]string(nil), vals...)
+}
+
+// MustGet will panic if the key is not present or empty.
+func (c *CachedGetter) MustGet(key string) string {
+	return MustGet(c, key)
+}
+
+// Prefetch : Fetch keys from the wrapped Getter in parallel and cache them, so that the Gets and GetStrings that
+// follow (binding a large struct, say) are served from the cache instead of going to the backend one key at a time.
+// At most concurrency keys are fetched at once (at least one), which bounds the load on the backend; keys that are
+// already cached and unexpired aren't fetched. Values are cached under the same TTL rules as Get, and a key that's
+// invalidated while it's being fetched isn't cached.
+//
+// Fetches go through GetContext, so ctx reaches backends that are ContextGetters; once ctx is done no more fetches
+// are started. Unset keys aren't errors. Prefetch returns the errors of all the fetches that failed, joined (see
+// errors.Join), along with ctx's error if it was done before every fetch started, or nil. It complements
+// ConsistentGetter's GetBatch, which is about reading keys from one snapshot rather than about latency.
+func (c *CachedGetter) Prefetch(ctx context.Context, keys []string, concurrency int) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	work := make(chan string)
+	var (
+		wg      sync.WaitGroup
+		errLock sync.Mutex
+		errs    []error
+	)
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range work {
+				if _, err := c.GetContext(ctx, key); err != nil {
+					errLock.Lock()
+					errs = append(errs, err)
+					errLock.Unlock()
+					continue
+				}
+				c.GetStrings(key)
+			}
+		}()
+	}
+dispatch:
+	for _, key := range keys {
+		if c.fresh(key) {
+			continue
+		}
+		select {
+		case work <- key:
+		case <-ctx.Done():
+			errLock.Lock()
+			errs = append(errs, ctx.Err())
+			errLock.Unlock()
+			break dispatch
+		}
+	}
+	close(work)
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// fresh reports whether key has unexpired cache entries for both Get and GetStrings.
+func (c *CachedGetter) fresh(key string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := c.now()
+	val, ok := c.values.entries[key]
+	list, listOK := c.lists.entries[key]
+	return ok && listOK && now.Before(val.expires) && now.Before(list.expires)
+}
+
+// Invalidate : Drop any cache entries for key, so that the next lookup goes to the wrapped Getter. Lookups already
+// in flight return what they get, but don't cache it, and later lookups don't wait on them.
+func (c *CachedGetter) Invalidate(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.generation++
+	delete(c.values.entries, key)
+	delete(c.lists.entries, key)
+	delete(c.values.inFlight, key)
+	delete(c.lists.inFlight, key)
+}
+
+func (c *CachedGetter) ttlFor(miss bool) time.Duration {
+	if miss {
+		return c.negativeTTL
+	}
+	return c.ttl
+}
+
+// isEmptyList reports whether a GetStrings result represents an unset value.
+func isEmptyList(vals []string) bool {
+	return len(vals) == 0 || (len(vals) == 1 && vals[0] == "")
+}
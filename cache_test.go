@@ -0,0 +1,280 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type countingGetter struct {
+	*Env
+	calls int
+}
+
+func (c *countingGetter) Get(key string) string {
+	c.calls++
+	return os.Getenv(key)
+}
+
+func TestCachedTTLs(t *testing.T) {
+	defer os.Unsetenv("CONFIG_TEST_CACHED")
+	now := time.Now()
+	backend := &countingGetter{}
+	c := Cached(backend, time.Minute, time.Second).(*CachedGetter)
+	c.now = func() time.Time { return now }
+
+	if v := c.Get("CONFIG_TEST_CACHED"); v != "" {
+		t.Fatalf("Expected empty value, got '%s'", v)
+	}
+	os.Setenv("CONFIG_TEST_CACHED", "1")
+	if v := c.Get("CONFIG_TEST_CACHED"); v != "" {
+		t.Errorf("Miss should be cached within negativeTTL; got '%s'", v)
+	}
+	now = now.Add(2 * time.Second)
+	if v := c.Get("CONFIG_TEST_CACHED"); v != "1" {
+		t.Errorf("Miss should expire after negativeTTL; expected '1', got '%s'", v)
+	}
+	os.Setenv("CONFIG_TEST_CACHED", "2")
+	now = now.Add(30 * time.Second)
+	if v := c.Get("CONFIG_TEST_CACHED"); v != "1" {
+		t.Errorf("Hit should be cached within ttl; expected '1', got '%s'", v)
+	}
+	now = now.Add(time.Minute)
+	if v := c.Get("CONFIG_TEST_CACHED"); v != "2" {
+		t.Errorf("Hit should expire after ttl; expected '2', got '%s'", v)
+	}
+	if backend.calls != 3 {
+		t.Errorf("Expected 3 backend calls, got %d", backend.calls)
+	}
+	os.Setenv("CONFIG_TEST_CACHED", "3")
+	c.Invalidate("CONFIG_TEST_CACHED")
+	if v := c.Get("CONFIG_TEST_CACHED"); v != "3" {
+		t.Errorf("Invalidate should drop the entry; expected '3', got '%s'", v)
+	}
+}
+
+func TestCachedNoNegativeTTL(t *testing.T) {
+	backend := &countingGetter{}
+	c := Cached(backend, time.Minute, 0)
+	c.Get("CONFIG_TEST_CACHED_UNSET")
+	c.Get("CONFIG_TEST_CACHED_UNSET")
+	if backend.calls != 2 {
+		t.Errorf("Misses should not be cached with a zero negativeTTL; expected 2 calls, got %d", backend.calls)
+	}
+}
+
+// slowGetter is a ContextGetter that records its peak number of concurrent lookups.
+type slowGetter struct {
+	Map
+	lock     sync.Mutex
+	inFlight int
+	peak     int
+	calls    int
+}
+
+func (s *slowGetter) GetContext(ctx context.Context, key string) (string, error) {
+	s.lock.Lock()
+	s.calls++
+	s.inFlight++
+	s.peak = max(s.peak, s.inFlight)
+	s.lock.Unlock()
+	defer func() {
+		s.lock.Lock()
+		s.inFlight--
+		s.lock.Unlock()
+	}()
+	select {
+	case <-time.After(5 * time.Millisecond):
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	if key == "BROKEN" {
+		return "", errors.New("backend unavailable")
+	}
+	if val, ok := s.Map[key]; ok {
+		return val, nil
+	}
+	return "", fmt.Errorf("config: %s: %w", key, ErrKeyNotSet)
+}
+
+func (s *slowGetter) Get(key string) string {
+	s.lock.Lock()
+	s.calls++
+	s.lock.Unlock()
+	return s.Map.Get(key)
+}
+
+func TestCachedPrefetch(t *testing.T) {
+	backend := &slowGetter{Map: Map{}}
+	keys := make([]string, 0, 20)
+	for i := range 20 {
+		key := fmt.Sprintf("KEY_%d", i)
+		backend.Map[key] = fmt.Sprint(i)
+		keys = append(keys, key)
+	}
+	c := Cached(backend, time.Minute, time.Minute).(*CachedGetter)
+	if err := c.Prefetch(context.Background(), append(keys, "UNSET"), 4); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if backend.peak > 4 || backend.peak < 2 {
+		t.Errorf("Expected up to 4 concurrent fetches, got a peak of %d", backend.peak)
+	}
+	calls := backend.calls
+	if v := c.Get("KEY_7"); v != "7" {
+		t.Errorf("Expected '7', got '%s'", v)
+	}
+	c.Get("UNSET")
+	if err := c.Prefetch(context.Background(), keys, 4); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if backend.calls != calls {
+		t.Errorf("Prefetched keys should be served from the cache; %d more backend calls", backend.calls-calls)
+	}
+
+	if err := c.Prefetch(context.Background(), []string{"BROKEN", "KEY_1"}, 0); err == nil || !strings.Contains(err.Error(), "backend unavailable") {
+		t.Errorf("Expected the fetch error, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.Invalidate("KEY_2")
+	if err := c.Prefetch(ctx, []string{"KEY_2"}, 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+// blockingGetter is an ErrorGetter whose lookups of BLOCKED wait until release is closed.
+type blockingGetter struct {
+	Map
+	release chan struct{}
+	lock    sync.Mutex
+	calls   map[string]int
+	err     error
+}
+
+func (b *blockingGetter) GetErr(key string) (string, error) {
+	b.lock.Lock()
+	b.calls[key]++
+	err := b.err
+	b.lock.Unlock()
+	if key == "BLOCKED" {
+		<-b.release
+	}
+	if err != nil {
+		return "", err
+	}
+	if val, ok := b.Map[key]; ok {
+		return val, nil
+	}
+	return "", fmt.Errorf("config: %s: %w", key, ErrKeyNotSet)
+}
+
+func (b *blockingGetter) Get(key string) string {
+	val, _ := b.GetErr(key)
+	return val
+}
+
+func (b *blockingGetter) GetStrings(key string) []string {
+	b.lock.Lock()
+	b.calls["list:"+key]++
+	b.lock.Unlock()
+	return b.Map.GetStrings(key)
+}
+
+func (b *blockingGetter) count(key string) int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.calls[key]
+}
+
+func TestCachedConcurrentLookups(t *testing.T) {
+	backend := &blockingGetter{Map: Map{"BLOCKED": "slow", "OTHER": "fast"}, release: make(chan struct{}), calls: map[string]int{}}
+	c := Cached(backend, time.Minute, time.Minute)
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v := c.Get("BLOCKED"); v != "slow" {
+				t.Errorf("Expected 'slow', got '%s'", v)
+			}
+		}()
+	}
+	for backend.count("BLOCKED") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	done := make(chan string)
+	go func() { done <- c.Get("OTHER") }()
+	select {
+	case v := <-done:
+		if v != "fast" {
+			t.Errorf("Expected 'fast', got '%s'", v)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("A slow lookup of one key blocked the lookup of another")
+	}
+	close(backend.release)
+	wg.Wait()
+	if n := backend.count("BLOCKED"); n != 1 {
+		t.Errorf("Expected concurrent lookups of a key to share one call, got %d calls", n)
+	}
+}
+
+func TestCachedErrors(t *testing.T) {
+	backend := &blockingGetter{Map: Map{"HOST": "db1"}, calls: map[string]int{}, err: errors.New("backend unavailable")}
+	c := Cached(backend, time.Minute, time.Minute)
+	if _, err := GetErr(c, "HOST"); err == nil || errors.Is(err, ErrKeyNotSet) {
+		t.Errorf("Expected the backend error through GetErr, got %v", err)
+	}
+	backend.lock.Lock()
+	backend.err = nil
+	backend.lock.Unlock()
+	if v, err := GetErr(c, "HOST"); v != "db1" || err != nil {
+		t.Errorf("Backend errors should not be cached; got '%s', %v", v, err)
+	}
+	c.Get("UNSET")
+	c.Get("UNSET")
+	if n := backend.count("UNSET"); n != 1 {
+		t.Errorf("ErrKeyNotSet should be cached as a miss; %d calls", n)
+	}
+}
+
+func TestCachedPrefetchLists(t *testing.T) {
+	backend := &blockingGetter{Map: Map{"HOSTS": "a,b"}, calls: map[string]int{}}
+	c := Cached(backend, time.Minute, time.Minute).(*CachedGetter)
+	if err := c.Prefetch(context.Background(), []string{"HOSTS"}, 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := c.GetStrings("HOSTS"); len(v) != 2 {
+		t.Errorf("Expected 2 values, got %q", v)
+	}
+	if n := backend.count("list:HOSTS"); n != 1 {
+		t.Errorf("Expected GetStrings to be served from the prefetched cache; %d backend calls", n)
+	}
+}
+
+func TestCachedInvalidateDuringFetch(t *testing.T) {
+	backend := &blockingGetter{Map: Map{"BLOCKED": "old"}, release: make(chan struct{}), calls: map[string]int{}}
+	c := Cached(backend, time.Minute, time.Minute).(*CachedGetter)
+	done := make(chan error)
+	go func() { done <- c.Prefetch(context.Background(), []string{"BLOCKED"}, 1) }()
+	for backend.count("BLOCKED") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// A write lands, and is invalidated, while the prefetch is still reading the old value.
+	backend.lock.Lock()
+	backend.Map["BLOCKED"] = "new"
+	backend.lock.Unlock()
+	c.Invalidate("BLOCKED")
+	close(backend.release)
+	if err := <-done; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := c.Get("BLOCKED"); v != "new" {
+		t.Errorf("A fetch that started before Invalidate should not be cached; expected 'new', got '%s'", v)
+	}
+}
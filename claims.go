@@ -0,0 +1,13 @@
+package config
+
+// NewClaimsGetter : Return a Getter exposing the claims of a JWT as config keys, so that per-request config carried
+// in a token can be read through the usual interface (say, by a middleware that builds one from the request's token).
+// Nested objects are flattened with dots, arrays are joined for GetStrings, and scalars are stringified,
+// as described for FlattenValue.
+//
+// This does no verification of any kind: only pass it claims from a token you've already verified.
+func NewClaimsGetter(claims map[string]any) Getter {
+	rval := make(Map)
+	FlattenValue(rval, "", claims)
+	return rval
+}
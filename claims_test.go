@@ -0,0 +1,34 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestNewClaimsGetter(t *testing.T) {
+	var claims map[string]any
+	json.Unmarshal([]byte(`{
+		"sub": "user-1",
+		"exp": 1767225600,
+		"scope": ["read", "write"],
+		"tenant": {"id": "t1", "plan": {"tier": "pro"}},
+		"admin": false
+	}`), &claims)
+	c := NewClaimsGetter(claims)
+	tests := map[string]string{
+		"sub":              "user-1",
+		"exp":              "1767225600",
+		"tenant.id":        "t1",
+		"tenant.plan.tier": "pro",
+		"admin":            "false",
+	}
+	for key, expected := range tests {
+		if v := c.Get(key); v != expected {
+			t.Errorf("%s: expected '%s', got '%s'", key, expected, v)
+		}
+	}
+	if v := c.GetStrings("scope"); !reflect.DeepEqual(v, []string{"read", "write"}) {
+		t.Errorf("Expected [read write], got %q", v)
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrorCollector reads typed values from a Getter, collecting parse errors instead of returning them one at a time.
+// See NewErrorCollector.
+type ErrorCollector struct {
+	g    Getter
+	lock sync.Mutex
+	errs []error
+}
+
+// NewErrorCollector : Return an ErrorCollector over g, for binding many typed values at startup with one error check:
+//
+//	ec := config.NewErrorCollector(g)
+//	port := ec.Int("PORT")
+//	timeout := ec.Duration("TIMEOUT")
+//	if err := ec.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// A value that doesn't parse is recorded and read as the type's zero value; an unset key is the zero value, and is
+// not an error. Error messages name the key, and show the value unless it's a secret (see SetSecretPredicate).
+func NewErrorCollector(g Getter) *ErrorCollector {
+	return &ErrorCollector{g: g}
+}
+
+// Err : Return all the parse errors so far, joined into one error (see errors.Join), or nil.
+func (ec *ErrorCollector) Err() error {
+	ec.lock.Lock()
+	defer ec.lock.Unlock()
+	return errors.Join(ec.errs...)
+}
+
+// collect parses key's value, recording any error.
+func collect[T any](ec *ErrorCollector, key string, kind string, parse func(string) (T, error)) T {
+	var zero T
+	raw := ec.g.Get(key)
+	if raw == "" {
+		return zero
+	}
+	val, err := parse(raw)
+	if err != nil {
+		ec.lock.Lock()
+		ec.errs = append(ec.errs, fmt.Errorf("config: %s value %s is not a valid %s", key, displayValue(ec.g, key, raw), kind))
+		ec.lock.Unlock()
+		return zero
+	}
+	return val
+}
+
+// Int : Return key's value as an int.
+func (ec *ErrorCollector) Int(key string) int {
+	return collect(ec, key, "int", strconv.Atoi)
+}
+
+// Int64 : Return key's value as an int64.
+func (ec *ErrorCollector) Int64(key string) int64 {
+	return collect(ec, key, "int64", func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) })
+}
+
+// Uint : Return key's value as a uint.
+func (ec *ErrorCollector) Uint(key string) uint {
+	return collect(ec, key, "uint", func(s string) (uint, error) {
+		u, err := strconv.ParseUint(s, 10, 0)
+		return uint(u), err
+	})
+}
+
+// Float : Return key's value as a float64.
+func (ec *ErrorCollector) Float(key string) float64 {
+	return collect(ec, key, "float", func(s string) (float64, error) { return strconv.ParseFloat(s, 64) })
+}
+
+// Bool : Return key's value as a bool, per strconv.ParseBool.
+func (ec *ErrorCollector) Bool(key string) bool {
+	return collect(ec, key, "bool", strconv.ParseBool)
+}
+
+// Duration : Return key's value as a time.Duration, per time.ParseDuration.
+func (ec *ErrorCollector) Duration(key string) time.Duration {
+	return collect(ec, key, "duration", time.ParseDuration)
+}
+
+// String : Return key's value. Strings can't fail to parse; it's here so reads look alike.
+func (ec *ErrorCollector) String(key string) string {
+	return ec.g.Get(key)
+}
+
+// Strings : Return key's value split like GetStrings.
+func (ec *ErrorCollector) Strings(key string) []string {
+	return ec.g.GetStrings(key)
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorCollector(t *testing.T) {
+	SetSecretPredicate(func(key string) bool { return key == "API_TOKEN" })
+	defer SetSecretPredicate(nil)
+	ec := NewErrorCollector(Map{
+		"PORT":      "8080",
+		"TIMEOUT":   "soon",
+		"DEBUG":     "true",
+		"RATIO":     "x",
+		"API_TOKEN": "hunter2",
+		"HOSTS":     "a, b",
+	})
+	if v := ec.Int("PORT"); v != 8080 {
+		t.Errorf("Expected 8080, got %d", v)
+	}
+	if v := ec.Duration("TIMEOUT"); v != 0 {
+		t.Errorf("Expected the zero value for an invalid duration, got %v", v)
+	}
+	if v := ec.Bool("DEBUG"); !v {
+		t.Error("Expected true")
+	}
+	ec.Float("RATIO")
+	ec.Int64("API_TOKEN")
+	if v := ec.Uint("MISSING"); v != 0 {
+		t.Errorf("Expected 0 for an unset key, got %d", v)
+	}
+	if v := ec.Strings("HOSTS"); len(v) != 2 || ec.String("PORT") != "8080" {
+		t.Errorf("Unexpected string values %q", v)
+	}
+	err := ec.Err()
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	msg := err.Error()
+	for _, want := range []string{`TIMEOUT value "soon" is not a valid duration`, "RATIO", "API_TOKEN value *** is not a valid int64"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Expected the error to contain '%s', got '%s'", want, msg)
+		}
+	}
+	if strings.Contains(msg, "hunter2") || strings.Contains(msg, "MISSING") {
+		t.Errorf("Unexpected error contents '%s'", msg)
+	}
+	if err := NewErrorCollector(Map{"T": "1s"}); err.Duration("T") != time.Second || err.Err() != nil {
+		t.Error("Expected no errors for valid values")
+	}
+}
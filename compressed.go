@@ -0,0 +1,97 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Decompressor describes a compression format for NewCompressedFileGetter.
+type Decompressor struct {
+	// Magic is the leading bytes that identify the format.
+	Magic []byte
+	// Ext is the file extension for the format, including the dot.
+	Ext string
+	// Open wraps r with a decompressing reader.
+	Open func(r io.Reader) (io.Reader, error)
+}
+
+var (
+	decompressorsLock sync.RWMutex
+	decompressors     = map[string]Decompressor{
+		"gzip": {
+			Magic: []byte{0x1f, 0x8b},
+			Ext:   ".gz",
+			Open:  func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		},
+	}
+)
+
+// RegisterDecompressor : Add (or replace) a compression format for NewCompressedFileGetter. gzip is built in.
+func RegisterDecompressor(name string, d Decompressor) {
+	decompressorsLock.Lock()
+	defer decompressorsLock.Unlock()
+	decompressors[name] = d
+}
+
+// NewCompressedFileGetter : Read a possibly-compressed config file, decompress it, and parse it with the
+// named format (see RegisterFormat). The file is read once.
+//
+// The compression is detected by sniffing the file's leading bytes for a registered decompressor's magic
+// number. Only if that finds nothing is the extension consulted: a file named like a compressed file
+// (".gz") that doesn't start with the magic number is reported as corrupt. Files that match neither are
+// parsed as is. A corrupt or truncated archive is an error naming the file and the compression, as is one that
+// decompresses to more than 32MB.
+//
+// The Getter is a Lister, and a TimestampedGetter that reports the file's mtime (when it was read) for every key.
+func NewCompressedFileGetter(path string, format string) (Getter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	br := bufio.NewReader(f)
+	name, d, ok := sniffDecompressor(br, path)
+	var r io.Reader = br
+	if ok {
+		if r, err = d.Open(br); err != nil {
+			return nil, fmt.Errorf("config: %s: corrupt %s data: %w", path, name, err)
+		}
+	}
+	data, err := readDocument(r)
+	if err != nil {
+		return nil, fmt.Errorf("config: %s: reading %s data: %w", path, name, err)
+	}
+	values, err := ParseFormat(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	return &fileValues{Map: values, modTime: info.ModTime()}, nil
+}
+
+// sniffDecompressor picks the decompressor for the data in br (or, failing that, for path's extension).
+func sniffDecompressor(br *bufio.Reader, path string) (string, Decompressor, bool) {
+	decompressorsLock.RLock()
+	defer decompressorsLock.RUnlock()
+	for name, d := range decompressors {
+		if head, _ := br.Peek(len(d.Magic)); len(d.Magic) > 0 && bytes.Equal(head, d.Magic) {
+			return name, d, true
+		}
+	}
+	ext := filepath.Ext(path)
+	for name, d := range decompressors {
+		if d.Ext != "" && d.Ext == ext {
+			return name, d, true
+		}
+	}
+	return "", Decompressor{}, false
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func gzipped(t *testing.T, data string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(data))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestCompressedFileGetter(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"sniffed.conf":  gzipped(t, "HOST=db\n"),
+		"named.json.gz": gzipped(t, `{"HOST": "db"}`),
+		"plain.env":     []byte("HOST=db\n"),
+	}
+	formats := map[string]string{"sniffed.conf": "env", "named.json.gz": "json", "plain.env": "env"}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, data, 0600)
+		c, err := NewCompressedFileGetter(path, formats[name])
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if v := c.Get("HOST"); v != "db" {
+			t.Errorf("%s: expected 'db', got '%s'", name, v)
+		}
+		if _, ok := ModTime(c, "HOST"); !ok {
+			t.Errorf("%s: expected a mod time", name)
+		}
+	}
+}
+
+func TestCompressedFileGetterCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	truncated := gzipped(t, "HOST=db\nPORT=5432\n")
+	os.WriteFile(filepath.Join(dir, "truncated"), truncated[:len(truncated)-6], 0600)
+	os.WriteFile(filepath.Join(dir, "fake.gz"), []byte("HOST=db\n"), 0600)
+	os.WriteFile(filepath.Join(dir, "bomb.gz"), gzipped(t, strings.Repeat("#", maxDocumentSize+1)), 0600)
+	for _, name := range []string{"truncated", "fake.gz", "bomb.gz"} {
+		if _, err := NewCompressedFileGetter(filepath.Join(dir, name), "env"); err == nil {
+			t.Errorf("%s: expected a corrupt archive error", name)
+		}
+	}
+}
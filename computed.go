@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// WithComputedValues : Wrap a Getter so that values containing template syntax ("{{") are executed as text/template
+// templates, for computed values like LOG_DIR="/var/log/{{ .Hostname }}" or URL="https://{{ key "HOST" }}:8443".
+// Templates see data, plus .Hostname (unless data sets it), and these functions as well as those in funcs (which
+// can replace them):
+//
+//	env "NAME"               the NAME environment variable
+//	key "KEY"                the (computed) value of another key in g
+//	now                      the current time.Time
+//	formatTime "layout" t    t.Format("layout"), e.g. {{ formatTime "2006-01-02" now }}
+//
+// Plain values are returned without touching the template engine, so they cost no more than they would unwrapped.
+// Results are cached by value string, so a template is executed once per distinct value; the cache is cleared when
+// g reloads (if it's a Watcher), which is the point at which key results can change. Results that depend on env or
+// now are fixed until then too. A key that refers back to itself, directly or via others, is an error rather than a
+// stack overflow, as is a chain of more than 8 key lookups.
+//
+// The returned Getter is an ErrorGetter; template errors surface through GetErr, and Get returns "". GetStrings
+// splits the computed value.
+func WithComputedValues(g Getter, data map[string]any, funcs template.FuncMap) Getter {
+	merged := make(map[string]any, len(data)+1)
+	if hostname, err := os.Hostname(); err == nil {
+		merged["Hostname"] = hostname
+	}
+	for k, v := range data {
+		merged[k] = v
+	}
+	c := &computed{g: g, data: merged, funcs: funcs, cache: make(map[string]string)}
+	if w, ok := g.(Watcher); ok {
+		c.changes = w.Watch()
+	}
+	return c
+}
+
+type computed struct {
+	g       Getter
+	data    map[string]any
+	funcs   template.FuncMap
+	changes <-chan struct{}
+	lock    sync.Mutex
+	cache   map[string]string
+}
+
+// cached returns the cached result for val, first dropping the cache if g has reloaded since it was filled.
+func (c *computed) cached(val string) (string, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	select {
+	case <-c.changes:
+		clear(c.cache)
+	default:
+	}
+	rval, ok := c.cache[val]
+	return rval, ok
+}
+
+// compute returns key's computed value; visiting holds the keys being computed further up the stack.
+func (c *computed) compute(key string, visiting []string) (string, error) {
+	val, err := GetErr(c.g, key)
+	if err != nil || !strings.Contains(val, "{{") {
+		return val, err
+	}
+	if rval, ok := c.cached(val); ok {
+		return rval, nil
+	}
+	for _, v := range visiting {
+		if v == key {
+			return "", fmt.Errorf("config: computing %s: %w", strings.Join(append(visiting, key), " -> "), errResolveCycle)
+		}
+	}
+	if len(visiting) >= maxResolveDepth {
+		return "", fmt.Errorf("config: computing %s: %w", key, errResolveCycle)
+	}
+	visiting = append(visiting, key)
+	funcs := template.FuncMap{
+		"env": os.Getenv,
+		"key": func(other string) (string, error) {
+			return c.compute(other, visiting)
+		},
+		"now": time.Now,
+		"formatTime": func(layout string, t time.Time) string {
+			return t.Format(layout)
+		},
+	}
+	for name, f := range c.funcs {
+		funcs[name] = f
+	}
+	tmpl, err := template.New(key).Funcs(funcs).Option("missingkey=error").Parse(val)
+	if err != nil {
+		return "", fmt.Errorf("config: computing %s: %w", key, err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, c.data); err != nil {
+		return "", fmt.Errorf("config: computing %s: %w", key, err)
+	}
+	c.lock.Lock()
+	c.cache[val] = b.String()
+	c.lock.Unlock()
+	return b.String(), nil
+}
+
+func (c *computed) GetErr(key string) (string, error) {
+	return c.compute(key, nil)
+}
+
+func (c *computed) Get(key string) string {
+	val, _ := c.GetErr(key)
+	return val
+}
+
+func (c *computed) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(c, key, dflt)
+}
+
+func (c *computed) GetStrings(key string) []string {
+	return SplitStrings(c.Get(key))
+}
+
+func (c *computed) MustGet(key string) string {
+	return MustGet(c, key)
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestWithComputedValues(t *testing.T) {
+	os.Setenv("CONFIG_TEST_COMPUTED_ZONE", "eu-1")
+	defer os.Unsetenv("CONFIG_TEST_COMPUTED_ZONE")
+	hostname, _ := os.Hostname()
+	m := NewMutableGetter(Map{
+		"HOST":    "db1",
+		"URL":     `https://{{ key "HOST" }}:{{ .Port }}`,
+		"LOG_DIR": "/var/log/{{ .Hostname }}/{{ env \"CONFIG_TEST_COMPUTED_ZONE\" }}",
+		"SHOUT":   `{{ upper (key "HOST") }}`,
+		"DAY":     `{{ formatTime "2006" now }}`,
+		"LOOP_A":  `{{ key "LOOP_B" }}`,
+		"LOOP_B":  `{{ key "LOOP_A" }}`,
+		"BROKEN":  "{{ .Port",
+		"PLAIN":   "a, b",
+	})
+	g := WithComputedValues(m, map[string]any{"Port": 5432}, template.FuncMap{"upper": strings.ToUpper})
+
+	if v := g.Get("URL"); v != "https://db1:5432" {
+		t.Errorf("Expected 'https://db1:5432', got '%s'", v)
+	}
+	if v := g.Get("LOG_DIR"); v != "/var/log/"+hostname+"/eu-1" {
+		t.Errorf("Unexpected LOG_DIR '%s'", v)
+	}
+	if v := g.Get("SHOUT"); v != "DB1" {
+		t.Errorf("Expected a custom func to apply, got '%s'", v)
+	}
+	if v := g.Get("DAY"); len(v) != 4 {
+		t.Errorf("Expected a four-digit year, got '%s'", v)
+	}
+	if v := g.GetStrings("PLAIN"); len(v) != 2 {
+		t.Errorf("Expected plain values to pass through, got %q", v)
+	}
+	if _, err := GetErr(g, "LOOP_A"); !errors.Is(err, errResolveCycle) {
+		t.Errorf("Expected a cycle error, got %v", err)
+	}
+	if _, err := GetErr(g, "BROKEN"); err == nil {
+		t.Error("Expected a template error")
+	}
+
+	m.Set("HOST", "db2")
+	if v := g.Get("URL"); v != "https://db2:5432" {
+		t.Errorf("Expected the cache to be cleared on reload, got '%s'", v)
+	}
+}
@@ -11,12 +11,16 @@ package config
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
 	"strings"
 	"sync"
 )
 
+// ErrKeyNotSet is returned (possibly wrapped) by error-returning lookups when a required key has no value.
+var ErrKeyNotSet = errors.New("config: key not set")
+
 var (
 	loader      Loader
 	loadLock    sync.Mutex
@@ -31,10 +35,59 @@ type Getter interface {
 	MustGet(string) string
 }
 
+// Lister is implemented by Getters that can enumerate the keys they hold.
+type Lister interface {
+	Keys() []string
+}
+
+// Looker is implemented by Getters that can tell an unset key apart from a key set to "".
+type Looker interface {
+	Lookup(string) (string, bool)
+}
+
+// Lookup : Return the value of key and whether it is present. If g isn't a Looker, a key is considered present
+// when its value is non-empty.
+func Lookup(g Getter, key string) (string, bool) {
+	if l, ok := g.(Looker); ok {
+		return l.Lookup(key)
+	}
+	v := g.Get(key)
+	return v, v != ""
+}
+
+// ErrorGetter is implemented by Getters whose lookups can fail, e.g. when the value lives in a remote backend
+// or has to be resolved. GetErr returns the value for the key, or the reason it couldn't be retrieved;
+// the plain Get() of such a Getter returns "" on failure.
+type ErrorGetter interface {
+	GetErr(string) (string, error)
+}
+
+// GetErr : Return g.GetErr(key) if g is an ErrorGetter, otherwise g.Get(key) with a nil error.
+func GetErr(g Getter, key string) (string, error) {
+	if eg, ok := g.(ErrorGetter); ok {
+		return eg.GetErr(key)
+	}
+	return g.Get(key), nil
+}
+
+// ContextGetter is implemented by Getters whose lookups take a Context, usually because they go to a remote backend
+// (which should honor deadlines and cancellation, and can be traced) or because the value depends on the request.
+type ContextGetter interface {
+	GetContext(context.Context, string) (string, error)
+}
+
+// GetContext : Return g.GetContext(ctx, key) if g is a ContextGetter, otherwise GetErr(g, key).
+func GetContext(ctx context.Context, g Getter, key string) (string, error) {
+	if cg, ok := g.(ContextGetter); ok {
+		return cg.GetContext(ctx, key)
+	}
+	return GetErr(g, key)
+}
+
 // Loader is a callback function that used to delegate configuration loading.
 // Loader is expected to return a Getter that will be used by consumers to access configuration
 // values.
-// If the Loader copies configuration values into the environment, it can 
+// If the Loader copies configuration values into the environment, it can
 // return a standard environment getter using Environment(). See the SetLoader() example.
 //
 // The Context argument is defined to provide a hook for per-request mutated configs (which
@@ -85,12 +138,29 @@ func Environment() Getter {
 	return &Env{}
 }
 
-// Get : Equivalent to os.Getenv(key). Note that other Get-ish methods in Env 
+// Get : Equivalent to os.Getenv(key). Note that other Get-ish methods in Env
 // call Env.Get() (and not os.Getenv)
 func (e *Env) Get(key string) string {
 	return os.Getenv(key)
 }
 
+// Lookup : Equivalent to os.LookupEnv(key).
+func (e *Env) Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// Keys : Return the names of all the variables in the environment.
+func (e *Env) Keys() []string {
+	env := os.Environ()
+	rval := make([]string, 0, len(env))
+	for _, kv := range env {
+		if key, _, _ := strings.Cut(kv, "="); key != "" {
+			rval = append(rval, key)
+		}
+	}
+	return rval
+}
+
 // GetOrDefault : If the requested key is not present or empty, return the dflt.
 func (e *Env) GetOrDefault(key string, dflt string) string {
 	rval := e.Get(key)
@@ -102,11 +172,7 @@ func (e *Env) GetOrDefault(key string, dflt string) string {
 
 // GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
 func (e *Env) GetStrings(key string) []string {
-	rval := strings.Split(e.Get(key), ",")
-	for i, val := range rval {
-		rval[i] = strings.TrimSpace(val)
-	}
-	return rval
+	return SplitStrings(e.Get(key))
 }
 
 // MustGet will panic if the key is not present or empty. Use this only when you really must get.
@@ -117,3 +183,32 @@ func (e *Env) MustGet(key string) string {
 	}
 	return v
 }
+
+// SplitStrings : The GetStrings splitting rule (split on commas, trim whitespace around each element), exported for
+// Getter implementations outside this package.
+func SplitStrings(raw string) []string {
+	rval, _ := ParseStringsDetailed(raw, ParseOptions{TrimSpace: true})
+	return rval
+}
+
+// GetOrDefault : The GetOrDefault rule (g.Get(key), or dflt if that's empty), for Getters that wrap some other Get(),
+// in this package or outside it.
+func GetOrDefault(g Getter, key string, dflt string) string {
+	if rval := g.Get(key); rval != "" {
+		return rval
+	}
+	return dflt
+}
+
+// MustGet : The MustGet rule, for Getters that wrap some other Get(): panic if the value can't be read (see GetErr)
+// or is empty.
+func MustGet(g Getter, key string) string {
+	v, err := GetErr(g, key)
+	if err != nil {
+		log.Panic(err)
+	}
+	if v == "" {
+		log.Panicf("%s config value not set.", key)
+	}
+	return v
+}
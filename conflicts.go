@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+)
+
+// DetectConflicts : Report the keys that getters set to different values, with the competing values, for catching
+// misconfiguration such as a file and the environment both setting a port. It's a diagnostic to run at startup or
+// during a deploy, and doesn't change how any value is resolved; Merge and friends still pick one value by their
+// precedence rules.
+//
+// Every getter must be a Lister, since conflicts can only be found among keys that can be enumerated; otherwise
+// DetectConflicts returns an error. A key is compared only among the getters where it's present (see Lookup), so a
+// key set in just one of them is never a conflict. The competing values are listed once each, in the order of the
+// getters that first set them. Values of secret keys (see SetSecretPredicate and SecretMarker) are listed as Redacted,
+// so the result is safe to log. An empty map means no conflicts.
+func DetectConflicts(getters ...Getter) (conflicts map[string][]string, err error) {
+	for i, g := range getters {
+		if _, ok := g.(Lister); !ok {
+			return nil, fmt.Errorf("config: getter %d (%T) is not a Lister", i, g)
+		}
+	}
+	conflicts = make(map[string][]string)
+	for _, key := range unionKeys(getters...) {
+		secret := isSecretKey(key)
+		seen := make(map[string]bool)
+		var vals []string
+		for _, g := range getters {
+			val, ok := Lookup(g, key)
+			if !ok {
+				continue
+			}
+			secret = secret || IsSecret(g, key)
+			if !seen[val] {
+				seen[val] = true
+				vals = append(vals, val)
+			}
+		}
+		if len(vals) < 2 {
+			continue
+		}
+		if secret {
+			for i := range vals {
+				vals[i] = Redacted
+			}
+		}
+		conflicts[key] = vals
+	}
+	return conflicts, nil
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectConflicts(t *testing.T) {
+	SetSecretPredicate(func(key string) bool { return key == "DB_PASSWORD" })
+	defer SetSecretPredicate(nil)
+	file := Map{"PORT": "8080", "HOST": "localhost", "DB_PASSWORD": "a", "REGION": "us-east", "EMPTY": ""}
+	env := Map{"PORT": "9090", "HOST": "localhost", "DB_PASSWORD": "b"}
+	flags := Map{"PORT": "8080", "REGION": "us-east", "EMPTY": "x"}
+	conflicts, err := DetectConflicts(file, env, flags)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string][]string{
+		"PORT":        {"8080", "9090"},
+		"DB_PASSWORD": {Redacted, Redacted},
+		"EMPTY":       {"", "x"},
+	}
+	if !reflect.DeepEqual(conflicts, expected) {
+		t.Errorf("Expected %q, got %q", expected, conflicts)
+	}
+
+	if conflicts, err := DetectConflicts(file, Map{"PORT": "8080"}); err != nil || len(conflicts) != 0 {
+		t.Errorf("Expected no conflicts, got %q (%v)", conflicts, err)
+	}
+	if _, err := DetectConflicts(file, struct{ Getter }{env}); err == nil {
+		t.Error("Expected an error for a getter that isn't a Lister")
+	}
+}
@@ -0,0 +1,34 @@
+package config
+
+// Snapshotter is implemented by Getters whose values can change underneath readers (live-updated getters) but
+// can hand out a point-in-time view. Snapshot returns a Getter that won't change.
+type Snapshotter interface {
+	Snapshot() Getter
+}
+
+// Consistent : Wrap a Getter to add GetBatch, for reading several related keys (host, port, credentials) without
+// a torn read, where some values come from before a live update and some from after.
+//
+// If g is a Snapshotter, GetBatch reads all the keys from one snapshot. Otherwise it falls back to best-effort
+// per-key reads, which are only as consistent as g is. The Getter accessors pass through to g.
+func Consistent(g Getter) *ConsistentGetter {
+	return &ConsistentGetter{Getter: g}
+}
+
+// ConsistentGetter is the Getter returned by Consistent().
+type ConsistentGetter struct {
+	Getter
+}
+
+// GetBatch : Return the values of keys, from a single snapshot where g supports it.
+func (c *ConsistentGetter) GetBatch(keys ...string) map[string]string {
+	g := c.Getter
+	if s, ok := g.(Snapshotter); ok {
+		g = s.Snapshot()
+	}
+	rval := make(map[string]string, len(keys))
+	for _, key := range keys {
+		rval[key] = g.Get(key)
+	}
+	return rval
+}
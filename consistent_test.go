@@ -0,0 +1,47 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+// flipping changes its values after every read, unless read through a snapshot.
+type flipping struct {
+	generation int
+}
+
+func (f *flipping) values() Map {
+	if f.generation%2 == 0 {
+		return Map{"HOST": "old-host", "PORT": "1"}
+	}
+	return Map{"HOST": "new-host", "PORT": "2"}
+}
+
+func (f *flipping) Get(key string) string {
+	v := f.values().Get(key)
+	f.generation++
+	return v
+}
+
+func (f *flipping) GetOrDefault(key string, dflt string) string { return GetOrDefault(f, key, dflt) }
+func (f *flipping) GetStrings(key string) []string              { return SplitStrings(f.Get(key)) }
+func (f *flipping) MustGet(key string) string                   { return MustGet(f, key) }
+
+type snapshotFlipping struct {
+	flipping
+}
+
+func (s *snapshotFlipping) Snapshot() Getter {
+	return s.values()
+}
+
+func TestConsistentGetBatch(t *testing.T) {
+	batch := Consistent(&snapshotFlipping{}).GetBatch("HOST", "PORT")
+	if expected := map[string]string{"HOST": "old-host", "PORT": "1"}; !reflect.DeepEqual(batch, expected) {
+		t.Errorf("Expected a consistent snapshot %v, got %v", expected, batch)
+	}
+	torn := Consistent(&flipping{}).GetBatch("HOST", "PORT")
+	if expected := map[string]string{"HOST": "old-host", "PORT": "2"}; !reflect.DeepEqual(torn, expected) {
+		t.Errorf("Expected best-effort per-key reads %v, got %v", expected, torn)
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+)
+
+// WithConstraints : Wrap a Getter with guardrails against oversized or malformed values: a value longer than
+// maxLen bytes, or not matching pattern, is rejected. This protects against memory blowup from a maliciously
+// large variable and enforces expected value shapes. A zero maxLen or nil pattern disables that check. Note that
+// pattern is matched with MatchString, so anchor it (^...$) to constrain the whole value.
+//
+// Get (and the accessors based on it) returns "" for a rejected value and logs the key and the reason, but never
+// the value. The returned Getter is an ErrorGetter, and GetErr returns the rejection as an error.
+// GetStrings applies the checks to each element, dropping the ones that fail.
+func WithConstraints(g Getter, maxLen int, pattern *regexp.Regexp) Getter {
+	return &constrained{g: g, maxLen: maxLen, pattern: pattern}
+}
+
+type constrained struct {
+	g       Getter
+	maxLen  int
+	pattern *regexp.Regexp
+}
+
+func (c *constrained) check(key string, val string) error {
+	if c.maxLen > 0 && len(val) > c.maxLen {
+		return fmt.Errorf("config: %s value is %d bytes, longer than the limit of %d", key, len(val), c.maxLen)
+	}
+	if c.pattern != nil && val != "" && !c.pattern.MatchString(val) {
+		return fmt.Errorf("config: %s value does not match %s", key, c.pattern)
+	}
+	return nil
+}
+
+func (c *constrained) GetErr(key string) (string, error) {
+	val, err := GetErr(c.g, key)
+	if err != nil {
+		return "", err
+	}
+	if err := c.check(key, val); err != nil {
+		return "", err
+	}
+	return val, nil
+}
+
+func (c *constrained) Get(key string) string {
+	val, err := c.GetErr(key)
+	if err != nil {
+		log.Print(err)
+	}
+	return val
+}
+
+func (c *constrained) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(c, key, dflt)
+}
+
+func (c *constrained) GetStrings(key string) []string {
+	vals := c.g.GetStrings(key)
+	rval := make([]string, 0, len(vals))
+	for _, val := range vals {
+		if err := c.check(key, val); err != nil {
+			log.Print(err)
+			continue
+		}
+		rval = append(rval, val)
+	}
+	return rval
+}
+
+func (c *constrained) MustGet(key string) string {
+	return MustGet(c, key)
+}
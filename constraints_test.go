@@ -0,0 +1,50 @@
+package config
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestWithConstraints(t *testing.T) {
+	c := WithConstraints(Map{
+		"PORT":  "8080",
+		"BAD":   "80; rm -rf /",
+		"HUGE":  strings.Repeat("9", 100),
+		"PORTS": "80, 443,http, 8080",
+	}, 16, regexp.MustCompile(`^[0-9]+$`))
+	if v := c.Get("PORT"); v != "8080" {
+		t.Errorf("Expected '8080', got '%s'", v)
+	}
+	for _, key := range []string{"BAD", "HUGE"} {
+		if v := c.Get(key); v != "" {
+			t.Errorf("Key %s: expected rejected value to be empty, got '%s'", key, v)
+		}
+		if _, err := GetErr(c, key); err == nil {
+			t.Errorf("Key %s: expected an error", key)
+		}
+	}
+	if _, err := GetErr(c, "HUGE"); strings.Contains(err.Error(), "999") {
+		t.Errorf("Errors must not include the value: %v", err)
+	}
+	if v := c.GetStrings("PORTS"); !reflect.DeepEqual(v, []string{"80", "443", "8080"}) {
+		t.Errorf("Expected [80 443 8080], got %q", v)
+	}
+}
+
+func TestWithConstraintsDisabled(t *testing.T) {
+	c := WithConstraints(Map{"ANY": strings.Repeat("x", 1000)}, 0, nil)
+	if v := c.Get("ANY"); len(v) != 1000 {
+		t.Errorf("Expected checks to be disabled, got a %d byte value", len(v))
+	}
+}
+
+func TestWithConstraintsDoesNotMutate(t *testing.T) {
+	vals := []string{"bad", "80"}
+	c := WithConstraints(sharedList{Map: Map{}, vals: vals}, 0, regexp.MustCompile(`^[0-9]+$`))
+	c.GetStrings("PORTS")
+	if !reflect.DeepEqual(vals, []string{"bad", "80"}) {
+		t.Errorf("The backend's slice should be left alone, got %q", vals)
+	}
+}
@@ -0,0 +1,218 @@
+// Package consulconfig provides a config.Getter over the keys under a prefix in Consul's KV store, with consistent
+// multi-key reads for config that's updated several keys at a time. It keeps the Consul client dependency out of
+// the root package.
+package consulconfig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/efixler/config"
+)
+
+// waitTime is how long each blocking query waits for a change before Consul answers with the same index.
+const waitTime = 5 * time.Minute
+
+// retryDelay is how long the Getter waits before querying again after a failure.
+const retryDelay = time.Second
+
+type snapshot struct {
+	values config.Map
+	index  uint64
+}
+
+// Getter serves the keys under a prefix from an in-memory cache that Consul blocking queries keep current.
+// See NewConsulGetter.
+type Getter struct {
+	config.Notifier
+	config.ErrorReporter
+	kv        *api.KV
+	txn       *api.Txn
+	prefix    string
+	current   atomic.Pointer[snapshot]
+	err       atomic.Pointer[error]
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewConsulGetter : Load the keys under prefix and return a Getter where Get(key) is the value of prefix+key.
+// Folder entries (keys ending in "/") are skipped. A blocking query on the prefix keeps the values current, notifying
+// Watch() channels after each change. When a query fails the error goes to the Errors() channel and is returned from
+// GetErr until a query succeeds; the previous values stay in place. Call Close when you're done with the Getter.
+//
+// Get and the other lookups read the cache, so they're eventually consistent: a reader can see a multi-key update
+// only after the next query returns, and keys written by separate requests can arrive in separate queries. Use
+// GetConsistent to read related keys together.
+func NewConsulGetter(client *api.Client, prefix string) (*Getter, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &Getter{
+		kv:     client.KV(),
+		txn:    client.Txn(),
+		prefix: prefix,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	if _, err := g.refresh(ctx, 0); err != nil {
+		cancel()
+		return nil, err
+	}
+	go g.run(ctx)
+	return g, nil
+}
+
+func (g *Getter) run(ctx context.Context) {
+	defer close(g.done)
+	for {
+		changed, err := g.refresh(ctx, waitTime)
+		if ctx.Err() != nil {
+			return
+		} else if err != nil {
+			g.Report(err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryDelay):
+			}
+		} else if changed {
+			g.Notify()
+		}
+	}
+}
+
+// refresh lists the prefix, blocking for up to wait for it to change, and reports whether it had.
+func (g *Getter) refresh(ctx context.Context, wait time.Duration) (bool, error) {
+	q := &api.QueryOptions{WaitTime: wait}
+	prev := g.current.Load()
+	if prev != nil && wait > 0 {
+		q.WaitIndex = prev.index
+	}
+	pairs, meta, err := g.kv.List(g.prefix, q.WithContext(ctx))
+	if err != nil {
+		return false, g.fail(fmt.Errorf("consulconfig: reading %s: %w", g.prefix, err))
+	}
+	g.err.Store(nil)
+	// Consul indexes can go backwards (after a snapshot restore, say); start blocking again from scratch.
+	index := meta.LastIndex
+	if prev != nil && index < prev.index {
+		index = 0
+	}
+	if prev != nil && meta.LastIndex == prev.index {
+		return false, nil
+	}
+	values := make(config.Map, len(pairs))
+	for _, pair := range pairs {
+		if !strings.HasSuffix(pair.Key, "/") {
+			values[strings.TrimPrefix(pair.Key, g.prefix)] = string(pair.Value)
+		}
+	}
+	g.current.Store(&snapshot{values: values, index: index})
+	return true, nil
+}
+
+// GetConsistent : Read keys from Consul together, returning the values as of a single Raft index, so that a
+// multi-key update is seen either entirely or not at all. The reads are one Consul KV transaction, which Consul
+// applies atomically, made in consistent mode so the leader confirms it's current before answering. Keys that
+// aren't set are left out of the map. GetConsistent goes to Consul on every call, bypassing the cache, and is
+// subject to Consul's limit on operations per transaction; the error is Consul's, wrapped.
+func (g *Getter) GetConsistent(keys ...string) (map[string]string, error) {
+	ops := make(api.TxnOps, len(keys))
+	for i, key := range keys {
+		ops[i] = &api.TxnOp{KV: &api.KVTxnOp{Verb: api.KVGetOrEmpty, Key: g.prefix + key}}
+	}
+	ok, resp, _, err := g.txn.Txn(ops, &api.QueryOptions{RequireConsistent: true})
+	if err != nil {
+		return nil, fmt.Errorf("consulconfig: reading %s: %w", strings.Join(keys, ", "), err)
+	} else if !ok {
+		what := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			what[i] = e.What
+		}
+		return nil, fmt.Errorf("consulconfig: reading %s: %s", strings.Join(keys, ", "), strings.Join(what, "; "))
+	}
+	rval := make(map[string]string, len(keys))
+	for i, result := range resp.Results {
+		// get-or-empty answers a missing key with an empty entry that was never created.
+		if result.KV != nil && result.KV.CreateIndex != 0 {
+			rval[keys[i]] = string(result.KV.Value)
+		}
+	}
+	return rval, nil
+}
+
+func (g *Getter) fail(err error) error {
+	g.err.Store(&err)
+	return err
+}
+
+// Close : Stop querying Consul, abandoning any query in flight. The Getter keeps serving the values it has.
+func (g *Getter) Close() error {
+	g.closeOnce.Do(func() {
+		g.cancel()
+		<-g.done
+	})
+	return nil
+}
+
+// Index : Return the Consul index the cached values are current as of.
+func (g *Getter) Index() uint64 {
+	return g.current.Load().index
+}
+
+func (g *Getter) values() config.Map {
+	return g.current.Load().values
+}
+
+// GetErr : Return the value for key, the error from the latest query if it failed, or an error wrapping
+// config.ErrKeyNotSet.
+func (g *Getter) GetErr(key string) (string, error) {
+	if err := g.err.Load(); err != nil {
+		return "", *err
+	}
+	val, ok := g.values()[key]
+	if !ok {
+		return "", fmt.Errorf("consulconfig: %s: %w", key, config.ErrKeyNotSet)
+	}
+	return val, nil
+}
+
+// Lookup : Return the cached value for key, and whether it's set.
+func (g *Getter) Lookup(key string) (string, bool) {
+	return g.values().Lookup(key)
+}
+
+// Keys : Return the cached keys.
+func (g *Getter) Keys() []string {
+	return g.values().Keys()
+}
+
+// Snapshot : Return the values from the latest query, which won't change as later queries return.
+func (g *Getter) Snapshot() config.Getter {
+	return g.values()
+}
+
+// Get : Return the cached value for key.
+func (g *Getter) Get(key string) string {
+	return g.values().Get(key)
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (g *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(g, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (g *Getter) GetStrings(key string) []string {
+	return config.SplitStrings(g.Get(key))
+}
+
+// MustGet will panic if the key is not present or empty.
+func (g *Getter) MustGet(key string) string {
+	return config.MustGet(g, key)
+}
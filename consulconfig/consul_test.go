@@ -0,0 +1,204 @@
+package consulconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/efixler/config"
+)
+
+// fakeConsul serves the KV list and transaction endpoints over an in-memory store, answering blocking queries
+// when the store's index moves past the one asked for.
+type fakeConsul struct {
+	lock    sync.Mutex
+	values  map[string]string
+	index   uint64
+	changed chan struct{}
+	fail    bool
+	txns    []string
+}
+
+func newFakeConsul(t *testing.T, values map[string]string) (*fakeConsul, *api.Client) {
+	f := &fakeConsul{values: values, index: 10, changed: make(chan struct{})}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	client, err := api.NewClient(&api.Config{Address: strings.TrimPrefix(srv.URL, "http://")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, client
+}
+
+func (f *fakeConsul) put(values map[string]string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for k, v := range values {
+		f.values[k] = v
+	}
+	f.index++
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/v1/txn":
+		f.serveTxn(w, r)
+	case strings.HasPrefix(r.URL.Path, "/v1/kv/"):
+		f.serveList(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeConsul) serveList(w http.ResponseWriter, r *http.Request) {
+	wait, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+	f.lock.Lock()
+	for wait != 0 && f.index == wait && !f.fail {
+		changed := f.changed
+		f.lock.Unlock()
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+		f.lock.Lock()
+	}
+	defer f.lock.Unlock()
+	if f.fail {
+		http.Error(w, "No cluster leader", http.StatusInternalServerError)
+		return
+	}
+	prefix := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	pairs := api.KVPairs{{Key: prefix}} // the folder entry
+	for k, v := range f.values {
+		if strings.HasPrefix(k, prefix) {
+			pairs = append(pairs, &api.KVPair{Key: k, Value: []byte(v)})
+		}
+	}
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(f.index, 10))
+	json.NewEncoder(w).Encode(pairs)
+}
+
+func (f *fakeConsul) serveTxn(w http.ResponseWriter, r *http.Request) {
+	var ops []struct{ KV api.KVTxnOp }
+	if err := json.NewDecoder(r.Body).Decode(&ops); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.txns = append(f.txns, r.URL.RawQuery)
+	var resp api.TxnResponse
+	for i, op := range ops {
+		if op.KV.Verb != api.KVGetOrEmpty {
+			resp.Errors = append(resp.Errors, &api.TxnError{OpIndex: i, What: fmt.Sprintf("unexpected verb %s", op.KV.Verb)})
+			continue
+		}
+		kv := &api.KVPair{Key: op.KV.Key}
+		if v, ok := f.values[op.KV.Key]; ok {
+			kv.Value, kv.CreateIndex, kv.ModifyIndex = []byte(v), f.index, f.index
+		}
+		resp.Results = append(resp.Results, &api.TxnResult{KV: kv})
+	}
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(f.index, 10))
+	if len(resp.Errors) > 0 {
+		w.WriteHeader(http.StatusConflict)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func waitFor(t *testing.T, changes <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a change notification")
+	}
+}
+
+func TestConsulGetter(t *testing.T) {
+	f, client := newFakeConsul(t, map[string]string{"app/db.host": "db1", "app/db.port": "5432", "other/key": "x"})
+	g, err := NewConsulGetter(client, "app/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	if v := g.Get("db.host"); v != "db1" {
+		t.Errorf("Expected 'db1', got '%s'", v)
+	}
+	if keys := g.Keys(); len(keys) != 2 {
+		t.Errorf("Expected the two keys under the prefix and no folder, got %q", keys)
+	}
+	if i := g.Index(); i != 10 {
+		t.Errorf("Expected index 10, got %d", i)
+	}
+
+	changes := g.Watch()
+	f.put(map[string]string{"app/db.host": "db2"})
+	waitFor(t, changes)
+	if v := g.Get("db.host"); v != "db2" {
+		t.Errorf("Expected 'db2', got '%s'", v)
+	}
+
+	f.lock.Lock()
+	f.fail = true
+	close(f.changed)
+	f.changed = make(chan struct{})
+	f.lock.Unlock()
+	if err := <-g.Errors(); err == nil {
+		t.Error("Expected the failed query to be reported")
+	}
+	if _, err := g.GetErr("db.host"); err == nil {
+		t.Error("Expected GetErr to return the query error")
+	}
+	if v := g.Get("db.host"); v != "db2" {
+		t.Errorf("Expected the previous values to stay, got '%s'", v)
+	}
+	f.lock.Lock()
+	f.fail = false
+	f.lock.Unlock()
+	f.put(map[string]string{"app/db.port": "6432"})
+	waitFor(t, changes)
+	if v, err := g.GetErr("db.port"); err != nil || v != "6432" {
+		t.Errorf("Expected '6432' once queries succeed again, got %q, %v", v, err)
+	}
+	if _, err := g.GetErr("db.missing"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+}
+
+func TestConsulGetConsistent(t *testing.T) {
+	f, client := newFakeConsul(t, map[string]string{"app/db.host": "db1", "app/db.port": "5432", "app/empty": ""})
+	g, err := NewConsulGetter(client, "app/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	values, err := g.GetConsistent("db.host", "db.port", "empty", "db.missing")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{"db.host": "db1", "db.port": "5432", "empty": ""}
+	if len(values) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+	for k, v := range expected {
+		if got, ok := values[k]; !ok || got != v {
+			t.Errorf("%s: expected %q, got %q (%v)", k, v, got, ok)
+		}
+	}
+	if len(f.txns) != 1 || !strings.Contains(f.txns[0], "consistent") {
+		t.Errorf("Expected a single consistent transaction, got %q", f.txns)
+	}
+}
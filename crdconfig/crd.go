@@ -0,0 +1,209 @@
+// Package crdconfig provides a config.Getter over the spec of a Kubernetes custom resource, for operators and apps
+// that model their config as a CR. It keeps the client-go dependency out of the root package.
+package crdconfig
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/efixler/config"
+)
+
+// syncTimeout bounds how long NewCRDGetter waits for the informer's initial list.
+const syncTimeout = 30 * time.Second
+
+// Getter serves the flattened spec of a custom resource, kept current by an informer. See NewCRDGetter.
+type Getter struct {
+	config.Notifier
+	config.ErrorReporter
+	gvr       schema.GroupVersionResource
+	namespace string
+	name      string
+	values    atomic.Pointer[config.Map]
+	err       atomic.Pointer[error]
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewCRDGetter : Return a Getter over the spec of the custom resource name in namespace, of resource type gvr
+// (e.g. {Group: "example.com", Version: "v1", Resource: "appconfigs"}). Spec fields are keyed by their path with
+// dots, as with the JSON format: spec.db.host is "db.host", and lists of scalars are comma-joined for GetStrings.
+//
+// An informer watches the resource, and Watch() channels are notified whenever its spec changes. NewCRDGetter
+// returns once the informer has listed the resource; an API error (forbidden, an unknown resource type) or a
+// resource that doesn't exist is returned. After that, list and watch failures go to the Errors() channel and are
+// returned by GetErr until the informer recovers, while Get keeps serving the last spec seen. If the resource is
+// deleted its values are dropped, and GetErr returns a NotFound error. Call Close to stop the informer.
+func NewCRDGetter(client dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string) (*Getter, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &Getter{
+		gvr:       gvr,
+		namespace: namespace,
+		name:      name,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	empty := config.Map{}
+	g.values.Store(&empty)
+	informer := dynamicinformer.NewFilteredDynamicInformer(client, gvr, namespace, 0, cache.Indexers{},
+		func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}).Informer()
+	if err := informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		err = fmt.Errorf("crdconfig: watching %s: %w", g.describe(), err)
+		g.err.Store(&err)
+		g.Report(err)
+	}); err != nil {
+		cancel()
+		return nil, fmt.Errorf("crdconfig: %w", err)
+	}
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    g.update,
+		UpdateFunc: func(_, obj any) { g.update(obj) },
+		DeleteFunc: g.delete,
+	}); err != nil {
+		cancel()
+		return nil, fmt.Errorf("crdconfig: %w", err)
+	}
+	go func() {
+		defer close(g.done)
+		informer.Run(ctx.Done())
+	}()
+
+	deadline := time.After(syncTimeout)
+	for !informer.HasSynced() {
+		if err := g.err.Load(); err != nil {
+			g.Close()
+			return nil, *err
+		}
+		select {
+		case <-deadline:
+			g.Close()
+			return nil, fmt.Errorf("crdconfig: timed out listing %s", g.describe())
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if err := g.err.Load(); err != nil {
+		g.Close()
+		return nil, *err
+	}
+	storeKey := name
+	if namespace != "" {
+		storeKey = namespace + "/" + name
+	}
+	if _, exists, _ := informer.GetStore().GetByKey(storeKey); !exists {
+		g.Close()
+		return nil, fmt.Errorf("crdconfig: %w", apierrors.NewNotFound(gvr.GroupResource(), name))
+	}
+	return g, nil
+}
+
+func (g *Getter) describe() string {
+	return fmt.Sprintf("%s %s/%s", g.gvr.GroupResource(), g.namespace, g.name)
+}
+
+// update loads the spec of obj. It's also called for each relist, so it clears any error from before one.
+func (g *Getter) update(obj any) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok || u.GetName() != g.name {
+		return
+	}
+	values := make(config.Map)
+	if spec, ok := u.Object["spec"].(map[string]any); ok {
+		config.FlattenValue(values, "", spec)
+	}
+	old := g.values.Swap(&values)
+	g.err.Store(nil)
+	if !maps.Equal(*old, values) {
+		g.Notify()
+	}
+}
+
+func (g *Getter) delete(obj any) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if u, ok := obj.(*unstructured.Unstructured); !ok || u.GetName() != g.name {
+		return
+	}
+	empty := config.Map{}
+	g.values.Store(&empty)
+	var err error = apierrors.NewNotFound(g.gvr.GroupResource(), g.name)
+	g.err.Store(&err)
+	g.Notify()
+}
+
+// Close : Stop the informer. The Getter keeps serving the values it has.
+func (g *Getter) Close() error {
+	g.closeOnce.Do(func() {
+		g.cancel()
+		<-g.done
+	})
+	return nil
+}
+
+func (g *Getter) current() config.Map {
+	return *g.values.Load()
+}
+
+// GetErr : Return the value for key, the latest API error if the informer hasn't recovered from it, or an error
+// wrapping config.ErrKeyNotSet.
+func (g *Getter) GetErr(key string) (string, error) {
+	if err := g.err.Load(); err != nil {
+		return "", *err
+	}
+	val, ok := g.current()[key]
+	if !ok {
+		return "", fmt.Errorf("crdconfig: %s: %w", key, config.ErrKeyNotSet)
+	}
+	return val, nil
+}
+
+// Lookup : Return the value for key from the latest spec, and whether it's set.
+func (g *Getter) Lookup(key string) (string, bool) {
+	return g.current().Lookup(key)
+}
+
+// Keys : Return the keys of the latest spec.
+func (g *Getter) Keys() []string {
+	return g.current().Keys()
+}
+
+// Snapshot : Return the values of the latest spec, which won't change as the resource does.
+func (g *Getter) Snapshot() config.Getter {
+	return g.current()
+}
+
+// Get : Return the value for key from the latest spec.
+func (g *Getter) Get(key string) string {
+	return g.current().Get(key)
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (g *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(g, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (g *Getter) GetStrings(key string) []string {
+	return config.SplitStrings(g.Get(key))
+}
+
+// MustGet will panic if the key is not present or empty.
+func (g *Getter) MustGet(key string) string {
+	return config.MustGet(g, key)
+}
@@ -0,0 +1,105 @@
+package crdconfig
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/efixler/config"
+)
+
+var testGVR = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "appconfigs"}
+
+func appConfig(name string, spec map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.com/v1",
+		"kind":       "AppConfig",
+		"metadata":   map[string]any{"name": name, "namespace": "prod"},
+		"spec":       spec,
+	}}
+}
+
+func newFakeClient(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{testGVR: "AppConfigList"}, objects...)
+}
+
+func TestCRDGetter(t *testing.T) {
+	client := newFakeClient(
+		appConfig("web", map[string]any{"db": map[string]any{"host": "db1", "port": int64(5432)}, "hosts": []any{"a", "b"}}),
+		appConfig("worker", map[string]any{"db": map[string]any{"host": "other"}}),
+	)
+	g, err := NewCRDGetter(client, testGVR, "prod", "web")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	if v := g.Get("db.host"); v != "db1" {
+		t.Errorf("Expected 'db1', got '%s'", v)
+	}
+	if v := g.Get("db.port"); v != "5432" {
+		t.Errorf("Expected '5432', got '%s'", v)
+	}
+	if v := g.GetStrings("hosts"); len(v) != 2 {
+		t.Errorf("Expected [a b], got %q", v)
+	}
+	if _, err := g.GetErr("missing"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+
+	changes := g.Watch()
+	resources := client.Resource(testGVR).Namespace("prod")
+	ctx := context.Background()
+	if _, err := resources.Update(ctx, appConfig("worker", map[string]any{"db": map[string]any{"host": "db9"}}), metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resources.Update(ctx, appConfig("web", map[string]any{"db": map[string]any{"host": "db2"}}), metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a change notification")
+	}
+	if v, err := g.GetErr("db.host"); err != nil || v != "db2" {
+		t.Errorf("Expected 'db2', got '%s' (%v)", v, err)
+	}
+	if _, ok := g.Lookup("hosts"); ok {
+		t.Error("Fields removed from the spec should be dropped")
+	}
+
+	changes = g.Watch()
+	if err := resources.Delete(ctx, "web", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a change notification for the deletion")
+	}
+	if _, err := g.GetErr("db.host"); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected a NotFound error after deletion, got %v", err)
+	}
+}
+
+func TestCRDGetterErrors(t *testing.T) {
+	if _, err := NewCRDGetter(newFakeClient(), testGVR, "prod", "web"); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected a NotFound error for a missing resource, got %v", err)
+	}
+	client := newFakeClient(appConfig("web", map[string]any{"a": "1"}))
+	client.PrependReactor("list", "appconfigs", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(testGVR.GroupResource(), "", errors.New("no RBAC"))
+	})
+	if _, err := NewCRDGetter(client, testGVR, "prod", "web"); !apierrors.IsForbidden(err) {
+		t.Errorf("Expected a Forbidden error, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// NewCSVGetter : Return a Getter over the key/value rows of a CSV file, e.g. a spreadsheet export maintained by
+// people who don't edit config files. keyCol and valCol are the zero-based indices of the key and value columns; other
+// columns (descriptions, owners and so on) are ignored.
+//
+// The first row is a header and is skipped. Quoting follows encoding/csv, so values can contain commas and newlines.
+// Keys and values are trimmed of surrounding whitespace, and rows with an empty key are skipped. A key that appears in
+// more than one row is an error, as is a row that's too short to have both columns; errors name the line.
+func NewCSVGetter(path string, keyCol, valCol int) (Getter, error) {
+	if keyCol < 0 || valCol < 0 || keyCol == valCol {
+		return nil, fmt.Errorf("config: invalid CSV columns %d and %d", keyCol, valCol)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	if _, err := r.Read(); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	values, lines := make(Map), make(map[string]int)
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("config: %s: %w", path, err)
+		}
+		line, _ := r.FieldPos(0)
+		if len(row) <= max(keyCol, valCol) {
+			return nil, fmt.Errorf("config: %s: line %d has %d columns", path, line, len(row))
+		}
+		key := strings.TrimSpace(row[keyCol])
+		if key == "" {
+			continue
+		}
+		if prev, ok := lines[key]; ok {
+			return nil, fmt.Errorf("config: %s: line %d: %s is already set on line %d", path, line, key, prev)
+		}
+		values[key], lines[key] = strings.TrimSpace(row[valCol]), line
+	}
+	return values, nil
+}
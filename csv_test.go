@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewCSVGetter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.csv")
+	os.WriteFile(path, []byte("Owner,Key,Value\nops, HOST ,db1\nops,HOSTS,\"a, b\"\n,,\nsales,GREETING,\"multi\nline\"\n"), 0600)
+	g, err := NewCSVGetter(path, 1, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := g.Get("HOST"); v != "db1" {
+		t.Errorf("Expected 'db1', got '%s'", v)
+	}
+	if v := g.GetStrings("HOSTS"); len(v) != 2 || v[1] != "b" {
+		t.Errorf("Expected [a b], got %q", v)
+	}
+	if v := g.Get("GREETING"); v != "multi\nline" {
+		t.Errorf("Expected a multi-line value, got '%s'", v)
+	}
+	if v := g.Get("Key"); v != "" {
+		t.Errorf("Expected the header to be skipped, got '%s'", v)
+	}
+
+	tests := []struct {
+		data     string
+		expected string
+	}{
+		{"k,v\na,1\nb,2\na,3\n", "line 4: a is already set on line 2"},
+		{"k,v\na,1\nb\n", "line 3 has 1 columns"},
+		{"k,v\na,\"1\n", "line 2"},
+	}
+	for _, test := range tests {
+		os.WriteFile(path, []byte(test.data), 0600)
+		if _, err := NewCSVGetter(path, 0, 1); err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("Expected an error containing '%s', got %v", test.expected, err)
+		}
+	}
+	if _, err := NewCSVGetter(path, 1, 1); err == nil {
+		t.Error("Expected an error for identical columns")
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// DefaultEncryptedPrefix marks encrypted values for WithDecryption when no prefix is given.
+const DefaultEncryptedPrefix = "enc:"
+
+// WithDecryption : Wrap a Getter so that values of the form prefix+base64(ciphertext), e.g. "enc:AAECAw==", are
+// decoded (standard, padded base64) and passed to decrypt, and the plaintext is returned in their place. Values
+// without the prefix pass through unchanged. An empty prefix means DefaultEncryptedPrefix.
+//
+// decrypt is supplied by the caller and holds the key, which should live apart from the config (a KMS client, an age
+// identity, an AEAD with a key from the keyring, ...); this package does no cryptography of its own. Decryption
+// happens on every read, so cache the result if decrypt is slow.
+//
+// The returned Getter is an ErrorGetter; decoding and decryption failures surface through GetErr, and Get returns "".
+// It's also a SecretMarker that marks every encrypted key as a secret.
+func WithDecryption(g Getter, decrypt func(ciphertext []byte) ([]byte, error), prefix string) Getter {
+	if prefix == "" {
+		prefix = DefaultEncryptedPrefix
+	}
+	return &decrypting{g: g, decrypt: decrypt, prefix: prefix}
+}
+
+type decrypting struct {
+	g       Getter
+	decrypt func([]byte) ([]byte, error)
+	prefix  string
+}
+
+func (d *decrypting) GetErr(key string) (string, error) {
+	val, err := GetErr(d.g, key)
+	if err != nil {
+		return "", err
+	}
+	encoded, ok := strings.CutPrefix(val, d.prefix)
+	if !ok {
+		return val, nil
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("config: %s: decoding encrypted value: %w", key, err)
+	}
+	plaintext, err := d.decrypt(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("config: %s: decrypting: %w", key, err)
+	}
+	return string(plaintext), nil
+}
+
+func (d *decrypting) IsSecret(key string) bool {
+	return strings.HasPrefix(d.g.Get(key), d.prefix) || IsSecret(d.g, key)
+}
+
+func (d *decrypting) Get(key string) string {
+	val, _ := d.GetErr(key)
+	return val
+}
+
+func (d *decrypting) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(d, key, dflt)
+}
+
+func (d *decrypting) GetStrings(key string) []string {
+	if !strings.HasPrefix(d.g.Get(key), d.prefix) {
+		return d.g.GetStrings(key)
+	}
+	return SplitStrings(d.Get(key))
+}
+
+func (d *decrypting) MustGet(key string) string {
+	return MustGet(d, key)
+}
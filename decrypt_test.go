@@ -0,0 +1,52 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// xorDecrypt is a stand-in cipher for the tests.
+func xorDecrypt(ciphertext []byte) ([]byte, error) {
+	if bytes.HasPrefix(ciphertext, []byte("bad")) {
+		return nil, errors.New("authentication failed")
+	}
+	rval := make([]byte, len(ciphertext))
+	for i, b := range ciphertext {
+		rval[i] = b ^ 0x2a
+	}
+	return rval, nil
+}
+
+func TestWithDecryption(t *testing.T) {
+	encrypt := func(plaintext string) string {
+		ct, _ := xorDecrypt([]byte(plaintext))
+		return "enc:" + base64.StdEncoding.EncodeToString(ct)
+	}
+	g := WithDecryption(Map{
+		"PASSWORD": encrypt("hunter2"),
+		"HOSTS":    encrypt("a, b"),
+		"PLAIN":    "visible",
+		"NOT_B64":  "enc:!!!",
+		"TAMPERED": "enc:" + base64.StdEncoding.EncodeToString([]byte("bad")),
+	}, xorDecrypt, "")
+	if v := g.Get("PASSWORD"); v != "hunter2" {
+		t.Errorf("Expected 'hunter2', got '%s'", v)
+	}
+	if v := g.GetStrings("HOSTS"); !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %q", v)
+	}
+	if v := g.Get("PLAIN"); v != "visible" {
+		t.Errorf("Expected 'visible', got '%s'", v)
+	}
+	for _, key := range []string{"NOT_B64", "TAMPERED"} {
+		if v, err := GetErr(g, key); err == nil || v != "" {
+			t.Errorf("%s: expected an error, got '%s'", key, v)
+		}
+	}
+	if !IsSecret(g, "PASSWORD") || IsSecret(g, "PLAIN") {
+		t.Error("Expected only encrypted keys to be marked secret")
+	}
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultsRegistry holds typed default values, registered once (typically at init time) and used by the typed
+// accessors GetInt, GetBool, etc., so each default is defined in exactly one place. It's safe for concurrent use.
+type DefaultsRegistry struct {
+	lock     sync.RWMutex
+	defaults map[string]any
+}
+
+// RegisteredDefault describes a registered default, for generating documentation.
+type RegisteredDefault struct {
+	Key   string
+	Kind  string
+	Value string
+}
+
+// NewDefaultsRegistry : Return an empty registry.
+func NewDefaultsRegistry() *DefaultsRegistry {
+	return &DefaultsRegistry{defaults: make(map[string]any)}
+}
+
+func (r *DefaultsRegistry) set(key string, val any) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.defaults[key] = val
+}
+
+// SetInt : Register an int default for key. Registering a key again replaces its default (and kind).
+func (r *DefaultsRegistry) SetInt(key string, val int) { r.set(key, val) }
+
+// SetBool : Register a bool default for key.
+func (r *DefaultsRegistry) SetBool(key string, val bool) { r.set(key, val) }
+
+// SetDuration : Register a time.Duration default for key.
+func (r *DefaultsRegistry) SetDuration(key string, val time.Duration) { r.set(key, val) }
+
+// SetFloat : Register a float64 default for key.
+func (r *DefaultsRegistry) SetFloat(key string, val float64) { r.set(key, val) }
+
+// SetString : Register a string default for key.
+func (r *DefaultsRegistry) SetString(key string, val string) { r.set(key, val) }
+
+// SetStrings : Register a []string default for key.
+func (r *DefaultsRegistry) SetStrings(key string, val []string) {
+	r.set(key, append([]string(nil), val...))
+}
+
+// Defaults : Return all the registered defaults, sorted by key.
+func (r *DefaultsRegistry) Defaults() []RegisteredDefault {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	rval := make([]RegisteredDefault, 0, len(r.defaults))
+	for key, val := range r.defaults {
+		kind := fmt.Sprintf("%T", val)
+		if _, ok := val.(time.Duration); ok {
+			kind = "duration"
+		}
+		rval = append(rval, RegisteredDefault{Key: key, Kind: kind, Value: fmt.Sprint(val)})
+	}
+	sort.Slice(rval, func(i, j int) bool { return rval[i].Key < rval[j].Key })
+	return rval
+}
+
+// lookupDefault returns the default registered for key, if there is one of type T. A nil registry has no defaults.
+func lookupDefault[T any](r *DefaultsRegistry, key string) T {
+	var zero T
+	if r == nil {
+		return zero
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if val, ok := r.defaults[key].(T); ok {
+		return val
+	}
+	return zero
+}
+
+// getTyped parses key's value, falling back to its registered default when it's unset or doesn't parse.
+func getTyped[T any](g Getter, r *DefaultsRegistry, key string, kind string, parse func(string) (T, error)) T {
+	if raw := g.Get(key); raw != "" {
+		val, err := parse(raw)
+		if err == nil {
+			return val
+		}
+		log.Printf("config: %s is not a valid %s, using the default", key, kind)
+	}
+	return lookupDefault[T](r, key)
+}
+
+// GetInt : Return key's value as an int, or the default registered in r if it's unset or not an int.
+// r may be nil, in which case the default is 0. The other typed accessors work the same way.
+func GetInt(g Getter, r *DefaultsRegistry, key string) int {
+	return getTyped(g, r, key, "int", strconv.Atoi)
+}
+
+// GetBool : Return key's value as a bool (per strconv.ParseBool), or its registered default.
+func GetBool(g Getter, r *DefaultsRegistry, key string) bool {
+	return getTyped(g, r, key, "bool", strconv.ParseBool)
+}
+
+// GetDuration : Return key's value as a time.Duration (per time.ParseDuration), or its registered default.
+func GetDuration(g Getter, r *DefaultsRegistry, key string) time.Duration {
+	return getTyped(g, r, key, "duration", time.ParseDuration)
+}
+
+// GetFloat : Return key's value as a float64, or its registered default.
+func GetFloat(g Getter, r *DefaultsRegistry, key string) float64 {
+	return getTyped(g, r, key, "float", func(s string) (float64, error) { return strconv.ParseFloat(s, 64) })
+}
+
+// GetString : Return key's value, or its registered default if it's unset.
+func GetString(g Getter, r *DefaultsRegistry, key string) string {
+	return getTyped(g, r, key, "string", func(s string) (string, error) { return s, nil })
+}
+
+// GetStringSlice : Return g.GetStrings(key), or a copy of key's registered default if it's unset.
+func GetStringSlice(g Getter, r *DefaultsRegistry, key string) []string {
+	if g.Get(key) != "" {
+		return g.GetStrings(key)
+	}
+	return append([]string(nil), lookupDefault[[]string](r, key)...)
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDefaultsRegistry(t *testing.T) {
+	reg := NewDefaultsRegistry()
+	reg.SetInt("PORT", 8080)
+	reg.SetBool("DEBUG", true)
+	reg.SetDuration("TIMEOUT", 5*time.Second)
+	reg.SetFloat("RATIO", 0.5)
+	reg.SetString("HOST", "localhost")
+	reg.SetStrings("TAGS", []string{"a", "b"})
+
+	empty := Map{}
+	if v := GetInt(empty, reg, "PORT"); v != 8080 {
+		t.Errorf("Expected default 8080, got %d", v)
+	}
+	if v := GetBool(empty, reg, "DEBUG"); !v {
+		t.Error("Expected default true")
+	}
+	if v := GetDuration(empty, reg, "TIMEOUT"); v != 5*time.Second {
+		t.Errorf("Expected default 5s, got %v", v)
+	}
+	if v := GetFloat(empty, reg, "RATIO"); v != 0.5 {
+		t.Errorf("Expected default 0.5, got %v", v)
+	}
+	if v := GetString(empty, reg, "HOST"); v != "localhost" {
+		t.Errorf("Expected default 'localhost', got '%s'", v)
+	}
+	if v := GetStringSlice(empty, reg, "TAGS"); !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("Expected default [a b], got %q", v)
+	}
+
+	set := Map{"PORT": "9090", "DEBUG": "nope", "TAGS": "x"}
+	if v := GetInt(set, reg, "PORT"); v != 9090 {
+		t.Errorf("Expected configured 9090, got %d", v)
+	}
+	if v := GetBool(set, reg, "DEBUG"); !v {
+		t.Error("Expected the default for an invalid bool")
+	}
+	if v := GetStringSlice(set, reg, "TAGS"); !reflect.DeepEqual(v, []string{"x"}) {
+		t.Errorf("Expected configured [x], got %q", v)
+	}
+	if v := GetInt(set, nil, "MISSING"); v != 0 {
+		t.Errorf("Expected 0 with no registry, got %d", v)
+	}
+
+	defaults := reg.Defaults()
+	if len(defaults) != 6 || defaults[0] != (RegisteredDefault{Key: "DEBUG", Kind: "bool", Value: "true"}) {
+		t.Errorf("Unexpected defaults listing %v", defaults)
+	}
+	if defaults[4] != (RegisteredDefault{Key: "TAGS", Kind: "[]string", Value: "[a b]"}) || defaults[5].Kind != "duration" {
+		t.Errorf("Unexpected defaults listing %v", defaults)
+	}
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// FieldInfo describes a field of a config struct, for generated documentation and admin UIs. See DescribeStruct.
+type FieldInfo struct {
+	// Field is the Go path to the field, e.g. "DB.Host".
+	Field string
+	// Key is the config key, from the field's config tag.
+	Key string
+	// Type is the field's Go type, e.g. "time.Duration".
+	Type string
+	// Value is the field's current value, stringified; Redacted for keys that SetSecretPredicate marks secret.
+	Value    string
+	Required bool
+	// Default is the field's default tag, if any.
+	Default string
+}
+
+// DescribeStruct : Describe the config fields of target, a struct or a pointer to one, in field order.
+//
+// Config fields are tagged with the key they're read from and, optionally, whether it's required, with the default
+// in its own tag so it can contain commas:
+//
+//	Port  int      `config:"PORT,required"`
+//	Hosts []string `config:"HOSTS" default:"a,b"`
+//
+// Untagged struct fields (and non-nil pointers to structs) are nested config and are described recursively, their
+// Field paths joined with dots; their keys are used as tagged, without a prefix. Other untagged fields, and unexported
+// fields, are skipped. Slices are stringified comma-joined, so Value reads like the config value it came from.
+func DescribeStruct(target any) []FieldInfo {
+	v := reflect.ValueOf(target)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	rval := make([]FieldInfo, 0)
+	if v.Kind() == reflect.Struct {
+		describeFields(&rval, "", v)
+	}
+	return rval
+}
+
+func describeFields(dst *[]FieldInfo, prefix string, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		tag, tagged := sf.Tag.Lookup("config")
+		if !tagged {
+			for fv.Kind() == reflect.Pointer && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				describeFields(dst, prefix+sf.Name+".", fv)
+			}
+			continue
+		}
+		key, opts, _ := strings.Cut(tag, ",")
+		info := FieldInfo{
+			Field:   prefix + sf.Name,
+			Key:     key,
+			Type:    sf.Type.String(),
+			Value:   fieldString(fv),
+			Default: sf.Tag.Get("default"),
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			if strings.TrimSpace(opt) == "required" {
+				info.Required = true
+			}
+		}
+		if isSecretKey(key) {
+			info.Value = Redacted
+		}
+		*dst = append(*dst, info)
+	}
+}
+
+// fieldString stringifies a field value the way it would be written in config.
+func fieldString(v reflect.Value) string {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	switch val := v.Interface().(type) {
+	case time.Duration:
+		return val.String()
+	case fmt.Stringer:
+		return val.String()
+	}
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		vals := make([]string, v.Len())
+		for i := range vals {
+			vals[i] = fieldString(v.Index(i))
+		}
+		return strings.Join(vals, ",")
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type describedDB struct {
+	Host     string `config:"DB_HOST,required"`
+	Password string `config:"DB_PASSWORD"`
+}
+
+type describedConfig struct {
+	Port    int           `config:"PORT,required" default:"8080"`
+	Hosts   []string      `config:"HOSTS" default:"a,b"`
+	Timeout time.Duration `config:"TIMEOUT" default:"5s"`
+	DB      *describedDB
+	Notes   string
+	secret  string `config:"HIDDEN"`
+}
+
+func TestDescribeStruct(t *testing.T) {
+	SetSecretPredicate(func(key string) bool { return key == "DB_PASSWORD" })
+	defer SetSecretPredicate(nil)
+	cfg := describedConfig{
+		Port:    9090,
+		Hosts:   []string{"x", "y"},
+		Timeout: 2 * time.Second,
+		DB:      &describedDB{Host: "db1", Password: "hunter2"},
+		secret:  "s",
+	}
+	expected := []FieldInfo{
+		{Field: "Port", Key: "PORT", Type: "int", Value: "9090", Required: true, Default: "8080"},
+		{Field: "Hosts", Key: "HOSTS", Type: "[]string", Value: "x,y", Default: "a,b"},
+		{Field: "Timeout", Key: "TIMEOUT", Type: "time.Duration", Value: "2s", Default: "5s"},
+		{Field: "DB.Host", Key: "DB_HOST", Type: "string", Value: "db1", Required: true},
+		{Field: "DB.Password", Key: "DB_PASSWORD", Type: "string", Value: Redacted},
+	}
+	if got := DescribeStruct(&cfg); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+	if got := DescribeStruct(describedConfig{}); len(got) != 3 {
+		t.Errorf("Expected a nil nested pointer to be skipped, got %+v", got)
+	}
+	if got := DescribeStruct(42); len(got) != 0 {
+		t.Errorf("Expected nothing for a non-struct, got %+v", got)
+	}
+}
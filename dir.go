@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NewDirGetter : Return a Getter that reads each key from the file of the same name in dir, the layout used by
+// Kubernetes secret/configmap volumes and Docker secrets. Files are read on every Get, so the Getter sees updates
+// to the directory, and surrounding whitespace (usually a trailing newline) is trimmed from the contents.
+// Keys containing a path separator are never read.
+//
+// The Getter is a Lister (the regular files in dir, and symlinks to them, skipping dotfiles like Kubernetes' ..data)
+// and a TimestampedGetter (the file's mtime).
+func NewDirGetter(dir string) Getter {
+	return &dirGetter{dir: dir}
+}
+
+type dirGetter struct {
+	dir string
+}
+
+func (d *dirGetter) path(key string) (string, bool) {
+	if key == "" || strings.ContainsAny(key, `/\`) || key == "." || key == ".." {
+		return "", false
+	}
+	return filepath.Join(d.dir, key), true
+}
+
+func (d *dirGetter) Lookup(key string) (string, bool) {
+	path, ok := d.path(key)
+	if !ok {
+		return "", false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+func (d *dirGetter) Get(key string) string {
+	v, _ := d.Lookup(key)
+	return v
+}
+
+func (d *dirGetter) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(d, key, dflt)
+}
+
+func (d *dirGetter) GetStrings(key string) []string {
+	return SplitStrings(d.Get(key))
+}
+
+func (d *dirGetter) MustGet(key string) string {
+	return MustGet(d, key)
+}
+
+func (d *dirGetter) Keys() []string {
+	rval := make([]string, 0)
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return rval
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		// Stat through symlinks: in Kubernetes volumes every key is a link into the ..data directory.
+		if info, err := os.Stat(filepath.Join(d.dir, entry.Name())); err == nil && info.Mode().IsRegular() {
+			rval = append(rval, entry.Name())
+		}
+	}
+	sort.Strings(rval)
+	return rval
+}
+
+func (d *dirGetter) ModTime(key string) (time.Time, bool) {
+	path, ok := d.path(key)
+	if !ok {
+		return time.Time{}, false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return info.ModTime(), true
+}
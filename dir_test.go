@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDirGetter(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "DB_PASSWORD"), []byte("hunter2\n"), 0600)
+	os.WriteFile(filepath.Join(dir, "HOSTS"), []byte("a,b"), 0600)
+	os.WriteFile(filepath.Join(dir, ".hidden"), []byte("x"), 0600)
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	os.Chtimes(filepath.Join(dir, "HOSTS"), mtime, mtime)
+
+	c := NewDirGetter(dir)
+	if v := c.Get("DB_PASSWORD"); v != "hunter2" {
+		t.Errorf("Expected 'hunter2', got '%s'", v)
+	}
+	if v := c.Get("../DB_PASSWORD"); v != "" {
+		t.Errorf("Keys with path separators should not be read, got '%s'", v)
+	}
+	if keys := c.(Lister).Keys(); !reflect.DeepEqual(keys, []string{"DB_PASSWORD", "HOSTS"}) {
+		t.Errorf("Expected keys [DB_PASSWORD HOSTS], got %q", keys)
+	}
+	if ts, ok := ModTime(c, "HOSTS"); !ok || !ts.Equal(mtime) {
+		t.Errorf("Expected mod time %v, got %v (%t)", mtime, ts, ok)
+	}
+	if _, ok := ModTime(c, "MISSING"); ok {
+		t.Error("Expected no mod time for a missing key")
+	}
+}
+
+func TestDirGetterSymlinks(t *testing.T) {
+	// The layout of a Kubernetes configmap volume: each key links into ..data, which links to a timestamped dir.
+	dir := t.TempDir()
+	os.Mkdir(filepath.Join(dir, "..2024_01_01"), 0700)
+	os.WriteFile(filepath.Join(dir, "..2024_01_01", "HOST"), []byte("db1"), 0600)
+	if err := os.Symlink("..2024_01_01", filepath.Join(dir, "..data")); err != nil {
+		t.Skipf("Can't create symlinks: %v", err)
+	}
+	os.Symlink(filepath.Join("..data", "HOST"), filepath.Join(dir, "HOST"))
+	os.Symlink("..data", filepath.Join(dir, "LINKED_DIR"))
+
+	c := NewDirGetter(dir)
+	if keys := c.(Lister).Keys(); !reflect.DeepEqual(keys, []string{"HOST"}) {
+		t.Errorf("Expected keys [HOST], got %q", keys)
+	}
+	if v := c.Get("HOST"); v != "db1" {
+		t.Errorf("Expected 'db1', got '%s'", v)
+	}
+}
+
+func TestModTimeUnsupported(t *testing.T) {
+	if ts, ok := ModTime(Map{"A": "1"}, "A"); ok || !ts.IsZero() {
+		t.Errorf("Expected zero time and false, got %v, %t", ts, ok)
+	}
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// NewDirectiveGetter : Read a file of Caddy/Nginx-style directives and return a Getter holding its values. The file
+// is read once.
+//
+// The grammar is small. A file is a sequence of directives; each is a name followed by zero or more values, all
+// separated by whitespace and terminated by a semicolon, a newline, or the } that closes the enclosing block.
+// A directive whose arguments are followed by { opens a block, which runs to the matching }; the { must be on the
+// same line as the directive. # starts a comment that runs to the end of the line. Values can be in single or double quotes
+// to hold whitespace, semicolons, braces or # characters; inside double quotes a backslash escapes the next character.
+//
+//	# listen on two ports
+//	listen 80 443;
+//	server {
+//	    name "Example Site"
+//	    tls { cert /etc/tls/cert.pem }
+//	}
+//	upstream backend { host a.internal b.internal }
+//
+// Directives inside blocks are keyed by the block's name and arguments, joined with dots, and then the directive name:
+// the file above has the keys listen, server.name, server.tls.cert and upstream.backend.host. Get returns a
+// directive's values joined with single spaces ("80 443") and GetStrings returns them separately, so quoted values
+// containing commas or whitespace survive. A directive that appears more than once in the same block accumulates
+// its values, as with nginx's repeated listen directives. Parse errors name the line.
+func NewDirectiveGetter(path string) (Getter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	lists, err := parseDirectives(data)
+	if err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	values := make(Map, len(lists))
+	for key, vals := range lists {
+		values[key] = strings.Join(vals, " ")
+	}
+	return &directiveGetter{values: values, lists: lists}, nil
+}
+
+type directiveGetter struct {
+	values Map
+	lists  map[string][]string
+}
+
+func (d *directiveGetter) Get(key string) string {
+	return d.values.Get(key)
+}
+
+func (d *directiveGetter) Lookup(key string) (string, bool) {
+	return d.values.Lookup(key)
+}
+
+func (d *directiveGetter) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(d, key, dflt)
+}
+
+// GetStrings : Return the directive's values as written, rather than splitting Get() on commas.
+func (d *directiveGetter) GetStrings(key string) []string {
+	vals, ok := d.lists[key]
+	if !ok {
+		return []string{""}
+	}
+	return append([]string(nil), vals...)
+}
+
+func (d *directiveGetter) MustGet(key string) string {
+	return MustGet(d, key)
+}
+
+func (d *directiveGetter) Keys() []string {
+	return d.values.Keys()
+}
+
+// Snapshot : The values are read once, so the Getter is its own snapshot.
+func (d *directiveGetter) Snapshot() Getter {
+	return d
+}
+
+type directiveTokenKind int
+
+const (
+	directiveWord directiveTokenKind = iota
+	directiveEnd                     // ; or newline
+	directiveOpen
+	directiveClose
+)
+
+type directiveToken struct {
+	kind directiveTokenKind
+	text string
+	line int
+}
+
+func lexDirectives(data []byte) ([]directiveToken, error) {
+	var tokens []directiveToken
+	src := string(data)
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			tokens = append(tokens, directiveToken{kind: directiveEnd, line: line})
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == ';':
+			tokens = append(tokens, directiveToken{kind: directiveEnd, line: line})
+			i++
+		case c == '{':
+			tokens = append(tokens, directiveToken{kind: directiveOpen, line: line})
+			i++
+		case c == '}':
+			tokens = append(tokens, directiveToken{kind: directiveClose, line: line})
+			i++
+		case c == '"' || c == '\'':
+			start := line
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(src) {
+					return nil, fmt.Errorf("line %d: unterminated quoted string", start)
+				}
+				if src[i] == c {
+					i++
+					break
+				}
+				if src[i] == '\\' && c == '"' && i+1 < len(src) {
+					i++
+				}
+				if src[i] == '\n' {
+					line++
+				}
+				b.WriteByte(src[i])
+				i++
+			}
+			tokens = append(tokens, directiveToken{kind: directiveWord, text: b.String(), line: start})
+		default:
+			start := i
+			for i < len(src) && !strings.ContainsRune(" \t\r\n#;{}\"'", rune(src[i])) {
+				i++
+			}
+			tokens = append(tokens, directiveToken{kind: directiveWord, text: src[start:i], line: line})
+		}
+	}
+	return tokens, nil
+}
+
+// parseDirectives returns each flattened directive key with its accumulated values.
+func parseDirectives(data []byte) (map[string][]string, error) {
+	tokens, err := lexDirectives(data)
+	if err != nil {
+		return nil, err
+	}
+	lists := make(map[string][]string)
+	var prefixes []string // the key prefix of each open block
+	var opened []int      // the line each open block started on
+	var words []string    // the directive being read
+	prefix := func() string {
+		if len(prefixes) == 0 {
+			return ""
+		}
+		return prefixes[len(prefixes)-1]
+	}
+	finish := func() {
+		if len(words) > 0 {
+			key := prefix() + words[0]
+			lists[key] = append(lists[key], words[1:]...)
+			if lists[key] == nil {
+				lists[key] = []string{}
+			}
+		}
+		words = words[:0]
+	}
+	for _, tok := range tokens {
+		switch tok.kind {
+		case directiveWord:
+			words = append(words, tok.text)
+		case directiveEnd:
+			finish()
+		case directiveOpen:
+			if len(words) == 0 {
+				return nil, fmt.Errorf("line %d: block has no name", tok.line)
+			}
+			prefixes = append(prefixes, prefix()+strings.Join(words, ".")+".")
+			opened = append(opened, tok.line)
+			words = words[:0]
+		case directiveClose:
+			if len(prefixes) == 0 {
+				return nil, fmt.Errorf("line %d: unexpected }", tok.line)
+			}
+			finish()
+			prefixes, opened = prefixes[:len(prefixes)-1], opened[:len(opened)-1]
+		}
+	}
+	if len(opened) > 0 {
+		return nil, fmt.Errorf("line %d: block is never closed", opened[len(opened)-1])
+	}
+	finish()
+	return lists, nil
+}
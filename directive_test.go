@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDirectives = `
+# listen on two ports
+listen 80 443;
+listen 8443
+server {
+    name "Example Site"; root /srv/www # trailing comment
+    tls { cert /etc/tls/cert.pem }
+    header 'X-Frame-Options' "DENY"
+    motd "say \"hi\"; {ok}"
+}
+upstream backend { host a.internal b.internal }
+gzip;
+`
+
+func writeDirectives(t *testing.T, data string) string {
+	path := filepath.Join(t.TempDir(), "site.conf")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewDirectiveGetter(t *testing.T) {
+	g, err := NewDirectiveGetter(writeDirectives(t, testDirectives))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tests := map[string]string{
+		"listen":                "80 443 8443",
+		"server.name":           "Example Site",
+		"server.root":           "/srv/www",
+		"server.tls.cert":       "/etc/tls/cert.pem",
+		"server.header":         "X-Frame-Options DENY",
+		"server.motd":           `say "hi"; {ok}`,
+		"upstream.backend.host": "a.internal b.internal",
+	}
+	for key, expected := range tests {
+		if v := g.Get(key); v != expected {
+			t.Errorf("%s: expected %q, got %q", key, expected, v)
+		}
+	}
+	if v := g.GetStrings("listen"); !reflect.DeepEqual(v, []string{"80", "443", "8443"}) {
+		t.Errorf("Expected each listen value, got %q", v)
+	}
+	if v := g.GetStrings("server.name"); !reflect.DeepEqual(v, []string{"Example Site"}) {
+		t.Errorf("Quoted values should stay whole, got %q", v)
+	}
+	if v, ok := Lookup(g, "gzip"); !ok || v != "" {
+		t.Errorf("A directive without values should be set and empty, got %q, %v", v, ok)
+	}
+	if _, ok := Lookup(g, "server"); ok {
+		t.Error("A block name should not be a key of its own")
+	}
+	expected := []string{"gzip", "listen", "server.header", "server.motd", "server.name", "server.root",
+		"server.tls.cert", "upstream.backend.host"}
+	if keys := g.(Lister).Keys(); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Expected keys %q, got %q", expected, keys)
+	}
+}
+
+func TestNewDirectiveGetterErrors(t *testing.T) {
+	tests := map[string]string{
+		"server {\n  name x\n":       "line 1: block is never closed",
+		"name x\n}\n":                "line 2: unexpected }",
+		"a 1\n{ b 2 }\n":             "line 2: block has no name",
+		"a 1\nname \"unterminated\n": "line 2: unterminated quoted string",
+	}
+	for data, expected := range tests {
+		_, err := NewDirectiveGetter(writeDirectives(t, data))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%q: expected an error containing %q, got %v", data, expected, err)
+		}
+	}
+	if _, err := NewDirectiveGetter(filepath.Join(t.TempDir(), "missing.conf")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
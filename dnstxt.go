@@ -0,0 +1,142 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// dnsTXTMaxAge stands in for the record TTL, which net.Resolver doesn't expose.
+	dnsTXTMaxAge = 5 * time.Minute
+	// dnsTXTTimeout bounds each lookup.
+	dnsTXTTimeout = 5 * time.Second
+)
+
+// DNSTXTGetter holds config published in the TXT records of a domain. See NewDNSTXTGetter.
+type DNSTXTGetter struct {
+	domain     string
+	lookup     func(ctx context.Context, domain string) ([]string, error)
+	lock       sync.Mutex
+	values     Map
+	fetched    time.Time
+	refreshing bool
+	err        error
+	now        func() time.Time
+}
+
+// NewDNSTXTGetter : Return a Getter over KEY=VALUE tokens published in the TXT records of domain, for service discovery
+// setups that publish config in DNS. A nil resolver means net.DefaultResolver. The records are looked up here, and a
+// failure is returned.
+//
+// Each record is split into whitespace-separated tokens; a token of the form KEY=VALUE sets KEY (a value can't contain
+// spaces, so use commas for lists), and other tokens are ignored, so unrelated records like SPF don't get in the way
+// (beyond any = tokens they contain). The strings of a multi-string record are concatenated before splitting, so a
+// token can span them. When the same key appears more than once, the last record (in the order the resolver returns
+// them, which DNS doesn't define) wins. Each string in a TXT record is limited to 255 bytes, and a whole response should
+// stay under about 1232 bytes to avoid truncation and fallback to TCP, so keep DNS for small amounts of config.
+//
+// net.Resolver doesn't report record TTLs, so instead the records are looked up again on the first read that's more
+// than five minutes after the last lookup; keep the record TTL at or under that. Reads made while that lookup is in
+// flight are served the previous values rather than waiting on it. If a lookup fails it's logged, and GetErr returns
+// the error (until a lookup succeeds), while Get keeps serving the last values that were looked up.
+func NewDNSTXTGetter(domain string, resolver *net.Resolver) (*DNSTXTGetter, error) {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return newDNSTXTGetter(domain, resolver.LookupTXT)
+}
+
+func newDNSTXTGetter(domain string, lookup func(context.Context, string) ([]string, error)) (*DNSTXTGetter, error) {
+	d := &DNSTXTGetter{domain: domain, lookup: lookup, values: Map{}, now: time.Now}
+	d.fetched = d.now()
+	if err := d.refresh(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// refresh looks the records up again, without holding the lock while it waits on DNS.
+func (d *DNSTXTGetter) refresh() error {
+	ctx, cancel := context.WithTimeout(context.Background(), dnsTXTTimeout)
+	defer cancel()
+	records, err := d.lookup(ctx, d.domain)
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.refreshing = false
+	if err != nil {
+		d.err = fmt.Errorf("config: looking up TXT records for %s: %w", d.domain, err)
+		return d.err
+	}
+	values := make(Map)
+	for _, record := range records {
+		for _, token := range strings.Fields(record) {
+			if key, val, ok := strings.Cut(token, "="); ok && key != "" {
+				values[key] = val
+			}
+		}
+	}
+	d.values, d.err = values, nil
+	return nil
+}
+
+// current returns the values, looking the records up again first if they're stale and no other read is already
+// doing so.
+func (d *DNSTXTGetter) current() (Map, error) {
+	d.lock.Lock()
+	if !d.refreshing && d.now().Sub(d.fetched) >= dnsTXTMaxAge {
+		d.refreshing, d.fetched = true, d.now()
+		d.lock.Unlock()
+		if err := d.refresh(); err != nil {
+			log.Print(err)
+		}
+		d.lock.Lock()
+	}
+	defer d.lock.Unlock()
+	return d.values, d.err
+}
+
+// GetErr : Return the value for key, the error from the latest lookup if it failed, or an error wrapping
+// ErrKeyNotSet.
+func (d *DNSTXTGetter) GetErr(key string) (string, error) {
+	values, err := d.current()
+	if err != nil {
+		return "", err
+	}
+	val, ok := values[key]
+	if !ok {
+		return "", fmt.Errorf("config: %s: %w", key, ErrKeyNotSet)
+	}
+	return val, nil
+}
+
+// Get : Return the value for key from the latest successful lookup.
+func (d *DNSTXTGetter) Get(key string) string {
+	values, _ := d.current()
+	return values.Get(key)
+}
+
+// Keys : Return the keys from the latest successful lookup.
+func (d *DNSTXTGetter) Keys() []string {
+	values, _ := d.current()
+	return values.Keys()
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (d *DNSTXTGetter) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(d, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (d *DNSTXTGetter) GetStrings(key string) []string {
+	return SplitStrings(d.Get(key))
+}
+
+// MustGet will panic if the key is not present or empty.
+func (d *DNSTXTGetter) MustGet(key string) string {
+	return MustGet(d, key)
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDNSTXTGetter(t *testing.T) {
+	records := []string{"v=spf1 include:_spf.example.com ~all", "region=us-east hosts=a,b", "region=eu-west"}
+	var lookupErr error
+	lookups := 0
+	lookup := func(ctx context.Context, domain string) ([]string, error) {
+		lookups++
+		if domain != "_config.example.com" {
+			t.Errorf("Unexpected domain %s", domain)
+		}
+		return records, lookupErr
+	}
+	d, err := newDNSTXTGetter("_config.example.com", lookup)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	now := time.Now()
+	d.now = func() time.Time { return now }
+	if v := d.Get("region"); v != "eu-west" {
+		t.Errorf("Expected the last record to win, got '%s'", v)
+	}
+	if v := d.GetStrings("hosts"); !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %q", v)
+	}
+	if v := d.Get("include:_spf.example.com"); v != "" {
+		t.Errorf("Expected tokens without = to be ignored, got '%s'", v)
+	}
+	if _, err := d.GetErr("missing"); !errors.Is(err, ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+
+	records, lookupErr = nil, errors.New("no such host")
+	now = now.Add(10 * time.Minute)
+	if _, err := d.GetErr("region"); err == nil {
+		t.Error("Expected the lookup error")
+	}
+	if v := d.Get("region"); v != "eu-west" {
+		t.Errorf("Expected the last good value, got '%s'", v)
+	}
+	if lookups != 2 {
+		t.Errorf("Expected 2 lookups, got %d", lookups)
+	}
+	if _, err := newDNSTXTGetter("_config.example.com", lookup); err == nil {
+		t.Error("Expected an error from the initial lookup")
+	}
+}
+
+func TestDNSTXTGetterServesStale(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	blocking := false
+	lookup := func(ctx context.Context, domain string) ([]string, error) {
+		if blocking {
+			started <- struct{}{}
+			<-release
+		}
+		return []string{"region=us-east"}, nil
+	}
+	d, err := newDNSTXTGetter("_config.example.com", lookup)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	now := time.Now().Add(10 * time.Minute)
+	d.now = func() time.Time { return now }
+	blocking = true
+	go d.Get("region")
+	<-started
+	done := make(chan string)
+	go func() { done <- d.Get("region") }()
+	select {
+	case v := <-done:
+		if v != "us-east" {
+			t.Errorf("Expected the stale value 'us-east', got '%s'", v)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("A read waited on the lookup in flight")
+	}
+	close(release)
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// EnvironSlice : Enumerate g and return its values as "KEY=VALUE" entries suitable for exec.Cmd.Env, with prefix
+// prepended to each key. This builds a child process's environment from centrally-read config without
+// touching the current process's environment: there are no side effects.
+//
+// g must be a Lister. Entries are sorted by key, and keys that can't be environment variable names
+// (empty, or containing "=" or NUL) are skipped.
+func EnvironSlice(g Getter, prefix string) ([]string, error) {
+	lister, ok := g.(Lister)
+	if !ok {
+		return nil, errors.New("config: EnvironSlice requires a Getter that implements Lister")
+	}
+	keys := lister.Keys()
+	sort.Strings(keys)
+	rval := make([]string, 0, len(keys))
+	for _, key := range keys {
+		name := prefix + key
+		if name == "" || strings.ContainsAny(name, "=\x00") {
+			continue
+		}
+		rval = append(rval, name+"="+g.Get(key))
+	}
+	return rval, nil
+}
@@ -0,0 +1,23 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestEnvironSlice(t *testing.T) {
+	env, err := EnvironSlice(Map{"PORT": "80", "HOST": "db", "A=B": "x"}, "APP_")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := []string{"APP_HOST=db", "APP_PORT=80"}; !reflect.DeepEqual(env, expected) {
+		t.Errorf("Expected %q, got %q", expected, env)
+	}
+	if os.Getenv("APP_HOST") != "" {
+		t.Error("EnvironSlice should not modify the process environment")
+	}
+	if _, err := EnvironSlice(WithURLDecode(Map{}), ""); err == nil {
+		t.Error("Expected an error for a Getter that isn't a Lister")
+	}
+}
@@ -0,0 +1,278 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EpochSource is a remote config backend that can report the version of its config cheaply, so that a large
+// document is only fetched and parsed when it has changed. See NewEpochGetter.
+//
+// The contract: an epoch is an opaque, non-empty string naming one version of the whole document. Two calls that
+// return the same epoch refer to the same values, and any change to the values changes the epoch (a counter, a
+// content hash and a commit ID all work). Epoch must be much cheaper than Fetch. Fetch returns the values, in a map
+// the caller may keep, along with the epoch they belong to, read together, so that a change landing between an Epoch
+// call and a Fetch can't pair new values with an old epoch.
+type EpochSource interface {
+	Epoch(ctx context.Context) (string, error)
+	Fetch(ctx context.Context) (epoch string, values map[string]string, err error)
+}
+
+// epochCache is the on-disk cache format, and the state the Getter serves.
+type epochCache struct {
+	Epoch  string            `json:"epoch"`
+	Values map[string]string `json:"values"`
+	// Omitted are the secret keys of the document, which are left out of the file.
+	Omitted []string `json:"omitted,omitempty"`
+}
+
+// EpochGetter serves a document from an EpochSource, re-fetching only when its epoch changes. See NewEpochGetter.
+type EpochGetter struct {
+	Notifier
+	ErrorReporter
+	src       EpochSource
+	path      string
+	interval  time.Duration
+	state     atomic.Pointer[epochCache]
+	err       atomic.Pointer[error]
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewEpochGetter : Return a Getter over the document src serves, cached in memory and in the file at cachePath.
+// Every interval (which must be positive) the Getter asks src for its epoch, and fetches the document only if the epoch differs from the one
+// it has, notifying Watch() channels when it does. The cache file is rewritten after each fetch, and read at startup,
+// so a restarted process whose cached epoch is still current doesn't fetch or parse the document at all.
+//
+// The cache file is JSON holding the epoch, the values, and the names of any keys held back from it:
+//
+//	{"epoch": "42", "values": {"db.host": "db1"}, "omitted": ["db.password"]}
+//
+// Secret keys (those matched by SetSecretPredicate, or that src reports as secret by being a SecretMarker) are never
+// written to the file; they're listed in omitted instead. A cache with omitted keys can't stand in for the document,
+// so at startup it's only used as a fallback, and the document is fetched even if the epoch matches. The file is
+// written atomically with mode 0600; a missing or unreadable cache is the same as no cache.
+//
+// If src can't be reached at startup, NewEpochGetter serves the cached document, if there is one, and returns the
+// error otherwise. Failures after that go to the Errors() channel, and GetErr returns the error for omitted secrets
+// that haven't yet been fetched. Call Close to stop checking the epoch.
+func NewEpochGetter(src EpochSource, cachePath string, interval time.Duration) (*EpochGetter, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("config: epoch check interval must be positive, got %v", interval)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &EpochGetter{
+		src:      src,
+		path:     cachePath,
+		interval: interval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	cached, err := readEpochCache(cachePath)
+	if err != nil {
+		log.Printf("config: ignoring epoch cache: %v", err)
+	}
+	if cached != nil {
+		g.state.Store(cached)
+	}
+	if _, err := g.refresh(ctx); err != nil {
+		if cached == nil {
+			cancel()
+			return nil, err
+		}
+		g.Report(err)
+	}
+	go g.run(ctx)
+	return g, nil
+}
+
+func (g *EpochGetter) run(ctx context.Context) {
+	defer close(g.done)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(g.interval):
+		}
+		changed, err := g.refresh(ctx)
+		if ctx.Err() != nil {
+			return
+		} else if err != nil {
+			g.Report(err)
+		} else if changed {
+			g.Notify()
+		}
+	}
+}
+
+// refresh fetches the document if the epoch has moved on, or if the current values are missing omitted secrets.
+func (g *EpochGetter) refresh(ctx context.Context) (bool, error) {
+	epoch, err := g.src.Epoch(ctx)
+	if err != nil {
+		return false, g.fail(fmt.Errorf("config: reading the config epoch: %w", err))
+	}
+	current := g.state.Load()
+	if current != nil && current.Epoch == epoch && len(current.Omitted) == 0 {
+		g.err.Store(nil)
+		return false, nil
+	}
+	fetched, values, err := g.src.Fetch(ctx)
+	if err != nil {
+		return false, g.fail(fmt.Errorf("config: fetching config epoch %s: %w", epoch, err))
+	}
+	epoch = fetched
+	g.state.Store(&epochCache{Epoch: epoch, Values: values})
+	g.err.Store(nil)
+	if err := g.persist(epoch, values); err != nil {
+		g.Report(err)
+	}
+	return true, nil
+}
+
+func (g *EpochGetter) fail(err error) error {
+	g.err.Store(&err)
+	return err
+}
+
+// persist writes the document to the cache file, leaving out secrets.
+func (g *EpochGetter) persist(epoch string, values map[string]string) error {
+	cache := epochCache{Epoch: epoch, Values: make(map[string]string, len(values))}
+	for key, val := range values {
+		if g.IsSecret(key) {
+			cache.Omitted = append(cache.Omitted, key)
+		} else {
+			cache.Values[key] = val
+		}
+	}
+	sort.Strings(cache.Omitted)
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("config: writing epoch cache: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(g.path), filepath.Base(g.path)+".*")
+	if err != nil {
+		return fmt.Errorf("config: writing epoch cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("config: writing epoch cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("config: writing epoch cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), g.path); err != nil {
+		return fmt.Errorf("config: writing epoch cache: %w", err)
+	}
+	return nil
+}
+
+// readEpochCache returns the cache at path, or nil if there isn't one.
+func readEpochCache(path string) (*epochCache, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var cache epochCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if cache.Epoch == "" {
+		return nil, fmt.Errorf("%s: no epoch", path)
+	}
+	if cache.Values == nil {
+		cache.Values = make(map[string]string)
+	}
+	return &cache, nil
+}
+
+// Epoch : Return the epoch of the document being served.
+func (g *EpochGetter) Epoch() string {
+	return g.state.Load().Epoch
+}
+
+// IsSecret : Report whether key is a secret, and so kept out of the cache file.
+func (g *EpochGetter) IsSecret(key string) bool {
+	if isSecretKey(key) {
+		return true
+	}
+	sm, ok := g.src.(SecretMarker)
+	return ok && sm.IsSecret(key)
+}
+
+// Close : Stop checking the epoch, abandoning any request in flight. The Getter keeps serving the current document.
+func (g *EpochGetter) Close() error {
+	g.closeOnce.Do(func() {
+		g.cancel()
+		<-g.done
+	})
+	return nil
+}
+
+func (g *EpochGetter) current() Map {
+	return g.state.Load().Values
+}
+
+// GetErr : Return the value for key. A secret that was omitted from the cache and hasn't been fetched yet is the
+// error from the latest fetch; any other missing key is an error wrapping ErrKeyNotSet.
+func (g *EpochGetter) GetErr(key string) (string, error) {
+	state := g.state.Load()
+	if val, ok := state.Values[key]; ok {
+		return val, nil
+	}
+	if i := sort.SearchStrings(state.Omitted, key); i < len(state.Omitted) && state.Omitted[i] == key {
+		if err := g.err.Load(); err != nil {
+			return "", fmt.Errorf("config: %s isn't cached: %w", key, *err)
+		}
+		return "", fmt.Errorf("config: %s isn't cached and hasn't been fetched yet", key)
+	}
+	return "", fmt.Errorf("config: %s: %w", key, ErrKeyNotSet)
+}
+
+// Snapshot : Return the values of the current document, which won't change as new documents arrive.
+func (g *EpochGetter) Snapshot() Getter {
+	return g.current()
+}
+
+// Get : Return the value for key from the current document.
+func (g *EpochGetter) Get(key string) string {
+	return g.current().Get(key)
+}
+
+// Lookup : Return the value for key from the current document, and whether it's present.
+func (g *EpochGetter) Lookup(key string) (string, bool) {
+	return g.current().Lookup(key)
+}
+
+// Keys : Return the keys of the current document.
+func (g *EpochGetter) Keys() []string {
+	return g.current().Keys()
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (g *EpochGetter) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(g, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (g *EpochGetter) GetStrings(key string) []string {
+	return g.current().GetStrings(key)
+}
+
+// MustGet will panic if the key is not present or empty.
+func (g *EpochGetter) MustGet(key string) string {
+	return MustGet(g, key)
+}
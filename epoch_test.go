@@ -0,0 +1,171 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeEpochSource struct {
+	lock    sync.Mutex
+	epoch   int
+	values  map[string]string
+	down    bool
+	epochs  int
+	fetches int
+}
+
+func (f *fakeEpochSource) Epoch(ctx context.Context) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.epochs++
+	if f.down {
+		return "", errors.New("connection refused")
+	}
+	return fmt.Sprintf("e%d", f.epoch), nil
+}
+
+func (f *fakeEpochSource) Fetch(ctx context.Context) (string, map[string]string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.fetches++
+	if f.down {
+		return "", nil, errors.New("connection refused")
+	}
+	return fmt.Sprintf("e%d", f.epoch), copyValues(f.values), nil
+}
+
+func (f *fakeEpochSource) publish(values map[string]string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.epoch++
+	f.values = values
+}
+
+func (f *fakeEpochSource) stats() (int, int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.epochs, f.fetches
+}
+
+func TestEpochGetter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.cache")
+	src := &fakeEpochSource{}
+	src.publish(map[string]string{"DB_HOST": "db1"})
+	g, err := NewEpochGetter(src, path, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := g.Get("DB_HOST"); v != "db1" || g.Epoch() != "e1" {
+		t.Errorf("Expected 'db1' at epoch e1, got '%s' at %s", v, g.Epoch())
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, fetches := src.stats(); fetches != 1 {
+		t.Errorf("An unchanged epoch should not be fetched again; got %d fetches", fetches)
+	}
+
+	changes := g.Watch()
+	src.publish(map[string]string{"DB_HOST": "db2"})
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a change notification")
+	}
+	if v := g.Get("DB_HOST"); v != "db2" {
+		t.Errorf("Expected 'db2', got '%s'", v)
+	}
+	g.Close()
+
+	// A restart with the epoch unchanged is served from the cache file.
+	restarted := &fakeEpochSource{epoch: src.epoch}
+	g, err = NewEpochGetter(restarted, path, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	if v := g.Get("DB_HOST"); v != "db2" {
+		t.Errorf("Expected the cached 'db2', got '%s'", v)
+	}
+	if epochs, fetches := restarted.stats(); epochs != 1 || fetches != 0 {
+		t.Errorf("Expected one epoch check and no fetch, got %d and %d", epochs, fetches)
+	}
+}
+
+func TestEpochGetterSecrets(t *testing.T) {
+	SetSecretPredicate(func(key string) bool { return key == "DB_PASSWORD" })
+	defer SetSecretPredicate(nil)
+	path := filepath.Join(t.TempDir(), "config.cache")
+	src := &fakeEpochSource{}
+	src.publish(map[string]string{"DB_HOST": "db1", "DB_PASSWORD": "hunter2"})
+	g, err := NewEpochGetter(src, path, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g.Close()
+	if v := g.Get("DB_PASSWORD"); v != "hunter2" {
+		t.Errorf("Secrets should be served from memory, got '%s'", v)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") || !strings.Contains(string(data), `"omitted":["DB_PASSWORD"]`) {
+		t.Errorf("Secrets should be omitted from the cache file: %s", data)
+	}
+
+	// With secrets held back the cache can't stand in for the document, so a restart fetches it,
+	// and falls back to the cache if the source is down.
+	restarted := &fakeEpochSource{epoch: src.epoch, values: src.values}
+	g, err = NewEpochGetter(restarted, path, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g.Close()
+	if _, fetches := restarted.stats(); fetches != 1 || g.Get("DB_PASSWORD") != "hunter2" {
+		t.Errorf("Expected the document to be fetched for its secrets; %d fetches", fetches)
+	}
+	down := &fakeEpochSource{down: true}
+	g, err = NewEpochGetter(down, path, time.Hour)
+	if err != nil {
+		t.Fatalf("The cache should be served when the source is down; got %v", err)
+	}
+	defer g.Close()
+	if v := g.Get("DB_HOST"); v != "db1" {
+		t.Errorf("Expected the cached 'db1', got '%s'", v)
+	}
+	if _, err := g.GetErr("DB_PASSWORD"); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("Expected the fetch error for an omitted secret, got %v", err)
+	}
+	if _, err := g.GetErr("MISSING"); !errors.Is(err, ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+}
+
+func TestEpochGetterErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.cache")
+	if _, err := NewEpochGetter(&fakeEpochSource{down: true}, path, time.Hour); err == nil {
+		t.Error("Expected an error with no source and no cache")
+	}
+	if _, err := NewEpochGetter(&fakeEpochSource{}, path, 0); err == nil {
+		t.Error("Expected an error for a zero interval")
+	}
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	src := &fakeEpochSource{}
+	src.publish(map[string]string{"A": "1"})
+	g, err := NewEpochGetter(src, path, time.Hour)
+	if err != nil {
+		t.Fatalf("A corrupt cache should be ignored; got %v", err)
+	}
+	defer g.Close()
+	if v := g.Get("A"); v != "1" {
+		t.Errorf("Expected '1', got '%s'", v)
+	}
+}
@@ -0,0 +1,239 @@
+// Package etcdconfig provides a config.Getter over the keys under a prefix in etcd, with consistent multi-key reads
+// for config that's updated several keys at a time. It keeps the etcd client dependency out of the root package.
+package etcdconfig
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/efixler/config"
+)
+
+// requestTimeout bounds each read from etcd.
+const requestTimeout = 30 * time.Second
+
+// rewatchDelay is how long the Getter waits before reloading after its watch fails.
+const rewatchDelay = time.Second
+
+// Client is the part of *clientv3.Client the Getter uses.
+type Client interface {
+	Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error)
+	Txn(ctx context.Context) clientv3.Txn
+	Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan
+}
+
+type snapshot struct {
+	values   config.Map
+	revision int64
+}
+
+// Getter serves the keys under a prefix from an in-memory cache that an etcd watch keeps current. See NewEtcdGetter.
+type Getter struct {
+	config.Notifier
+	config.ErrorReporter
+	client    Client
+	prefix    string
+	current   atomic.Pointer[snapshot]
+	err       atomic.Pointer[error]
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewEtcdGetter : Load the keys under prefix and return a Getter where Get(key) is the value of prefix+key.
+// A watch on the prefix, starting from the revision of the initial read, keeps the values current; Watch() channels
+// are notified after each change. If the watch fails (its revision is compacted away, or there's no leader) the
+// error goes to the Errors() channel, and the Getter reloads the prefix and watches again, returning the error from
+// GetErr until it has. Call Close when you're done with the Getter.
+//
+// Get and the other lookups read the cache, so they're eventually consistent: a reader can see some keys of a
+// multi-key update before the rest arrive. Use GetConsistent to read related keys together.
+func NewEtcdGetter(client Client, prefix string) (*Getter, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &Getter{
+		client: client,
+		prefix: prefix,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	if err := g.load(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+	go g.run(ctx)
+	return g, nil
+}
+
+// load reads every key under the prefix, replacing the cache.
+func (g *Getter) load(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	resp, err := g.client.Get(ctx, g.prefix, clientv3.WithPrefix())
+	if err != nil {
+		return g.fail(fmt.Errorf("etcdconfig: reading %s: %w", g.prefix, err))
+	}
+	values := make(config.Map, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		values[strings.TrimPrefix(string(kv.Key), g.prefix)] = string(kv.Value)
+	}
+	g.current.Store(&snapshot{values: values, revision: resp.Header.Revision})
+	g.err.Store(nil)
+	return nil
+}
+
+func (g *Getter) run(ctx context.Context) {
+	defer close(g.done)
+	for {
+		err := g.follow(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		g.Report(g.fail(err))
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(rewatchDelay):
+			}
+			err := g.load(ctx)
+			if err == nil {
+				break
+			} else if ctx.Err() != nil {
+				return
+			}
+			g.Report(err)
+		}
+		g.Notify()
+	}
+}
+
+// follow applies watch events to the cache until the watch ends, returning why it did.
+func (g *Getter) follow(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+	defer cancel()
+	watch := g.client.Watch(ctx, g.prefix, clientv3.WithPrefix(), clientv3.WithRev(g.current.Load().revision+1))
+	for resp := range watch {
+		if err := resp.Err(); err != nil {
+			return fmt.Errorf("etcdconfig: watching %s: %w", g.prefix, err)
+		}
+		if len(resp.Events) == 0 {
+			continue
+		}
+		prev := g.current.Load()
+		values := maps.Clone(prev.values)
+		for _, ev := range resp.Events {
+			key := strings.TrimPrefix(string(ev.Kv.Key), g.prefix)
+			if ev.Type == clientv3.EventTypeDelete {
+				delete(values, key)
+			} else {
+				values[key] = string(ev.Kv.Value)
+			}
+		}
+		g.current.Store(&snapshot{values: values, revision: resp.Header.Revision})
+		g.Notify()
+	}
+	return fmt.Errorf("etcdconfig: watch of %s ended", g.prefix)
+}
+
+// GetConsistent : Read keys from etcd together, returning the values as of a single revision, so that a multi-key
+// update is seen either entirely or not at all. The reads are the Then branch of one etcd transaction, which etcd
+// serves at one revision of its store, and they're linearizable: the result is at least as new as any write
+// acknowledged before the call. Keys that aren't set are left out of the map. GetConsistent goes to etcd on every
+// call, bypassing the cache; the error is etcd's, wrapped.
+func (g *Getter) GetConsistent(keys ...string) (map[string]string, error) {
+	ops := make([]clientv3.Op, len(keys))
+	for i, key := range keys {
+		ops[i] = clientv3.OpGet(g.prefix + key)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	resp, err := g.client.Txn(ctx).Then(ops...).Commit()
+	if err != nil {
+		return nil, fmt.Errorf("etcdconfig: reading %s: %w", strings.Join(keys, ", "), err)
+	}
+	rval := make(map[string]string, len(keys))
+	for i, r := range resp.Responses {
+		if kvs := r.GetResponseRange().GetKvs(); len(kvs) > 0 {
+			rval[keys[i]] = string(kvs[0].Value)
+		}
+	}
+	return rval, nil
+}
+
+func (g *Getter) fail(err error) error {
+	g.err.Store(&err)
+	return err
+}
+
+// Close : Stop watching etcd. The Getter keeps serving the values it has.
+func (g *Getter) Close() error {
+	g.closeOnce.Do(func() {
+		g.cancel()
+		<-g.done
+	})
+	return nil
+}
+
+// Revision : Return the etcd revision the cached values are current as of.
+func (g *Getter) Revision() int64 {
+	return g.current.Load().revision
+}
+
+func (g *Getter) values() config.Map {
+	return g.current.Load().values
+}
+
+// GetErr : Return the value for key, the error from the watch if it has failed and the prefix hasn't been reloaded
+// yet, or an error wrapping config.ErrKeyNotSet.
+func (g *Getter) GetErr(key string) (string, error) {
+	if err := g.err.Load(); err != nil {
+		return "", *err
+	}
+	val, ok := g.values()[key]
+	if !ok {
+		return "", fmt.Errorf("etcdconfig: %s: %w", key, config.ErrKeyNotSet)
+	}
+	return val, nil
+}
+
+// Lookup : Return the cached value for key, and whether it's set.
+func (g *Getter) Lookup(key string) (string, bool) {
+	return g.values().Lookup(key)
+}
+
+// Keys : Return the cached keys.
+func (g *Getter) Keys() []string {
+	return g.values().Keys()
+}
+
+// Snapshot : Return the cached values as of one revision, which won't change as the watch delivers updates.
+func (g *Getter) Snapshot() config.Getter {
+	return g.values()
+}
+
+// Get : Return the cached value for key.
+func (g *Getter) Get(key string) string {
+	return g.values().Get(key)
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (g *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(g, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (g *Getter) GetStrings(key string) []string {
+	return config.SplitStrings(g.Get(key))
+}
+
+// MustGet will panic if the key is not present or empty.
+func (g *Getter) MustGet(key string) string {
+	return config.MustGet(g, key)
+}
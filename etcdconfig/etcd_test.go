@@ -0,0 +1,177 @@
+package etcdconfig
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/efixler/config"
+)
+
+// fakeEtcd is an in-memory store with revisions and a single watch.
+type fakeEtcd struct {
+	lock     sync.Mutex
+	values   map[string]string
+	revision int64
+	watches  chan chan clientv3.WatchResponse
+	txnErr   error
+}
+
+func newFakeEtcd(values map[string]string) *fakeEtcd {
+	return &fakeEtcd{values: values, revision: 10, watches: make(chan chan clientv3.WatchResponse, 4)}
+}
+
+func (f *fakeEtcd) Get(_ context.Context, key string, _ ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	resp := &clientv3.GetResponse{Header: &pb.ResponseHeader{Revision: f.revision}}
+	for k, v := range f.values {
+		resp.Kvs = append(resp.Kvs, &mvccpb.KeyValue{Key: []byte(k), Value: []byte(v)})
+	}
+	return resp, nil
+}
+
+func (f *fakeEtcd) Watch(ctx context.Context, _ string, _ ...clientv3.OpOption) clientv3.WatchChan {
+	ch := make(chan clientv3.WatchResponse, 4)
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	f.watches <- ch
+	return ch
+}
+
+func (f *fakeEtcd) Txn(context.Context) clientv3.Txn {
+	return &fakeTxn{f: f}
+}
+
+// put changes keys at a new revision, returning the watch response for the change.
+func (f *fakeEtcd) put(values map[string]string) clientv3.WatchResponse {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.revision++
+	resp := clientv3.WatchResponse{Header: &pb.ResponseHeader{Revision: f.revision}}
+	for k, v := range values {
+		f.values[k] = v
+		resp.Events = append(resp.Events, &clientv3.Event{Kv: &mvccpb.KeyValue{Key: []byte(k), Value: []byte(v)}})
+	}
+	return resp
+}
+
+type fakeTxn struct {
+	clientv3.Txn
+	f   *fakeEtcd
+	ops []clientv3.Op
+}
+
+func (t *fakeTxn) Then(ops ...clientv3.Op) clientv3.Txn {
+	t.ops = ops
+	return t
+}
+
+func (t *fakeTxn) Commit() (*clientv3.TxnResponse, error) {
+	t.f.lock.Lock()
+	defer t.f.lock.Unlock()
+	if t.f.txnErr != nil {
+		return nil, t.f.txnErr
+	}
+	resp := &clientv3.TxnResponse{Header: &pb.ResponseHeader{Revision: t.f.revision}, Succeeded: true}
+	for _, op := range t.ops {
+		rr := &pb.RangeResponse{}
+		if v, ok := t.f.values[string(op.KeyBytes())]; ok {
+			rr.Kvs = []*mvccpb.KeyValue{{Key: op.KeyBytes(), Value: []byte(v)}}
+		}
+		resp.Responses = append(resp.Responses, &pb.ResponseOp{Response: &pb.ResponseOp_ResponseRange{ResponseRange: rr}})
+	}
+	return resp, nil
+}
+
+func waitFor(t *testing.T, changes <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a change notification")
+	}
+}
+
+func TestEtcdGetter(t *testing.T) {
+	f := newFakeEtcd(map[string]string{"/app/db.host": "db1", "/app/db.port": "5432"})
+	g, err := NewEtcdGetter(f, "/app/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	watch := <-f.watches
+	if v := g.Get("db.host"); v != "db1" {
+		t.Errorf("Expected 'db1', got '%s'", v)
+	}
+	if r := g.Revision(); r != 10 {
+		t.Errorf("Expected revision 10, got %d", r)
+	}
+
+	changes := g.Watch()
+	update := f.put(map[string]string{"/app/db.host": "db2"})
+	update.Events = append(update.Events, &clientv3.Event{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: []byte("/app/db.port")}})
+	watch <- update
+	waitFor(t, changes)
+	if v := g.Get("db.host"); v != "db2" {
+		t.Errorf("Expected 'db2', got '%s'", v)
+	}
+	if _, err := g.GetErr("db.port"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet for a deleted key, got %v", err)
+	}
+	if r := g.Revision(); r != 11 {
+		t.Errorf("Expected revision 11, got %d", r)
+	}
+
+	// A compacted watch is reported, and the Getter reloads and watches again.
+	f.put(map[string]string{"/app/db.port": "6432"})
+	watch <- clientv3.WatchResponse{Header: &pb.ResponseHeader{}, CompactRevision: 11}
+	if err := <-g.Errors(); !errors.Is(err, rpctypes.ErrCompacted) {
+		t.Errorf("Expected the compaction to be reported, got %v", err)
+	}
+	waitFor(t, changes)
+	if v := g.Get("db.port"); v != "6432" {
+		t.Errorf("Expected the reloaded '6432', got '%s'", v)
+	}
+	if _, err := g.GetErr("db.port"); err != nil {
+		t.Errorf("Unexpected error after reloading: %v", err)
+	}
+	<-f.watches
+}
+
+func TestEtcdGetConsistent(t *testing.T) {
+	f := newFakeEtcd(map[string]string{"/app/db.host": "db1", "/app/db.port": "5432"})
+	g, err := NewEtcdGetter(f, "/app/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	<-f.watches
+
+	// The write hasn't reached the cache, but a consistent read sees all of it.
+	f.put(map[string]string{"/app/db.host": "db2", "/app/db.port": "6432"})
+	values, err := g.GetConsistent("db.host", "db.port", "db.missing")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(values) != 2 || values["db.host"] != "db2" || values["db.port"] != "6432" {
+		t.Errorf("Expected both new values, got %v", values)
+	}
+	if v := g.Get("db.host"); v != "db1" {
+		t.Errorf("Expected the cache to still hold 'db1', got '%s'", v)
+	}
+
+	f.txnErr = errors.New("etcdserver: request timed out")
+	if _, err := g.GetConsistent("db.host"); !errors.Is(err, f.txnErr) {
+		t.Errorf("Expected the etcd error, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EvalBool : Evaluate a small boolean expression against config values, for feature gates declared in config,
+// e.g. FEATURE_X="REGION==us && TIER!=free". The grammar is intentionally tiny:
+//
+//	expr       = and { "||" and }
+//	and        = term { "&&" term }
+//	term       = "(" expr ")" | comparison
+//	comparison = KEY [ ( "==" | "!=" ) VALUE ]
+//
+// KEY is a bare word naming a config key, looked up with g.Get(). VALUE is a literal: a bare word or a
+// "double-quoted" string (use the quoted form for the empty string or for values with spaces or operators).
+// Bare words may contain letters, digits and _ . - : /. A lone KEY is true if its value is true
+// according to strconv.ParseBool, and false otherwise (including when unset). && binds tighter than ||.
+//
+// A malformed expression returns a *ParseError with the byte offset of the offending token.
+func EvalBool(g Getter, expr string) (bool, error) {
+	p := &exprParser{g: g, src: expr}
+	p.next()
+	rval, err := p.parseOr()
+	if err != nil {
+		return false, err
+	}
+	if p.err != nil {
+		return false, p.err
+	}
+	if p.tok.kind != tokEOF {
+		return false, p.errorf("unexpected %q", p.tok.text)
+	}
+	return rval, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type exprParser struct {
+	g   Getter
+	src string
+	pos int
+	tok token
+	err error
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '.' || c == '-' || c == ':' || c == '/' ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// next advances to the next token, recording a lexing error in p.err.
+func (p *exprParser) next() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+	switch c := p.src[p.pos]; {
+	case c == '(' || c == ')':
+		p.pos++
+		p.tok = token{kind: tokOp, text: p.src[start:p.pos], pos: start}
+	case strings.HasPrefix(p.src[p.pos:], "==") || strings.HasPrefix(p.src[p.pos:], "!=") ||
+		strings.HasPrefix(p.src[p.pos:], "&&") || strings.HasPrefix(p.src[p.pos:], "||"):
+		p.pos += 2
+		p.tok = token{kind: tokOp, text: p.src[start:p.pos], pos: start}
+	case c == '"':
+		end := strings.IndexByte(p.src[p.pos+1:], '"')
+		if end < 0 {
+			p.err = newParseError(p.src, start, "unterminated string")
+			p.tok = token{kind: tokEOF, pos: start}
+			return
+		}
+		p.pos += end + 2
+		p.tok = token{kind: tokString, text: p.src[start+1 : p.pos-1], pos: start}
+	case isWordByte(c):
+		for p.pos < len(p.src) && isWordByte(p.src[p.pos]) {
+			p.pos++
+		}
+		p.tok = token{kind: tokWord, text: p.src[start:p.pos], pos: start}
+	default:
+		p.err = newParseError(p.src, start, "unexpected character")
+		p.tok = token{kind: tokEOF, pos: start}
+	}
+}
+
+// errorf reports a problem at the current token (or the pending lexing error, if there is one).
+func (p *exprParser) errorf(format string, args ...any) error {
+	if p.err != nil {
+		return p.err
+	}
+	if p.tok.kind == tokEOF {
+		return newParseError(p.src, p.tok.pos, "unexpected end of expression")
+	}
+	return newParseError(p.src, p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *exprParser) parseOr() (bool, error) {
+	rval, err := p.parseAnd()
+	for err == nil && p.tok.kind == tokOp && p.tok.text == "||" {
+		p.next()
+		var rhs bool
+		rhs, err = p.parseAnd()
+		rval = rval || rhs
+	}
+	return rval, err
+}
+
+func (p *exprParser) parseAnd() (bool, error) {
+	rval, err := p.parseTerm()
+	for err == nil && p.tok.kind == tokOp && p.tok.text == "&&" {
+		p.next()
+		var rhs bool
+		rhs, err = p.parseTerm()
+		rval = rval && rhs
+	}
+	return rval, err
+}
+
+func (p *exprParser) parseTerm() (bool, error) {
+	if p.tok.kind == tokOp && p.tok.text == "(" {
+		p.next()
+		rval, err := p.parseOr()
+		if err != nil {
+			return false, err
+		}
+		if p.tok.kind != tokOp || p.tok.text != ")" {
+			return false, p.errorf("expected ) but found %q", p.tok.text)
+		}
+		p.next()
+		return rval, p.err
+	}
+	if p.tok.kind != tokWord {
+		return false, p.errorf("expected a key but found %q", p.tok.text)
+	}
+	val := p.g.Get(p.tok.text)
+	p.next()
+	if p.tok.kind != tokOp || (p.tok.text != "==" && p.tok.text != "!=") {
+		b, _ := strconv.ParseBool(val)
+		return b, p.err
+	}
+	op := p.tok.text
+	p.next()
+	if p.tok.kind != tokWord && p.tok.kind != tokString {
+		return false, p.errorf("expected a value but found %q", p.tok.text)
+	}
+	literal := p.tok.text
+	p.next()
+	return (val == literal) == (op == "=="), p.err
+}
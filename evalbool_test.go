@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEvalBool(t *testing.T) {
+	g := Map{"REGION": "us", "TIER": "pro", "BETA": "true", "NAME": "a b"}
+	tests := []struct {
+		expr     string
+		expected bool
+	}{
+		{"REGION==us && TIER!=free", true},
+		{"REGION==eu || TIER==pro", true},
+		{"REGION==eu || TIER==pro && BETA", true},
+		{"(REGION==eu || TIER==pro) && MISSING", false},
+		{`NAME=="a b"`, true},
+		{`MISSING==""`, true},
+		{"BETA", true},
+		{"TIER", false},
+	}
+	for _, test := range tests {
+		got, err := EvalBool(g, test.expr)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.expr, err)
+		} else if got != test.expected {
+			t.Errorf("%s: expected %t, got %t", test.expr, test.expected, got)
+		}
+	}
+}
+
+func TestEvalBoolErrors(t *testing.T) {
+	tests := []struct {
+		expr   string
+		offset int
+	}{
+		{"REGION==", 8},
+		{"(REGION==us", 11},
+		{"REGION==us TIER", 11},
+		{"REGION == us &| x", 13},
+		{`NAME=="unterminated`, 6},
+		{"&& x", 0},
+		{"(REGION==us) #junk", 13},
+		{"(A) $", 4},
+		{"(A) && (A) @", 11},
+	}
+	for _, test := range tests {
+		_, err := EvalBool(Map{}, test.expr)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("%s: expected a *ParseError, got %v", test.expr, err)
+		} else if perr.Offset != test.offset {
+			t.Errorf("%s: expected offset %d, got %d (%v)", test.expr, test.offset, perr.Offset, err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// EventType says how a key changed in a ConfigEvent.
+type EventType int
+
+const (
+	// Added : The key wasn't set before the reload.
+	Added EventType = iota
+	// Changed : The key's value is different after the reload.
+	Changed
+	// Removed : The key isn't set after the reload.
+	Removed
+)
+
+var eventTypeNames = [...]string{Added: "added", Changed: "changed", Removed: "removed"}
+
+func (t EventType) String() string {
+	if t < 0 || int(t) >= len(eventTypeNames) {
+		return "unknown"
+	}
+	return eventTypeNames[t]
+}
+
+// ConfigEvent describes the change to one key, as sent by Events().
+type ConfigEvent struct {
+	Type      EventType
+	Key       string
+	OldValue  string
+	NewValue  string
+	Timestamp time.Time
+}
+
+// eventsBuffer is the capacity of the channel returned by Events().
+const eventsBuffer = 64
+
+// Events : Return a channel of per-key change events for g, for code that reacts to particular keys changing,
+// like re-dialing when DB_HOST does. g must be a Watcher and a Lister. On each Watch() notification the current
+// values (taken from one Snapshot() if g is a Snapshotter) are compared with the previous ones, and an event is
+// sent for each key that was added, changed or removed, in key order.
+//
+// The channel is buffered (64 events). When it's full the oldest event is dropped to make room, so a slow consumer
+// loses history rather than holding up anything else, and always ends up with the most recent changes. Watch()
+// notifications are coalesced, so a key that changes and changes back between checks yields no event.
+// The channel is closed when ctx is done.
+func Events(ctx context.Context, g Getter) (<-chan ConfigEvent, error) {
+	w, ok := g.(Watcher)
+	if !ok {
+		return nil, errors.New("config: events require a Getter that implements Watcher")
+	}
+	if _, ok := g.(Lister); !ok {
+		return nil, errors.New("config: events require a Getter that implements Lister")
+	}
+	changes := w.Watch()
+	prev := currentValues(g)
+	ch := make(chan ConfigEvent, eventsBuffer)
+	go func() {
+		defer close(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changes:
+			}
+			next := currentValues(g)
+			for _, ev := range diffValues(prev, next, time.Now()) {
+				sendDropOldest(ch, ev)
+			}
+			prev = next
+		}
+	}()
+	return ch, nil
+}
+
+// currentValues reads every key of g, from a snapshot if g can provide one.
+func currentValues(g Getter) map[string]string {
+	if s, ok := g.(Snapshotter); ok {
+		g = s.Snapshot()
+	}
+	var keys []string
+	if l, ok := g.(Lister); ok {
+		keys = l.Keys()
+	}
+	rval := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if v, ok := Lookup(g, key); ok {
+			rval[key] = v
+		}
+	}
+	return rval
+}
+
+func diffValues(prev, next map[string]string, at time.Time) []ConfigEvent {
+	var rval []ConfigEvent
+	for key, nv := range next {
+		if ov, ok := prev[key]; !ok {
+			rval = append(rval, ConfigEvent{Type: Added, Key: key, NewValue: nv, Timestamp: at})
+		} else if ov != nv {
+			rval = append(rval, ConfigEvent{Type: Changed, Key: key, OldValue: ov, NewValue: nv, Timestamp: at})
+		}
+	}
+	for key, ov := range prev {
+		if _, ok := next[key]; !ok {
+			rval = append(rval, ConfigEvent{Type: Removed, Key: key, OldValue: ov, Timestamp: at})
+		}
+	}
+	sort.Slice(rval, func(i, j int) bool { return rval[i].Key < rval[j].Key })
+	return rval
+}
+
+// sendDropOldest sends ev on ch, discarding the oldest buffered event if ch is full.
+func sendDropOldest(ch chan ConfigEvent, ev ConfigEvent) {
+	for {
+		select {
+		case ch <- ev:
+			return
+		default:
+		}
+		select {
+		case <-ch:
+		default:
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func nextEvent(t *testing.T, ch <-chan ConfigEvent) ConfigEvent {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected an event")
+	}
+	return ConfigEvent{}
+}
+
+func TestEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := NewMutableGetter(Map{"DB_HOST": "db1", "MODE": "blue"})
+	ch, err := Events(ctx, m)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m.Set("DB_HOST", "db2")
+	ev := nextEvent(t, ch)
+	if ev.Type != Changed || ev.Key != "DB_HOST" || ev.OldValue != "db1" || ev.NewValue != "db2" || ev.Timestamp.IsZero() {
+		t.Errorf("Unexpected event %+v", ev)
+	}
+	m.Set("NEW", "x")
+	if ev := nextEvent(t, ch); ev.Type != Added || ev.Key != "NEW" || ev.NewValue != "x" {
+		t.Errorf("Unexpected event %+v", ev)
+	}
+	m.Unset("NEW")
+	if ev := nextEvent(t, ch); ev.Type != Removed || ev.Key != "NEW" || ev.OldValue != "x" || ev.Type.String() != "removed" {
+		t.Errorf("Unexpected event %+v", ev)
+	}
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("Expected no more events")
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Expected the channel to close on cancellation")
+	}
+	if _, err := Events(ctx, Map{}); err == nil {
+		t.Error("Expected an error for a Getter that isn't a Watcher")
+	}
+}
+
+func TestSendDropOldest(t *testing.T) {
+	ch := make(chan ConfigEvent, 2)
+	for _, key := range []string{"A", "B", "C"} {
+		sendDropOldest(ch, ConfigEvent{Key: key})
+	}
+	if a, b := <-ch, <-ch; a.Key != "B" || b.Key != "C" {
+		t.Errorf("Expected the oldest event to be dropped; got %s, %s", a.Key, b.Key)
+	}
+}
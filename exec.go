@@ -0,0 +1,42 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// NewExecGetter : Run an external command once and return a Getter holding the KEY=VALUE lines it
+// prints to stdout (blank lines and # comments are skipped). This is the escape hatch for config sources
+// that have a CLI but no Go client, such as git-credential-style helpers.
+//
+// A non-zero exit is returned as an error that includes the command's stderr.
+//
+// Be careful with this one: the command runs with the privileges and environment of the calling process,
+// so name and args must never come from untrusted input, and the command's stderr (which ends up in the
+// error) should not echo secrets.
+func NewExecGetter(name string, args ...string) (Getter, error) {
+	return NewExecGetterContext(context.Background(), name, args...)
+}
+
+// NewExecGetterContext : Same as NewExecGetter, but the command is killed if ctx is done before it completes,
+// e.g. when it's a context.WithTimeout().
+func NewExecGetterContext(ctx context.Context, name string, args ...string) (Getter, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("config: running %s: %w: %s", name, err, msg)
+		}
+		return nil, fmt.Errorf("config: running %s: %w", name, err)
+	}
+	values, err := parseKeyValues(&stdout)
+	if err != nil {
+		return nil, fmt.Errorf("config: parsing output of %s: %w", name, err)
+	}
+	return Map(values), nil
+}
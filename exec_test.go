@@ -0,0 +1,36 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecGetter(t *testing.T) {
+	c, err := NewExecGetter("sh", "-c", `printf '# creds\nUSER=bob\n\nexport TOKEN="abc=123"\n'`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := c.Get("USER"); v != "bob" {
+		t.Errorf("Expected 'bob', got '%s'", v)
+	}
+	if v := c.Get("TOKEN"); v != "abc=123" {
+		t.Errorf("Expected 'abc=123', got '%s'", v)
+	}
+}
+
+func TestExecGetterErrors(t *testing.T) {
+	_, err := NewExecGetter("sh", "-c", "echo no credentials >&2; exit 3")
+	if err == nil || !strings.Contains(err.Error(), "no credentials") {
+		t.Errorf("Expected an error including stderr, got %v", err)
+	}
+	if _, err = NewExecGetter("sh", "-c", "echo not a pair"); err == nil {
+		t.Error("Expected a parse error for a line without '='")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err = NewExecGetterContext(ctx, "sleep", "5"); err == nil {
+		t.Error("Expected an error when the command times out")
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// failoverCooldown is how long Failover() skips a backend after it fails.
+const failoverCooldown = 30 * time.Second
+
+// Failover : Compose region-local (or otherwise redundant) backends into one Getter that fails over on error.
+// GetErr and GetContext try the getters in priority order and return the first result that isn't an error;
+// they only fail, with every backend's error joined, when all of them do. A key that isn't set (an error wrapping
+// ErrKeyNotSet) is an answer from a working backend, not a failure, so it doesn't fail over: unlike Merge(),
+// which falls through on missing values, Failover() expects every backend to hold the same config.
+//
+// Health tracking: a backend that returns an error is marked down and skipped for 30 seconds, so a dead primary
+// doesn't add its timeout to every lookup; after that it's tried again, in its normal priority order, and a success
+// marks it healthy. If every backend is down they're all tried anyway. Get returns "" (and logs) when all fail.
+func Failover(getters ...Getter) Getter {
+	return &failover{getters: getters, downUntil: make([]time.Time, len(getters)), now: time.Now}
+}
+
+type failover struct {
+	getters   []Getter
+	lock      sync.Mutex
+	downUntil []time.Time
+	now       func() time.Time
+}
+
+// order returns the indexes of the backends to try: the healthy ones, then the ones that are down.
+func (f *failover) order() []int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	now := f.now()
+	rval := make([]int, 0, len(f.getters))
+	var down []int
+	for i, until := range f.downUntil {
+		if now.Before(until) {
+			down = append(down, i)
+		} else {
+			rval = append(rval, i)
+		}
+	}
+	return append(rval, down...)
+}
+
+func (f *failover) mark(i int, err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err == nil {
+		f.downUntil[i] = time.Time{}
+	} else {
+		f.downUntil[i] = f.now().Add(failoverCooldown)
+	}
+}
+
+func (f *failover) try(key string, get func(Getter) (string, error)) (string, error) {
+	var errs []error
+	for _, i := range f.order() {
+		val, err := get(f.getters[i])
+		if err != nil && !errors.Is(err, ErrKeyNotSet) {
+			f.mark(i, err)
+			errs = append(errs, err)
+			continue
+		}
+		f.mark(i, nil)
+		return val, err
+	}
+	return "", fmt.Errorf("config: all backends failed for %s: %w", key, errors.Join(errs...))
+}
+
+func (f *failover) GetErr(key string) (string, error) {
+	return f.try(key, func(g Getter) (string, error) { return GetErr(g, key) })
+}
+
+func (f *failover) GetContext(ctx context.Context, key string) (string, error) {
+	return f.try(key, func(g Getter) (string, error) { return GetContext(ctx, g, key) })
+}
+
+func (f *failover) Get(key string) string {
+	val, err := f.GetErr(key)
+	if err != nil && !errors.Is(err, ErrKeyNotSet) {
+		log.Print(err)
+	}
+	return val
+}
+
+func (f *failover) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(f, key, dflt)
+}
+
+func (f *failover) GetStrings(key string) []string {
+	return SplitStrings(f.Get(key))
+}
+
+func (f *failover) MustGet(key string) string {
+	return MustGet(f, key)
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type regionGetter struct {
+	Map
+	down  bool
+	calls int
+}
+
+func (r *regionGetter) GetErr(key string) (string, error) {
+	r.calls++
+	if r.down {
+		return "", errors.New("region unavailable")
+	}
+	if v, ok := r.Map[key]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("region: %s: %w", key, ErrKeyNotSet)
+}
+
+func TestFailover(t *testing.T) {
+	now := time.Now()
+	primary := &regionGetter{Map: Map{"HOST": "east"}, down: true}
+	secondary := &regionGetter{Map: Map{"HOST": "west"}}
+	g := Failover(primary, secondary)
+	g.(*failover).now = func() time.Time { return now }
+
+	if v, err := GetErr(g, "HOST"); err != nil || v != "west" {
+		t.Errorf("Expected failover to 'west', got '%s' (%v)", v, err)
+	}
+	if _, err := GetErr(g, "MISSING"); !errors.Is(err, ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet for a missing key, got %v", err)
+	}
+	if primary.calls != 1 || secondary.calls != 2 {
+		t.Errorf("Expected the down primary to be skipped; got %d primary and %d secondary calls", primary.calls, secondary.calls)
+	}
+
+	secondary.down = true
+	_, err := GetErr(g, "HOST")
+	if err == nil || !strings.Contains(err.Error(), "region unavailable") {
+		t.Errorf("Expected every backend's error, got %v", err)
+	}
+	if primary.calls != 2 {
+		t.Errorf("Expected the primary to be tried when every backend is down, got %d calls", primary.calls)
+	}
+	if v := g.GetOrDefault("HOST", "local"); v != "local" {
+		t.Errorf("Expected the default when every backend fails, got '%s'", v)
+	}
+
+	primary.down, secondary.down = false, false
+	now = now.Add(failoverCooldown)
+	if v := g.Get("HOST"); v != "east" {
+		t.Errorf("Expected the primary to be used again after the cooldown, got '%s'", v)
+	}
+}
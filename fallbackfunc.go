@@ -0,0 +1,41 @@
+package config
+
+// WithFallbackFunc : Wrap a Getter so that, when it returns an empty value, fn is consulted as a last resort and its
+// value used if fn returns true. This is the escape hatch for the key that needs special logic: deriving a
+// default from other keys, calling an API, and so on.
+//
+// fn only runs when the wrapped Getter's result is empty. GetStrings applies it before splitting, so the
+// fallback value is split like any other.
+func WithFallbackFunc(g Getter, fn func(key string) (string, bool)) Getter {
+	return &fallbackFunc{g: g, fn: fn}
+}
+
+type fallbackFunc struct {
+	g  Getter
+	fn func(key string) (string, bool)
+}
+
+func (f *fallbackFunc) Get(key string) string {
+	if v := f.g.Get(key); v != "" {
+		return v
+	}
+	if v, ok := f.fn(key); ok {
+		return v
+	}
+	return ""
+}
+
+func (f *fallbackFunc) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(f, key, dflt)
+}
+
+func (f *fallbackFunc) GetStrings(key string) []string {
+	if v := f.g.Get(key); v != "" {
+		return f.g.GetStrings(key)
+	}
+	return SplitStrings(f.Get(key))
+}
+
+func (f *fallbackFunc) MustGet(key string) string {
+	return MustGet(f, key)
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithFallbackFunc(t *testing.T) {
+	base := Map{"HOST": "db", "PORT": "5432"}
+	calls := 0
+	c := WithFallbackFunc(base, func(key string) (string, bool) {
+		calls++
+		switch key {
+		case "DSN":
+			return base.Get("HOST") + ":" + base.Get("PORT"), true
+		case "REPLICAS":
+			return "r1, r2", true
+		}
+		return "", false
+	})
+	if v := c.Get("HOST"); v != "db" || calls != 0 {
+		t.Errorf("Expected 'db' without calling fn, got '%s' (%d calls)", v, calls)
+	}
+	if v := c.Get("DSN"); v != "db:5432" {
+		t.Errorf("Expected derived value 'db:5432', got '%s'", v)
+	}
+	if v := c.GetStrings("REPLICAS"); !reflect.DeepEqual(v, []string{"r1", "r2"}) {
+		t.Errorf("Expected fallback to be split, got %q", v)
+	}
+	if v := c.GetOrDefault("OTHER", "x"); v != "x" {
+		t.Errorf("Expected default when fn declines, got '%s'", v)
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/efixler/config/featureconfig
+
+go 1.25.0
+
+require (
+	github.com/efixler/config v0.0.0-00010101000000-000000000000
+	github.com/open-feature/go-sdk v1.18.0
+)
+
+require go.uber.org/mock v0.6.0 // indirect
+
+replace github.com/efixler/config => ../
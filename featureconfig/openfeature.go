@@ -0,0 +1,93 @@
+// Package featureconfig provides a config.Getter over OpenFeature flags, so feature-gated code reads flags the same
+// way it reads the rest of its config. It's a separate package to keep the OpenFeature SDK dependency optional.
+package featureconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/open-feature/go-sdk/openfeature"
+
+	"github.com/efixler/config"
+)
+
+// Client is the part of *openfeature.Client the Getter uses.
+type Client interface {
+	StringValueDetails(ctx context.Context, flag string, defaultValue string, evalCtx openfeature.EvaluationContext, options ...openfeature.Option) (openfeature.StringEvaluationDetails, error)
+	BooleanValueDetails(ctx context.Context, flag string, defaultValue bool, evalCtx openfeature.EvaluationContext, options ...openfeature.Option) (openfeature.BooleanEvaluationDetails, error)
+}
+
+// Getter evaluates flags as config values. It's a config.ErrorGetter and a config.ContextGetter.
+type Getter struct {
+	client Client
+}
+
+// NewOpenFeatureGetter : Return a Getter where Get(key) evaluates the string flag key, with "" as its default.
+// Use GetBool for boolean flags.
+//
+// For targeting, use config.GetContext (or GetBool) with a Context carrying an evaluation context, from
+// openfeature.WithTransactionContext; it's merged with the client's and the global context as usual. The
+// Context-free accessors evaluate with just the client's and global contexts.
+//
+// Evaluation errors are returned by GetErr and GetContext, and the flag's default is returned with them; Get
+// returns the default and logs them. A flag that doesn't exist is an error wrapping config.ErrKeyNotSet.
+func NewOpenFeatureGetter(client Client) *Getter {
+	return &Getter{client: client}
+}
+
+// evalError turns an evaluation failure for key into an error, or nil if there wasn't one.
+func evalError(key string, details openfeature.EvaluationDetails, err error) error {
+	switch {
+	case details.ErrorCode == openfeature.FlagNotFoundCode:
+		return fmt.Errorf("featureconfig: %s: %w", key, config.ErrKeyNotSet)
+	case err != nil:
+		return fmt.Errorf("featureconfig: evaluating %s: %w", key, err)
+	}
+	return nil
+}
+
+// GetContext : Evaluate the string flag key in ctx.
+func (g *Getter) GetContext(ctx context.Context, key string) (string, error) {
+	details, err := g.client.StringValueDetails(ctx, key, "", openfeature.EvaluationContext{})
+	return details.Value, evalError(key, details.EvaluationDetails, err)
+}
+
+// GetBool : Evaluate the boolean flag key in ctx, returning dflt (and the error) if evaluation fails.
+func (g *Getter) GetBool(ctx context.Context, key string, dflt bool) (bool, error) {
+	details, err := g.client.BooleanValueDetails(ctx, key, dflt, openfeature.EvaluationContext{})
+	if err = evalError(key, details.EvaluationDetails, err); err != nil {
+		return dflt, err
+	}
+	return details.Value, nil
+}
+
+// GetErr : Evaluate the string flag key, without a transaction context.
+func (g *Getter) GetErr(key string) (string, error) {
+	return g.GetContext(context.Background(), key)
+}
+
+// Get : Evaluate the string flag key, or return "" if it doesn't exist or evaluation fails.
+func (g *Getter) Get(key string) string {
+	val, err := g.GetErr(key)
+	if err != nil && !errors.Is(err, config.ErrKeyNotSet) {
+		log.Print(err)
+	}
+	return val
+}
+
+// GetOrDefault : If the requested flag is not present or empty, return the dflt.
+func (g *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(g, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited flag value as an []string, stripping whitespace around the commas.
+func (g *Getter) GetStrings(key string) []string {
+	return config.SplitStrings(g.Get(key))
+}
+
+// MustGet will panic if the flag is not present or empty.
+func (g *Getter) MustGet(key string) string {
+	return config.MustGet(g, key)
+}
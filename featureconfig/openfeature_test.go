@@ -0,0 +1,55 @@
+package featureconfig
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/open-feature/go-sdk/openfeature/memprovider"
+
+	"github.com/efixler/config"
+)
+
+func TestOpenFeatureGetter(t *testing.T) {
+	byTier := func(this memprovider.InMemoryFlag, flatCtx openfeature.FlattenedContext) (any, openfeature.ProviderResolutionDetail) {
+		if flatCtx["tier"] == "gold" {
+			return this.Variants["fast"], openfeature.ProviderResolutionDetail{Variant: "fast", Reason: openfeature.TargetingMatchReason}
+		}
+		return this.Variants["slow"], openfeature.ProviderResolutionDetail{Variant: "slow", Reason: openfeature.DefaultReason}
+	}
+	provider := memprovider.NewInMemoryProvider(map[string]memprovider.InMemoryFlag{
+		"checkout-flow": {
+			State:            memprovider.Enabled,
+			DefaultVariant:   "slow",
+			Variants:         map[string]any{"slow": "v1", "fast": "v2"},
+			ContextEvaluator: &byTier,
+		},
+		"new-ui": {
+			State:          memprovider.Enabled,
+			DefaultVariant: "on",
+			Variants:       map[string]any{"on": true, "off": false},
+		},
+	})
+	if err := openfeature.SetNamedProviderAndWait("featureconfig-test", provider); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g := NewOpenFeatureGetter(openfeature.NewClient("featureconfig-test"))
+
+	if v := g.Get("checkout-flow"); v != "v1" {
+		t.Errorf("Expected 'v1', got '%s'", v)
+	}
+	ctx := openfeature.WithTransactionContext(context.Background(), openfeature.NewEvaluationContext("user-1", map[string]any{"tier": "gold"}))
+	if v, err := config.GetContext(ctx, g, "checkout-flow"); err != nil || v != "v2" {
+		t.Errorf("Expected the targeted 'v2', got '%s' (%v)", v, err)
+	}
+	if v, err := g.GetBool(ctx, "new-ui", false); err != nil || !v {
+		t.Errorf("Expected true, got %v (%v)", v, err)
+	}
+	if _, err := g.GetErr("missing"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+	if v, err := g.GetBool(ctx, "checkout-flow", true); err == nil || !v {
+		t.Errorf("Expected a type mismatch error and the default, got %v (%v)", v, err)
+	}
+}
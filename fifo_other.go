@@ -0,0 +1,25 @@
+//go:build !unix
+
+package config
+
+import (
+	"errors"
+)
+
+// FIFOGetter is only functional on Unix; see fifo_unix.go. It has the same methods, so code using it builds
+// everywhere, but NewFIFOGetter always fails.
+type FIFOGetter struct {
+	Notifier
+	ErrorReporter
+	Map
+}
+
+// NewFIFOGetter : Named pipes are only supported on Unix, so this always returns an error.
+func NewFIFOGetter(path string, format string) (*FIFOGetter, error) {
+	return nil, errors.New("config: named pipe getters are only available on unix")
+}
+
+// Close : Nothing to close.
+func (f *FIFOGetter) Close() error {
+	return nil
+}
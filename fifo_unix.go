@@ -0,0 +1,148 @@
+//go:build unix
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// fifoCloseRetry is how often Close tries again to wake a reader that wasn't blocked in open() yet.
+const fifoCloseRetry = 10 * time.Millisecond
+
+// FIFOGetter holds the latest config document pushed through a named pipe. See NewFIFOGetter.
+type FIFOGetter struct {
+	Notifier
+	ErrorReporter
+	path      string
+	format    string
+	values    atomic.Pointer[Map]
+	closeOnce sync.Once
+	closed    atomic.Bool
+	done      chan struct{}
+}
+
+// NewFIFOGetter : Return a Getter that's updated every time a config document is written to the named pipe
+// (FIFO) at path, parsed with the named format (see RegisterFormat). This is a push-based alternative to polling.
+//
+// The framing is one whole document per writer: a writer opens the pipe, writes a complete document, and closes
+// it. The document is read to that EOF, however many reads it takes, and only then parsed and swapped in, so
+// readers never see a partial update. Empty documents are ignored. Until the first document arrives the Getter is empty.
+//
+// The Getter is a Watcher, notified after each new document. Read and parse errors (including a document larger than
+// 32MB) are sent to the Errors() channel, and the previous values stay in place. Call Close to stop reading.
+func NewFIFOGetter(path string, format string) (*FIFOGetter, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if info.Mode()&os.ModeNamedPipe == 0 {
+		return nil, fmt.Errorf("config: %s is not a named pipe", path)
+	}
+	f := &FIFOGetter{path: path, format: format, done: make(chan struct{})}
+	f.values.Store(&Map{})
+	go f.run()
+	return f, nil
+}
+
+func (f *FIFOGetter) run() {
+	defer close(f.done)
+	for !f.closed.Load() {
+		data, err := f.readPipe()
+		if f.closed.Load() {
+			return
+		}
+		if err == nil && len(data) == 0 {
+			continue
+		}
+		var values Map
+		if err == nil {
+			values, err = ParseFormat(f.format, data)
+		}
+		if err != nil {
+			f.Report(fmt.Errorf("config: reading %s: %w", f.path, err))
+			continue
+		}
+		f.values.Store(&values)
+		f.Notify()
+	}
+}
+
+// readPipe blocks until a writer opens the pipe, then reads until it closes it (see readDocument).
+func (f *FIFOGetter) readPipe() ([]byte, error) {
+	r, err := os.OpenFile(f.path, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return readDocument(r)
+}
+
+// Close : Stop reading the pipe. The Getter keeps serving the last document it read.
+func (f *FIFOGetter) Close() error {
+	f.closeOnce.Do(func() {
+		f.closed.Store(true)
+		// A reader blocked in open() needs a writer to come along before it can notice it's been closed. If run()
+		// hasn't reached open() yet there's no reader, and opening for writing fails with ENXIO, so keep trying
+		// until run() is done.
+		for {
+			if w, err := os.OpenFile(f.path, os.O_WRONLY|syscall.O_NONBLOCK, 0); err == nil {
+				w.Close()
+			} else if !errors.Is(err, syscall.ENXIO) {
+				// The pipe is gone, and the reader can't be woken; don't wait for it.
+				f.Report(err)
+				return
+			}
+			select {
+			case <-f.done:
+				return
+			case <-time.After(fifoCloseRetry):
+			}
+		}
+	})
+	return nil
+}
+
+func (f *FIFOGetter) current() Map {
+	return *f.values.Load()
+}
+
+// Snapshot : Return the values of the latest document, which won't change as new documents arrive.
+func (f *FIFOGetter) Snapshot() Getter {
+	return f.current()
+}
+
+// Get : Return the value for key from the latest document.
+func (f *FIFOGetter) Get(key string) string {
+	return f.current().Get(key)
+}
+
+// Lookup : Return the value for key from the latest document, and whether it's present.
+func (f *FIFOGetter) Lookup(key string) (string, bool) {
+	return f.current().Lookup(key)
+}
+
+// Keys : Return the keys of the latest document.
+func (f *FIFOGetter) Keys() []string {
+	return f.current().Keys()
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (f *FIFOGetter) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(f, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (f *FIFOGetter) GetStrings(key string) []string {
+	return f.current().GetStrings(key)
+}
+
+// MustGet will panic if the key is not present or empty.
+func (f *FIFOGetter) MustGet(key string) string {
+	return MustGet(f, key)
+}
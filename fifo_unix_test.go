@@ -0,0 +1,102 @@
+//go:build unix
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func writeFIFO(t *testing.T, path string, chunks ...string) {
+	w, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, chunk := range chunks {
+		w.Write([]byte(chunk))
+	}
+	w.Close()
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the FIFO getter")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestFIFOGetter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.fifo")
+	if err := syscall.Mkfifo(path, 0600); err != nil {
+		t.Skipf("Can't create a FIFO: %v", err)
+	}
+	f, err := NewFIFOGetter(path, "env")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer f.Close()
+	changes := f.Watch()
+	if v := f.Get("MODE"); v != "" {
+		t.Errorf("Expected no values before the first write, got '%s'", v)
+	}
+	writeFIFO(t, path, "MODE=a\nLEVEL=", "info\n")
+	waitFor(t, func() bool { return f.Get("MODE") == "a" })
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a change notification")
+	}
+	if v := f.Get("LEVEL"); v != "info" {
+		t.Errorf("Document split across writes should be read whole; expected 'info', got '%s'", v)
+	}
+	writeFIFO(t, path, "not a pair\n")
+	select {
+	case <-f.Errors():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a parse error")
+	}
+	if v := f.Get("MODE"); v != "a" {
+		t.Errorf("A bad document should keep the previous values, got '%s'", v)
+	}
+	writeFIFO(t, path, "MODE=b\n")
+	waitFor(t, func() bool { return f.Get("MODE") == "b" })
+}
+
+func TestFIFOGetterNotAPipe(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "regular")
+	os.WriteFile(path, nil, 0600)
+	if _, err := NewFIFOGetter(path, "env"); err == nil {
+		t.Error("Expected an error for a regular file")
+	}
+}
+
+func TestFIFOGetterCloseRightAway(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.fifo")
+	if err := syscall.Mkfifo(path, 0600); err != nil {
+		t.Skipf("Can't create a FIFO: %v", err)
+	}
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		// Close before run() has had a chance to block in open(), many times over.
+		for i := 0; i < 50; i++ {
+			f, err := NewFIFOGetter(path, "env")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			f.Close()
+		}
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close deadlocked")
+	}
+}
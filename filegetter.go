@@ -0,0 +1,161 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// fileFormats maps file extensions to the format names NewFileGetter parses them with.
+var fileFormats = map[string]string{
+	".json":       "json",
+	".yaml":       "yaml",
+	".yml":        "yaml",
+	".toml":       "toml",
+	".ini":        "ini",
+	".env":        "env",
+	".properties": "properties",
+}
+
+// formatPackages names the packages that register the formats that aren't built in.
+var formatPackages = map[string]string{
+	"yaml": "github.com/efixler/config/yamlconfig",
+	"toml": "github.com/efixler/config/tomlconfig",
+}
+
+// FileOption configures NewFileGetter.
+type FileOption func(*fileOptions)
+
+type fileOptions struct {
+	sniff bool
+}
+
+// SniffFormat : Have NewFileGetter guess the format of a file without an extension from its contents, rather than
+// returning an error.
+func SniffFormat() FileOption {
+	return func(o *fileOptions) {
+		o.sniff = true
+	}
+}
+
+// NewFileGetter : Read the config file at path, parsed according to its extension, and return a Getter holding its
+// values. The file is read once, and the Getter is a TimestampedGetter reporting its mtime. A file larger than 32MB
+// is an error. Extensions are matched case-insensitively:
+//
+//	.json               json (see FlattenValue)
+//	.yaml, .yml         yaml (registered by importing yamlconfig)
+//	.toml               toml (registered by importing tomlconfig)
+//	.ini                ini (see ParseINI)
+//	.env                env (KEY=VALUE lines, dotenv style)
+//	.properties         properties (Java .properties files)
+//
+// Any other extension is an error, as is a file without one, unless SniffFormat is given. Sniffing looks at the
+// first line that isn't blank or a comment (# or ;): { is JSON; a [section] header is INI; KEY=VALUE is env;
+// a "key:" mapping or a --- document marker is YAML. Anything else is an error. YAML and TOML files need their
+// package imported (for side effects, if nothing else) to be read.
+func NewFileGetter(path string, opts ...FileOption) (Getter, error) {
+	var options fileOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return readFile(path, "", options)
+}
+
+// readFile reads the file at path, no larger than maxDocumentSize, and parses it in format, or in the format
+// NewFileGetter chooses for it if format is "".
+func readFile(path string, format string, options fileOptions) (*fileValues, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	data, err := readDocument(f)
+	if err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	if format == "" {
+		if format, err = fileFormat(path, data, options); err != nil {
+			return nil, err
+		}
+	}
+	values, err := parseFileAs(path, format, data)
+	if err != nil {
+		return nil, err
+	}
+	return &fileValues{Map: values, modTime: info.ModTime()}, nil
+}
+
+// parseFile parses data, read from path, in the format NewFileGetter chooses for it.
+func parseFile(path string, data []byte, options fileOptions) (Map, error) {
+	format, err := fileFormat(path, data, options)
+	if err != nil {
+		return nil, err
+	}
+	return parseFileAs(path, format, data)
+}
+
+// fileFormat is the format NewFileGetter chooses for data, read from path.
+func fileFormat(path string, data []byte, options fileOptions) (string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	format, ok := fileFormats[ext]
+	switch {
+	case ok:
+	case ext != "":
+		return "", fmt.Errorf("config: %s: unknown file extension %s", path, ext)
+	case !options.sniff:
+		return "", fmt.Errorf("config: %s: can't tell the format of a file without an extension", path)
+	default:
+		if format, ok = sniffFormat(data); !ok {
+			return "", fmt.Errorf("config: %s: can't tell the format from the contents", path)
+		}
+	}
+	return format, nil
+}
+
+// parseFileAs parses data, read from path, as format.
+func parseFileAs(path string, format string, data []byte) (Map, error) {
+	if pkg, ok := formatPackages[format]; ok && !hasFormat(format) {
+		return nil, fmt.Errorf("config: %s: the %s format isn't registered (import %s)", path, format, pkg)
+	}
+	values, err := ParseFormat(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	return values, nil
+}
+
+var (
+	sniffINISection = regexp.MustCompile(`^\[[^\[\]]+\]$`)
+	sniffEnvLine    = regexp.MustCompile(`^(export\s+)?[A-Za-z_][A-Za-z0-9_.]*\s*=`)
+	sniffYAMLLine   = regexp.MustCompile(`^(---|[^\s:#][^:]*:(\s|$))`)
+)
+
+// sniffFormat guesses the format of data from its first significant line.
+func sniffFormat(data []byte) (string, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+			continue
+		case line[0] == '{':
+			return "json", true
+		case sniffINISection.MatchString(line):
+			return "ini", true
+		case sniffEnvLine.MatchString(line):
+			return "env", true
+		case sniffYAMLLine.MatchString(line):
+			return "yaml", true
+		}
+		return "", false
+	}
+	return "", false
+}
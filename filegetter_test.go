@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, name, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewFileGetter(t *testing.T) {
+	tests := map[string]string{
+		"app.json":       `{"db": {"host": "db1"}}`,
+		"APP.JSON":       `{"db": {"host": "db1"}}`,
+		"app.ini":        "[db]\nhost = db1\n",
+		"app.env":        "db.host=db1\n",
+		".env":           "db.host=db1\n",
+		"app.properties": "db.host: db1\n",
+	}
+	for name, data := range tests {
+		g, err := NewFileGetter(writeConfigFile(t, name, data))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if v := g.Get("db.host"); v != "db1" {
+			t.Errorf("%s: expected 'db1', got '%s'", name, v)
+		}
+		if _, ok := ModTime(g, "db.host"); !ok {
+			t.Errorf("%s: expected the file's mod time", name)
+		}
+	}
+}
+
+func TestNewFileGetterSniffing(t *testing.T) {
+	tests := map[string]string{
+		"# comment\n{\"a\": \"1\"}":  "json",
+		"; comment\n[main]\na = 1\n": "ini",
+		"export A=1\nB=2\n":          "env",
+		"---\na: 1\n":                "yaml",
+		"a:\n  b: 1\n":               "yaml",
+		"just some text\n":           "",
+		"":                           "",
+	}
+	for data, expected := range tests {
+		if format, _ := sniffFormat([]byte(data)); format != expected {
+			t.Errorf("%q: expected %q, got %q", data, expected, format)
+		}
+	}
+	path := writeConfigFile(t, "config", "[main]\na = 1\n")
+	if _, err := NewFileGetter(path); err == nil {
+		t.Error("Expected an error for a file without an extension")
+	}
+	g, err := NewFileGetter(path, SniffFormat())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := g.Get("main.a"); v != "1" {
+		t.Errorf("Expected '1', got '%s'", v)
+	}
+}
+
+func TestNewFileGetterErrors(t *testing.T) {
+	tests := map[string]string{
+		"app.xml":  "unknown file extension .xml",
+		"app.toml": "import github.com/efixler/config/tomlconfig",
+		"app.json": "parsing json",
+	}
+	for name, expected := range tests {
+		_, err := NewFileGetter(writeConfigFile(t, name, "not valid"))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: expected an error containing %q, got %v", name, expected, err)
+		}
+	}
+	if _, err := NewFileGetter(writeConfigFile(t, "config", "just text"), SniffFormat()); err == nil {
+		t.Error("Expected an error for unrecognizable contents")
+	}
+	if _, err := NewFileGetter(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestNewFileGetterTooLarge(t *testing.T) {
+	path := writeConfigFile(t, "big.env", "A="+strings.Repeat("x", maxDocumentSize))
+	if _, err := NewFileGetter(path); err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("Expected an error for a file over the size cap, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// maxFileIndirectionDepth bounds how many files WithFileIndirection follows for one key.
+const maxFileIndirectionDepth = 8
+
+// WithFileIndirection : Wrap a Getter to support the *_FILE convention used by container platforms for secrets:
+// if key is unset but key+"_FILE" is set, the value is read from the file it names. A direct value always beats
+// the _FILE reference.
+//
+// The file's contents, with surrounding whitespace trimmed, are the value, unless the file is itself in KEY=VALUE
+// format and sets key or key+"_FILE"; then it's the value of key from the file or, failing that, the contents of the
+// file named by its key+"_FILE", and so on. Relative paths in a file are relative to the process, not the file.
+// A cycle, or more than 8 files, is an error.
+//
+// The returned Getter is an ErrorGetter; unreadable files and cycles surface through GetErr, and Get returns "".
+func WithFileIndirection(g Getter) Getter {
+	return &fileIndirection{g: g}
+}
+
+type fileIndirection struct {
+	g Getter
+}
+
+func (f *fileIndirection) GetErr(key string) (string, error) {
+	val, err := GetErr(f.g, key)
+	if err != nil || val != "" {
+		return val, err
+	}
+	path, err := GetErr(f.g, key+"_FILE")
+	if err != nil || path == "" {
+		return "", err
+	}
+	seen := make(map[string]bool)
+	for depth := 0; ; depth++ {
+		if seen[path] || depth >= maxFileIndirectionDepth {
+			return "", fmt.Errorf("config: %s_FILE: %s: %w", key, path, errResolveCycle)
+		}
+		seen[path] = true
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("config: %s_FILE: %w", key, err)
+		}
+		values, err := parseKeyValues(bytes.NewReader(data))
+		if err != nil {
+			return strings.TrimSpace(string(data)), nil
+		}
+		if val := values[key]; val != "" {
+			return val, nil
+		}
+		next, ok := values[key+"_FILE"]
+		if !ok {
+			if _, ok := values[key]; ok {
+				return "", nil
+			}
+			return strings.TrimSpace(string(data)), nil
+		}
+		path = next
+	}
+}
+
+func (f *fileIndirection) Get(key string) string {
+	val, _ := f.GetErr(key)
+	return val
+}
+
+func (f *fileIndirection) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(f, key, dflt)
+}
+
+func (f *fileIndirection) GetStrings(key string) []string {
+	if f.g.Get(key) != "" {
+		return f.g.GetStrings(key)
+	}
+	return SplitStrings(f.Get(key))
+}
+
+func (f *fileIndirection) MustGet(key string) string {
+	return MustGet(f, key)
+}
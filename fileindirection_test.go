@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithFileIndirection(t *testing.T) {
+	dir := t.TempDir()
+	secret, nested, loop := filepath.Join(dir, "secret"), filepath.Join(dir, "nested.env"), filepath.Join(dir, "loop.env")
+	os.WriteFile(secret, []byte("s3cret=\n"), 0600)
+	os.WriteFile(nested, []byte("# more indirection\nDB_PASSWORD_FILE="+secret+"\nOTHER=1\n"), 0600)
+	os.WriteFile(loop, []byte("LOOP_FILE="+loop+"\n"), 0600)
+	os.WriteFile(filepath.Join(dir, "direct.env"), []byte("API_KEY=abc\n"), 0600)
+
+	g := WithFileIndirection(Map{
+		"DB_PASSWORD_FILE": nested,
+		"TOKEN":            "direct",
+		"TOKEN_FILE":       secret,
+		"API_KEY_FILE":     filepath.Join(dir, "direct.env"),
+		"LOOP_FILE":        loop,
+		"MISSING_FILE":     filepath.Join(dir, "missing"),
+	})
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"DB_PASSWORD", "s3cret="},
+		{"TOKEN", "direct"},
+		{"API_KEY", "abc"},
+		{"UNSET", ""},
+	}
+	for _, test := range tests {
+		if v, err := GetErr(g, test.key); err != nil || v != test.expected {
+			t.Errorf("%s: expected '%s', got '%s' (%v)", test.key, test.expected, v, err)
+		}
+	}
+	for _, key := range []string{"LOOP", "MISSING"} {
+		if _, err := GetErr(g, key); err == nil {
+			t.Errorf("%s: expected an error", key)
+		}
+	}
+}
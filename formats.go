@@ -0,0 +1,140 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FormatParser converts a config document into flat keys and values.
+type FormatParser func(data []byte) (map[string]string, error)
+
+var (
+	formatsLock sync.RWMutex
+	formats     = map[string]FormatParser{
+		"env":        parseEnvFormat,
+		"json":       parseJSONFormat,
+		"properties": parsePropertiesFormat,
+	}
+)
+
+// RegisterFormat : Make a document format available, by name, to the getters that parse documents (files, remote
+// sources and the like). Built in are "env" (KEY=VALUE lines, dotenv style), "json" (see FlattenValue), "ini"
+// (see ParseINI) and "properties" (Java .properties files); importing the yamlconfig package adds "yaml", and
+// importing tomlconfig adds "toml".
+// Registering an existing name replaces it.
+func RegisterFormat(name string, parse FormatParser) {
+	formatsLock.Lock()
+	defer formatsLock.Unlock()
+	formats[name] = parse
+}
+
+func hasFormat(name string) bool {
+	formatsLock.RLock()
+	defer formatsLock.RUnlock()
+	_, ok := formats[name]
+	return ok
+}
+
+// ParseFormat : Parse data with the parser registered for format.
+func ParseFormat(format string, data []byte) (Map, error) {
+	formatsLock.RLock()
+	parse, ok := formats[format]
+	formatsLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("config: unknown format %q", format)
+	}
+	values, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("config: parsing %s: %w", format, err)
+	}
+	return Map(values), nil
+}
+
+// maxDocumentSize bounds the size of a config document read from a file or the network, so a runaway or
+// hostile source can't exhaust memory. Decompressed data counts against it, which also stops decompression bombs.
+const maxDocumentSize = 32 << 20
+
+// readDocument reads all of r, failing if it holds more than maxDocumentSize bytes.
+func readDocument(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxDocumentSize+1))
+	if err == nil && len(data) > maxDocumentSize {
+		err = fmt.Errorf("document is larger than %d bytes", maxDocumentSize)
+	}
+	return data, err
+}
+
+func parseEnvFormat(data []byte) (map[string]string, error) {
+	return parseKeyValues(bytes.NewReader(data))
+}
+
+func parseJSONFormat(data []byte) (map[string]string, error) {
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	obj, ok := doc.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON object at the top level, got %T", doc)
+	}
+	rval := make(map[string]string)
+	FlattenValue(rval, "", obj)
+	return rval, nil
+}
+
+// FlattenValue : Flatten a decoded document (as produced by encoding/json, or YAML decoders, into any) into dst.
+// Nested maps become dot-separated keys ("db.host"), and sequences of scalars become a comma-joined value that
+// GetStrings splits back apart. Sequences containing maps or sequences are flattened by index ("servers.0.host").
+// Scalars are stringified with fmt (floats without exponents), and nulls become "".
+func FlattenValue(dst map[string]string, prefix string, v any) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		for key, val := range v {
+			FlattenValue(dst, join(key), val)
+		}
+	case map[any]any:
+		for key, val := range v {
+			FlattenValue(dst, join(fmt.Sprint(key)), val)
+		}
+	case []any:
+		vals := make([]string, 0, len(v))
+		for _, val := range v {
+			switch val.(type) {
+			case map[string]any, map[any]any, []any:
+				for i, val := range v {
+					FlattenValue(dst, join(strconv.Itoa(i)), val)
+				}
+				return
+			}
+			vals = append(vals, scalarString(val))
+		}
+		dst[prefix] = strings.Join(vals, ",")
+	default:
+		dst[prefix] = scalarString(v)
+	}
+}
+
+func scalarString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		// Avoid exponents, so a timestamp decoded as a float comes back as it was written.
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFormatJSON(t *testing.T) {
+	m, err := ParseFormat("json", []byte(`{
+		"db": {"host": "localhost", "port": 5432, "tls": true},
+		"hosts": ["a", "b"],
+		"servers": [{"name": "x"}, {"name": "y"}],
+		"empty": null
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := Map{
+		"db.host":        "localhost",
+		"db.port":        "5432",
+		"db.tls":         "true",
+		"hosts":          "a,b",
+		"servers.0.name": "x",
+		"servers.1.name": "y",
+		"empty":          "",
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("Expected %v, got %v", expected, m)
+	}
+	if _, err := ParseFormat("json", []byte(`[1, 2]`)); err == nil {
+		t.Error("Expected an error for a non-object document")
+	}
+}
+
+func TestParseFormatUnknown(t *testing.T) {
+	if _, err := ParseFormat("xml", nil); err == nil {
+		t.Error("Expected an error for an unregistered format")
+	}
+	RegisterFormat("test-upper", func(data []byte) (map[string]string, error) {
+		return map[string]string{"DATA": string(data)}, nil
+	})
+	if m, err := ParseFormat("test-upper", []byte("x")); err != nil || m.Get("DATA") != "x" {
+		t.Errorf("Expected registered format to be used, got %v (%v)", m, err)
+	}
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNoPriorGeneration is returned by Rollback when there's no earlier generation in the history to restore.
+var ErrNoPriorGeneration = errors.New("config: no prior generation to roll back to")
+
+// GenerationalGetter is a SwappableGetter that keeps the Getters it has replaced, so it can roll back to them.
+// See NewGenerationalGetter.
+type GenerationalGetter struct {
+	*SwappableGetter
+	size int
+	// The rest is guarded by SwappableGetter.lock, so it changes in step with swaps.
+	last    int // the highest generation number handed out
+	current generation
+	history []generation // oldest first
+}
+
+type generation struct {
+	g       Getter
+	number  int
+	applied time.Time
+}
+
+// NewGenerationalGetter : Track the generations of s, so that a config that misbehaves once it's applied can be
+// backed out with Rollback. s's current Getter is generation 1, and each Swap after that (including those a Reloader
+// makes, for a Reloader's SwappableGetter) applies the next generation and pushes the one it replaced onto the
+// history. Pairing it with ValidatingReloader gives both validation before a config is applied and rollback after:
+//
+//	r, err := config.ValidatingReloader(load, validate)
+//	...
+//	gens := config.NewGenerationalGetter(r.SwappableGetter, 5)
+//	// hand gens (or r) to consumers; call r.Reload() on changes and gens.Rollback() on trouble
+//
+// The history is bounded: it holds the size generations (at least 1) before the current one, and the oldest is dropped
+// once there are more, so up to size rollbacks in a row can be made. The Getters in the history are kept as they are,
+// not reloaded, so Rollback is instant and works even when the config's source is what's broken. Create one
+// GenerationalGetter per SwappableGetter.
+func NewGenerationalGetter(s *SwappableGetter, size int) *GenerationalGetter {
+	if size < 1 {
+		size = 1
+	}
+	gg := &GenerationalGetter{SwappableGetter: s, size: size}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	gg.last = 1
+	gg.current = generation{g: s.getter(), number: 1, applied: time.Now()}
+	s.onSwap = gg.push
+	return gg
+}
+
+// push records a swap: old is now history, and the current Getter is the next generation.
+func (gg *GenerationalGetter) push(old Getter) {
+	gg.current.g = old
+	gg.history = append(gg.history, gg.current)
+	if len(gg.history) > gg.size {
+		gg.history = append(gg.history[:0], gg.history[len(gg.history)-gg.size:]...)
+	}
+	gg.last++
+	gg.current = generation{g: gg.getter(), number: gg.last, applied: time.Now()}
+}
+
+// Rollback : Atomically swap the previous generation back in, discarding the current one, and notify Watch()
+// channels. The restored generation gets its old number back; the next Swap applies a new, higher number rather
+// than reusing the discarded one. It returns ErrNoPriorGeneration if the history is empty.
+func (gg *GenerationalGetter) Rollback() error {
+	s := gg.SwappableGetter
+	s.lock.Lock()
+	if len(gg.history) == 0 {
+		s.lock.Unlock()
+		return ErrNoPriorGeneration
+	}
+	gg.current = gg.history[len(gg.history)-1]
+	gg.history = gg.history[:len(gg.history)-1]
+	s.current.Store(&swapped{g: gg.current.g})
+	s.lock.Unlock()
+	s.Notify()
+	return nil
+}
+
+// CurrentGeneration : Return the number of the generation being served.
+func (gg *GenerationalGetter) CurrentGeneration() int {
+	gg.SwappableGetter.lock.Lock()
+	defer gg.SwappableGetter.lock.Unlock()
+	return gg.current.number
+}
+
+// History : Return when each generation in memory was applied, oldest first. The last is the current generation;
+// the ones before it are those Rollback can restore.
+func (gg *GenerationalGetter) History() []time.Time {
+	gg.SwappableGetter.lock.Lock()
+	defer gg.SwappableGetter.lock.Unlock()
+	rval := make([]time.Time, 0, len(gg.history)+1)
+	for _, gen := range gg.history {
+		rval = append(rval, gen.applied)
+	}
+	return append(rval, gg.current.applied)
+}
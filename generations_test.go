@@ -0,0 +1,113 @@
+package config
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestGenerationalGetter(t *testing.T) {
+	gens := NewGenerationalGetter(NewSwappable(Map{"MODE": "v1"}), 2)
+	if n := gens.CurrentGeneration(); n != 1 {
+		t.Errorf("Expected generation 1, got %d", n)
+	}
+	if err := gens.Rollback(); !errors.Is(err, ErrNoPriorGeneration) {
+		t.Errorf("Expected ErrNoPriorGeneration with no history, got %v", err)
+	}
+	for _, mode := range []string{"v2", "v3", "v4"} {
+		gens.Swap(Map{"MODE": mode})
+	}
+	if n := gens.CurrentGeneration(); n != 4 {
+		t.Errorf("Expected generation 4, got %d", n)
+	}
+	history := gens.History()
+	if len(history) != 3 {
+		t.Fatalf("Expected two prior generations and the current one, got %d", len(history))
+	}
+	for i := 1; i < len(history); i++ {
+		if history[i].Before(history[i-1]) {
+			t.Errorf("Expected history oldest first, got %v", history)
+		}
+	}
+
+	watch := gens.Watch()
+	if err := gens.Rollback(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	select {
+	case <-watch:
+	default:
+		t.Error("Expected a notification on rollback")
+	}
+	if v, n := gens.Get("MODE"), gens.CurrentGeneration(); v != "v3" || n != 3 {
+		t.Errorf("Expected generation 3 with v3, got %d with %s", n, v)
+	}
+	if err := gens.Rollback(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := gens.Get("MODE"); v != "v2" {
+		t.Errorf("Expected v2, got %s", v)
+	}
+	// v1 fell off the bounded history.
+	if err := gens.Rollback(); !errors.Is(err, ErrNoPriorGeneration) {
+		t.Errorf("Expected ErrNoPriorGeneration past the history, got %v", err)
+	}
+	gens.Swap(Map{"MODE": "v5"})
+	if n := gens.CurrentGeneration(); n != 5 {
+		t.Errorf("Expected a new generation number after a rollback, got %d", n)
+	}
+}
+
+func TestGenerationalGetterWithReloader(t *testing.T) {
+	version := "v1"
+	r, err := ValidatingReloader(func() (Getter, error) {
+		return Map{"MODE": version}, nil
+	}, func(g Getter) error {
+		if g.Get("MODE") == "" {
+			return errors.New("MODE is required")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gens := NewGenerationalGetter(r.SwappableGetter, 5)
+	version = "v2"
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	version = ""
+	if err := r.Reload(); err == nil {
+		t.Error("Expected the invalid reload to be rejected")
+	}
+	if n := gens.CurrentGeneration(); n != 2 {
+		t.Errorf("Expected only the applied reload to make a generation, got %d", n)
+	}
+	if err := gens.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if v := r.Get("MODE"); v != "v1" {
+		t.Errorf("Expected the Reloader to serve the rolled back v1, got %s", v)
+	}
+}
+
+func TestGenerationalGetterConcurrent(t *testing.T) {
+	gens := NewGenerationalGetter(NewSwappable(Map{"MODE": "0"}), 3)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				gens.Swap(Map{"MODE": "x"})
+				gens.Rollback()
+				gens.History()
+				gens.Get("MODE")
+			}
+		}()
+	}
+	wg.Wait()
+	if h := gens.History(); len(h) > 4 {
+		t.Errorf("Expected the history to stay bounded, got %d", len(h))
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/efixler/config
+
+go 1.22
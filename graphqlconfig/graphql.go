@@ -0,0 +1,327 @@
+// Package graphqlconfig provides a config.Getter over the result of a GraphQL query, for apps whose control plane
+// serves config through a GraphQL API. It speaks GraphQL over HTTP with the standard library, so it adds no
+// dependencies.
+package graphqlconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/efixler/config"
+)
+
+// DefaultRefreshInterval is how often the query is run again, unless overridden with WithRefreshInterval.
+const DefaultRefreshInterval = time.Minute
+
+// requestTimeout bounds each query.
+const requestTimeout = 30 * time.Second
+
+// maxBodySize bounds the size of a query response; a larger one is an error rather than read into memory.
+const maxBodySize = 8 << 20
+
+// Error is one entry of the errors array of a GraphQL response.
+type Error struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+// Errors is the errors array of a GraphQL response. A query that returns errors is a failure, even if it also
+// returns data.
+type Errors []Error
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		if len(err.Path) > 0 {
+			msgs = append(msgs, fmt.Sprintf("%s (at %v)", err.Message, err.Path))
+		} else {
+			msgs = append(msgs, err.Message)
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Option configures a Getter.
+type Option func(*Getter)
+
+// WithHeader : Send the header with every request, e.g. WithHeader("Authorization", "Bearer "+token).
+func WithHeader(name, value string) Option {
+	return func(g *Getter) {
+		g.header.Set(name, value)
+	}
+}
+
+// WithVariables : Send variables with the query.
+func WithVariables(variables map[string]any) Option {
+	return func(g *Getter) {
+		g.variables = variables
+	}
+}
+
+// WithRefreshInterval : Run the query every interval instead of every DefaultRefreshInterval. NewGraphQLGetter
+// returns an error if interval isn't positive.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(g *Getter) {
+		g.interval = interval
+	}
+}
+
+// Getter holds the values of the latest successful query. See NewGraphQLGetter.
+type Getter struct {
+	config.Notifier
+	config.ErrorReporter
+	endpoint  string
+	query     string
+	client    *http.Client
+	header    http.Header
+	variables map[string]any
+	interval  time.Duration
+	values    atomic.Pointer[config.Map]
+	err       atomic.Pointer[error]
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewGraphQLGetter : Return a Getter over the result of query, POSTed to endpoint as a GraphQL request with client
+// (a nil client means http.DefaultClient). The query is run before NewGraphQLGetter returns, and a failure is
+// returned.
+//
+// The data of the response is flattened with config.FlattenValue, with one convenience: if it has a single
+// top-level field holding an object, that field is the root, so `{ appConfig { dbHost port } }` yields the keys
+// dbHost and port. If the root is a list of objects with key and value fields, as with `{ settings { key value } }`,
+// each entry is a key and its value. A single scalar or list field, like `{ maintenanceMode }`, keeps its name.
+//
+// After that the query runs again every DefaultRefreshInterval, and Watch() channels are notified when the result
+// changes. A failed query, whether a transport error, a non-2xx status or a response with an errors array (an Errors),
+// goes to the Errors() channel, and the previous values stay in place: Get keeps serving them, while GetErr returns
+// the error until a query succeeds. Call Close to stop refreshing.
+func NewGraphQLGetter(endpoint string, query string, client *http.Client, opts ...Option) (*Getter, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &Getter{
+		endpoint: endpoint,
+		query:    query,
+		client:   client,
+		header:   make(http.Header),
+		interval: DefaultRefreshInterval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.interval <= 0 {
+		cancel()
+		return nil, fmt.Errorf("graphqlconfig: refresh interval must be positive, got %v", g.interval)
+	}
+	if _, err := g.refresh(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+	go g.run(ctx)
+	return g, nil
+}
+
+func (g *Getter) run(ctx context.Context) {
+	defer close(g.done)
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		changed, err := g.refresh(ctx)
+		if ctx.Err() != nil {
+			return
+		} else if err != nil {
+			g.Report(err)
+		} else if changed {
+			g.Notify()
+		}
+	}
+}
+
+// refresh runs the query, reporting whether the values changed.
+func (g *Getter) refresh(ctx context.Context) (bool, error) {
+	values, err := g.fetch(ctx)
+	if err != nil {
+		g.err.Store(&err)
+		return false, err
+	}
+	old := g.values.Swap(&values)
+	g.err.Store(nil)
+	return old == nil || !maps.Equal(*old, values), nil
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors Errors          `json:"errors"`
+}
+
+func (g *Getter) fetch(ctx context.Context) (config.Map, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	body, err := json.Marshal(map[string]any{"query": g.query, "variables": g.variables})
+	if err != nil {
+		return nil, fmt.Errorf("graphqlconfig: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("graphqlconfig: %w", err)
+	}
+	for name, vals := range g.header {
+		req.Header[name] = vals
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/graphql-response+json, application/json")
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("graphqlconfig: querying %s: %w", g.endpoint, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize+1))
+	if err == nil && len(data) > maxBodySize {
+		err = fmt.Errorf("response is larger than %d bytes", maxBodySize)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("graphqlconfig: querying %s: %w", g.endpoint, err)
+	}
+	var result response
+	decodeErr := json.Unmarshal(data, &result)
+	switch {
+	case decodeErr == nil && len(result.Errors) > 0:
+		return nil, fmt.Errorf("graphqlconfig: querying %s: %w", g.endpoint, result.Errors)
+	case resp.StatusCode/100 != 2:
+		return nil, fmt.Errorf("graphqlconfig: querying %s: unexpected status %s", g.endpoint, resp.Status)
+	case decodeErr != nil:
+		return nil, fmt.Errorf("graphqlconfig: querying %s: decoding the response: %w", g.endpoint, decodeErr)
+	}
+	return flatten(result.Data)
+}
+
+// flatten turns the data of a response into values, as described for NewGraphQLGetter.
+func flatten(data json.RawMessage) (config.Map, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil || doc == nil {
+		return nil, fmt.Errorf("graphqlconfig: the response has no data")
+	}
+	var root any = doc
+	if len(doc) == 1 {
+		// Only unwrap a field that holds values; a lone scalar or list field keeps its name as the key.
+		for _, v := range doc {
+			if _, isObject := v.(map[string]any); isObject {
+				root = v
+			} else if _, isEntries := keyValueEntries(v); isEntries {
+				root = v
+			}
+		}
+	}
+	rval := make(config.Map)
+	if entries, ok := keyValueEntries(root); ok {
+		for key, val := range entries {
+			config.FlattenValue(rval, key, val)
+		}
+		return rval, nil
+	}
+	config.FlattenValue(rval, "", root)
+	return rval, nil
+}
+
+// keyValueEntries returns root as a map if it's a list of {key, value} objects.
+func keyValueEntries(root any) (map[string]any, bool) {
+	list, ok := root.([]any)
+	if !ok || len(list) == 0 {
+		return nil, false
+	}
+	rval := make(map[string]any, len(list))
+	for _, item := range list {
+		obj, ok := item.(map[string]any)
+		key, isString := obj["key"].(string)
+		if !ok || !isString || len(obj) != 2 {
+			return nil, false
+		}
+		val, ok := obj["value"]
+		if !ok {
+			return nil, false
+		}
+		rval[key] = val
+	}
+	return rval, true
+}
+
+// Close : Stop refreshing, abandoning any query in flight. The Getter keeps serving the values it has.
+func (g *Getter) Close() error {
+	g.closeOnce.Do(func() {
+		g.cancel()
+		<-g.done
+	})
+	return nil
+}
+
+func (g *Getter) current() config.Map {
+	return *g.values.Load()
+}
+
+// GetErr : Return the value for key, the error from the latest query if it failed, or an error wrapping
+// config.ErrKeyNotSet.
+func (g *Getter) GetErr(key string) (string, error) {
+	if err := g.err.Load(); err != nil {
+		return "", *err
+	}
+	val, ok := g.current()[key]
+	if !ok {
+		return "", fmt.Errorf("graphqlconfig: %s: %w", key, config.ErrKeyNotSet)
+	}
+	return val, nil
+}
+
+// Lookup : Return the value for key from the latest successful query, and whether it's set.
+func (g *Getter) Lookup(key string) (string, bool) {
+	return g.current().Lookup(key)
+}
+
+// Keys : Return the keys from the latest successful query.
+func (g *Getter) Keys() []string {
+	return g.current().Keys()
+}
+
+// Snapshot : Return the values of the latest successful query, which won't change as the query runs again.
+func (g *Getter) Snapshot() config.Getter {
+	return g.current()
+}
+
+// Get : Return the value for key from the latest successful query.
+func (g *Getter) Get(key string) string {
+	return g.current().Get(key)
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (g *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(g, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (g *Getter) GetStrings(key string) []string {
+	return config.SplitStrings(g.Get(key))
+}
+
+// MustGet will panic if the key is not present or empty.
+func (g *Getter) MustGet(key string) string {
+	return config.MustGet(g, key)
+}
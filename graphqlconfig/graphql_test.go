@@ -0,0 +1,156 @@
+package graphqlconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/efixler/config"
+)
+
+type fakeAPI struct {
+	lock     sync.Mutex
+	response string
+	status   int
+	requests []map[string]any
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"errors": [{"message": "not authenticated"}]}`))
+		return
+	}
+	var req map[string]any
+	json.NewDecoder(r.Body).Decode(&req)
+	f.requests = append(f.requests, req)
+	if f.status != 0 {
+		w.WriteHeader(f.status)
+	}
+	w.Write([]byte(f.response))
+}
+
+func (f *fakeAPI) update(status int, response string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.status, f.response = status, response
+}
+
+func TestGraphQLGetter(t *testing.T) {
+	api := &fakeAPI{response: `{"data": {"appConfig": {"db": {"host": "db1", "port": 5432}, "hosts": ["a", "b"]}}}`}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	query := `query($env: String!) { appConfig(env: $env) { db { host port } hosts } }`
+	g, err := NewGraphQLGetter(server.URL, query, nil, WithHeader("Authorization", "Bearer token"),
+		WithVariables(map[string]any{"env": "prod"}), WithRefreshInterval(5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	if v := g.Get("db.host"); v != "db1" {
+		t.Errorf("Expected 'db1', got '%s'", v)
+	}
+	if v := g.Get("db.port"); v != "5432" {
+		t.Errorf("Expected '5432', got '%s'", v)
+	}
+	if v := g.GetStrings("hosts"); len(v) != 2 {
+		t.Errorf("Expected [a b], got %q", v)
+	}
+	api.lock.Lock()
+	req := api.requests[0]
+	api.lock.Unlock()
+	if req["query"] != query || req["variables"].(map[string]any)["env"] != "prod" {
+		t.Errorf("Unexpected request %v", req)
+	}
+
+	changes := g.Watch()
+	api.update(0, `{"data": {"settings": [{"key": "db.host", "value": "db2"}, {"key": "debug", "value": true}]}}`)
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a change notification")
+	}
+	if v, err := g.GetErr("db.host"); err != nil || v != "db2" {
+		t.Errorf("Expected 'db2' from the key/value list, got '%s' (%v)", v, err)
+	}
+	if v := g.Get("debug"); v != "true" {
+		t.Errorf("Expected 'true', got '%s'", v)
+	}
+
+	api.update(0, `{"data": null, "errors": [{"message": "resolver failed", "path": ["settings"]}]}`)
+	select {
+	case err := <-g.Errors():
+		var gqlErrs Errors
+		if !errors.As(err, &gqlErrs) || !strings.Contains(err.Error(), "resolver failed (at [settings])") {
+			t.Errorf("Expected the GraphQL errors, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a refresh error")
+	}
+	if _, err := g.GetErr("db.host"); err == nil {
+		t.Error("Expected GetErr to report the failed query")
+	}
+	if v := g.Get("db.host"); v != "db2" {
+		t.Errorf("Expected the previous value to be served, got '%s'", v)
+	}
+}
+
+func TestGraphQLGetterErrors(t *testing.T) {
+	api := &fakeAPI{response: `{"data": {"a": "1", "b": "2"}}`}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	if _, err := NewGraphQLGetter(server.URL, "{ a b }", nil); err == nil || !strings.Contains(err.Error(), "not authenticated") {
+		t.Errorf("Expected the auth error, got %v", err)
+	}
+	auth := WithHeader("Authorization", "Bearer token")
+	g, err := NewGraphQLGetter(server.URL, "{ a b }", server.Client(), auth)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g.Close()
+	if v := g.Get("b"); v != "2" {
+		t.Errorf("Expected '2' with several top-level fields, got '%s'", v)
+	}
+	if _, err := g.GetErr("c"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+	api.update(http.StatusBadGateway, "upstream down")
+	if _, err := NewGraphQLGetter(server.URL, "{ a }", nil, auth); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("Expected a status error, got %v", err)
+	}
+	api.update(0, `{"data": null}`)
+	if _, err := NewGraphQLGetter(server.URL, "{ a }", nil, auth); err == nil {
+		t.Error("Expected an error for a response without data")
+	}
+	if _, err := NewGraphQLGetter(server.URL, "{ a }", nil, auth, WithRefreshInterval(0)); err == nil {
+		t.Error("Expected an error for a zero refresh interval")
+	}
+}
+
+func TestFlattenSingleField(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected config.Map
+	}{
+		{`{"maintenanceMode": true}`, config.Map{"maintenanceMode": "true"}},
+		{`{"regions": ["us", "eu"]}`, config.Map{"regions": "us,eu"}},
+		{`{"appConfig": {"dbHost": "db1"}}`, config.Map{"dbHost": "db1"}},
+		{`{"settings": [{"key": "a", "value": 1}]}`, config.Map{"a": "1"}},
+	}
+	for _, test := range tests {
+		got, err := flatten(json.RawMessage(test.data))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.data, err)
+		} else if !maps.Equal(got, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.data, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+// Package grpcconfig provides a config.Getter over config streamed from a control plane over gRPC, using the
+// ConfigService contract in config.proto. It's a separate package to keep the gRPC dependency optional.
+package grpcconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/efixler/config"
+)
+
+const (
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+)
+
+// WatchMethod is the full name of the streaming RPC.
+const WatchMethod = "/efixler.config.v1.ConfigService/Watch"
+
+// The descriptors of config.proto, built here so the client works with dynamic messages and needs no generated code.
+var (
+	fileDescriptor  = buildFileDescriptor()
+	watchRequest    = fileDescriptor.Messages().ByName("WatchRequest")
+	configSnapshot  = fileDescriptor.Messages().ByName("ConfigSnapshot")
+	knownVersion    = watchRequest.Fields().ByName("known_version")
+	snapshotVersion = configSnapshot.Fields().ByName("version")
+	snapshotValues  = configSnapshot.Fields().ByName("values")
+)
+
+func buildFileDescriptor() protoreflect.FileDescriptor {
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:   typ.Enum(),
+		}
+	}
+	values := field("values", 2, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE)
+	values.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	values.TypeName = proto.String(".efixler.config.v1.ConfigSnapshot.ValuesEntry")
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("efixler/config/v1/config.proto"),
+		Package: proto.String("efixler.config.v1"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:  proto.String("WatchRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{field("known_version", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64)},
+			},
+			{
+				Name:  proto.String("ConfigSnapshot"),
+				Field: []*descriptorpb.FieldDescriptorProto{field("version", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64), values},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name: proto.String("ValuesEntry"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+						field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+					},
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				}},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("ConfigService"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:            proto.String("Watch"),
+				InputType:       proto.String(".efixler.config.v1.WatchRequest"),
+				OutputType:      proto.String(".efixler.config.v1.ConfigSnapshot"),
+				ServerStreaming: proto.Bool(true),
+			}},
+		}},
+	}, nil)
+	if err != nil {
+		panic(err)
+	}
+	return fd
+}
+
+// Getter holds the latest config snapshot streamed from the control plane. See NewGRPCGetter.
+type Getter struct {
+	config.Notifier
+	config.ErrorReporter
+	conn      grpc.ClientConnInterface
+	values    atomic.Pointer[config.Map]
+	version   atomic.Int64
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+	done      chan struct{}
+	backoff   time.Duration
+}
+
+// NewGRPCGetter : Return a Getter that follows the config streamed by the ConfigService on conn (usually a
+// *grpc.ClientConn). The contract is in config.proto: the client calls Watch with the version it has, and the server
+// streams complete snapshots of every value, one straight away and another after each change. An empty value is
+// sent like any other; a key missing from a snapshot is unset.
+//
+// The first snapshot is received before NewGRPCGetter returns, and a failure there is returned. After that the
+// Getter is a Watcher, notified after each snapshot with a new version. When the stream fails or ends it's reopened
+// with exponential backoff (1s up to a minute) while the previous values stay in place; each failure is sent to the
+// Errors() channel, with a count of consecutive failures so persistent problems can be told from blips.
+// Call Close to stop streaming.
+func NewGRPCGetter(conn grpc.ClientConnInterface) (*Getter, error) {
+	return newGRPCGetter(conn, minBackoff)
+}
+
+func newGRPCGetter(conn grpc.ClientConnInterface, backoff time.Duration) (*Getter, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &Getter{conn: conn, cancel: cancel, done: make(chan struct{}), backoff: backoff}
+	stream, err := g.open(ctx)
+	if err == nil {
+		_, err = g.recv(stream)
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	go g.run(ctx, stream)
+	return g, nil
+}
+
+func (g *Getter) run(ctx context.Context, stream grpc.ClientStream) {
+	defer close(g.done)
+	backoff, failures := g.backoff, 0
+	for {
+		var err error
+		if stream == nil {
+			stream, err = g.open(ctx)
+		}
+		if err == nil {
+			var changed bool
+			if changed, err = g.recv(stream); err == nil {
+				backoff, failures = g.backoff, 0
+				if changed {
+					g.Notify()
+				}
+				continue
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		stream = nil
+		failures++
+		g.Report(fmt.Errorf("%w (%d consecutive failures)", err, failures))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// open starts a Watch call from the version the Getter has.
+func (g *Getter) open(ctx context.Context) (grpc.ClientStream, error) {
+	stream, err := g.conn.NewStream(ctx, &grpc.StreamDesc{StreamName: "Watch", ServerStreams: true}, WatchMethod)
+	if err != nil {
+		return nil, fmt.Errorf("grpcconfig: opening Watch stream: %w", err)
+	}
+	req := dynamicpb.NewMessage(watchRequest)
+	req.Set(knownVersion, protoreflect.ValueOfInt64(g.version.Load()))
+	if err := stream.SendMsg(req); err != nil {
+		return nil, fmt.Errorf("grpcconfig: sending Watch request: %w", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, fmt.Errorf("grpcconfig: sending Watch request: %w", err)
+	}
+	return stream, nil
+}
+
+// recv waits for the next snapshot and swaps it in, reporting whether it has a new version.
+func (g *Getter) recv(stream grpc.ClientStream) (bool, error) {
+	msg := dynamicpb.NewMessage(configSnapshot)
+	if err := stream.RecvMsg(msg); errors.Is(err, io.EOF) {
+		return false, errors.New("grpcconfig: Watch stream ended")
+	} else if err != nil {
+		return false, fmt.Errorf("grpcconfig: receiving snapshot: %w", err)
+	}
+	values := make(config.Map)
+	msg.Get(snapshotValues).Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		values[k.String()] = v.String()
+		return true
+	})
+	version := msg.Get(snapshotVersion).Int()
+	changed := g.values.Load() == nil || version != g.version.Load()
+	g.values.Store(&values)
+	g.version.Store(version)
+	return changed, nil
+}
+
+// Version : Return the version of the current snapshot.
+func (g *Getter) Version() int64 {
+	return g.version.Load()
+}
+
+// Close : Stop streaming. The Getter keeps serving the last snapshot it received.
+func (g *Getter) Close() error {
+	g.closeOnce.Do(func() {
+		g.cancel()
+		<-g.done
+	})
+	return nil
+}
+
+func (g *Getter) current() config.Map {
+	return *g.values.Load()
+}
+
+// Snapshot : Return the values of the current snapshot, which won't change as new ones arrive.
+func (g *Getter) Snapshot() config.Getter {
+	return g.current()
+}
+
+// GetErr : Return the value for key, or an error wrapping config.ErrKeyNotSet.
+func (g *Getter) GetErr(key string) (string, error) {
+	val, ok := g.current()[key]
+	if !ok {
+		return "", fmt.Errorf("grpcconfig: %s: %w", key, config.ErrKeyNotSet)
+	}
+	return val, nil
+}
+
+// Lookup : Return the value for key, and whether the current snapshot has it.
+func (g *Getter) Lookup(key string) (string, bool) {
+	return g.current().Lookup(key)
+}
+
+// Keys : Return the keys of the current snapshot.
+func (g *Getter) Keys() []string {
+	return g.current().Keys()
+}
+
+// Get : Return the value for key.
+func (g *Getter) Get(key string) string {
+	return g.current().Get(key)
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (g *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(g, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (g *Getter) GetStrings(key string) []string {
+	return config.SplitStrings(g.Get(key))
+}
+
+// MustGet will panic if the key is not present or empty.
+func (g *Getter) MustGet(key string) string {
+	return config.MustGet(g, key)
+}
@@ -0,0 +1,149 @@
+package grpcconfig
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/efixler/config"
+)
+
+type snapshot struct {
+	version int64
+	values  map[string]string
+}
+
+// fakeControlPlane serves ConfigService.Watch, sending each snapshot from its channel; a nil snapshot ends the stream.
+type fakeControlPlane struct {
+	snapshots chan *snapshot
+	requests  chan int64
+}
+
+func (f *fakeControlPlane) watch(_ any, stream grpc.ServerStream) error {
+	req := dynamicpb.NewMessage(watchRequest)
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+	f.requests <- req.Get(knownVersion).Int()
+	for {
+		select {
+		case s := <-f.snapshots:
+			if s == nil {
+				return status.Error(codes.Unavailable, "restarting")
+			}
+			msg := dynamicpb.NewMessage(configSnapshot)
+			msg.Set(snapshotVersion, protoreflect.ValueOfInt64(s.version))
+			values := msg.Mutable(snapshotValues).Map()
+			for k, v := range s.values {
+				values.Set(protoreflect.ValueOfString(k).MapKey(), protoreflect.ValueOfString(v))
+			}
+			if err := stream.SendMsg(msg); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func startControlPlane(t *testing.T) (*fakeControlPlane, *grpc.ClientConn) {
+	f := &fakeControlPlane{snapshots: make(chan *snapshot, 4), requests: make(chan int64, 4)}
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "efixler.config.v1.ConfigService",
+		HandlerType: (*any)(nil),
+		Streams:     []grpc.StreamDesc{{StreamName: "Watch", Handler: f.watch, ServerStreams: true}},
+	}, struct{}{})
+	go server.Serve(lis)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+	return f, conn
+}
+
+func TestGRPCGetter(t *testing.T) {
+	f, conn := startControlPlane(t)
+	f.snapshots <- &snapshot{version: 1, values: map[string]string{"HOSTS": "a, b", "MODE": "blue"}}
+	g, err := newGRPCGetter(conn, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	if v := <-f.requests; v != 0 {
+		t.Errorf("Expected the first request to have no version, got %d", v)
+	}
+	if v := g.GetStrings("HOSTS"); len(v) != 2 || g.Version() != 1 {
+		t.Errorf("Expected [a b] at version 1, got %q at %d", v, g.Version())
+	}
+
+	changes := g.Watch()
+	f.snapshots <- &snapshot{version: 2, values: map[string]string{"MODE": "green"}}
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a change notification")
+	}
+	if v := g.Get("MODE"); v != "green" {
+		t.Errorf("Expected 'green', got '%s'", v)
+	}
+	if _, err := g.GetErr("HOSTS"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected a key missing from the snapshot to be unset, got %v", err)
+	}
+
+	f.snapshots <- nil
+	select {
+	case err := <-g.Errors():
+		if err == nil {
+			t.Error("Expected a stream error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a stream error")
+	}
+	select {
+	case v := <-f.requests:
+		if v != 2 {
+			t.Errorf("Expected the reconnect to send version 2, got %d", v)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a reconnect")
+	}
+	f.snapshots <- &snapshot{version: 3, values: map[string]string{"MODE": "red"}}
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a change notification after reconnecting")
+	}
+	if v := g.Get("MODE"); v != "red" {
+		t.Errorf("Expected 'red', got '%s'", v)
+	}
+}
+
+func TestGRPCGetterUnavailable(t *testing.T) {
+	cc, err := grpc.NewClient("passthrough:///nowhere", grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return nil, errors.New("refused") }))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer cc.Close()
+	if _, err := NewGRPCGetter(cc); status.Code(errors.Unwrap(err)) != codes.Unavailable {
+		t.Errorf("Expected an Unavailable error, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"strings"
+)
+
+// WithHierarchy : Wrap a Getter so that lookups of a hierarchical key fall back to less-specific ancestors
+// when the key is unset or empty. Keys are split into segments on sep; the leaf (last) segment is kept and
+// the scope segments in front of it are dropped one at a time, innermost first. With sep ".", the order for
+// "service.api.timeout" is:
+//
+//	service.api.timeout
+//	service.timeout
+//	timeout
+//
+// The first non-empty value wins. The fallback applies to all the accessors; GetStrings splits the
+// value found by Get, via the wrapped Getter's GetStrings.
+func WithHierarchy(g Getter, sep string) Getter {
+	return &hierarchy{g: g, sep: sep}
+}
+
+type hierarchy struct {
+	g   Getter
+	sep string
+}
+
+// resolve returns the most specific candidate for key with a non-empty value.
+func (h *hierarchy) resolve(key string) (string, string) {
+	parts := strings.Split(key, h.sep)
+	leaf := parts[len(parts)-1]
+	for n := len(parts) - 1; n >= 0; n-- {
+		candidate := strings.Join(append(parts[:n:n], leaf), h.sep)
+		if v := h.g.Get(candidate); v != "" {
+			return candidate, v
+		}
+	}
+	return key, ""
+}
+
+func (h *hierarchy) Get(key string) string {
+	_, v := h.resolve(key)
+	return v
+}
+
+func (h *hierarchy) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(h, key, dflt)
+}
+
+func (h *hierarchy) GetStrings(key string) []string {
+	candidate, _ := h.resolve(key)
+	return h.g.GetStrings(candidate)
+}
+
+func (h *hierarchy) MustGet(key string) string {
+	return MustGet(h, key)
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithHierarchy(t *testing.T) {
+	c := WithHierarchy(Map{
+		"service.api.timeout": "5s",
+		"service.timeout":     "10s",
+		"timeout":             "30s",
+		"service.hosts":       "a, b",
+	}, ".")
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"service.api.timeout", "5s"},
+		{"service.db.timeout", "10s"},
+		{"other.db.timeout", "30s"},
+		{"other.retries", ""},
+	}
+	for _, test := range tests {
+		if v := c.Get(test.key); v != test.expected {
+			t.Errorf("Key %s: expected '%s', got '%s'", test.key, test.expected, v)
+		}
+	}
+	if v := c.GetStrings("service.api.hosts"); !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("Expected inherited list [a b], got %q", v)
+	}
+}
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HTTPGetter holds a config document fetched from a URL and refreshed with conditional requests. See NewHTTPGetter.
+type HTTPGetter struct {
+	Notifier
+	ErrorReporter
+	url          string
+	client       *http.Client
+	interval     time.Duration
+	values       atomic.Pointer[Map]
+	lock         sync.Mutex
+	etag         string
+	lastModified string
+	refreshed    time.Time
+	cancel       context.CancelFunc
+	closeOnce    sync.Once
+	done         chan struct{}
+	now          func() time.Time
+}
+
+// NewHTTPGetter : Return a Getter over the config document at url, fetched again every interval (which must be
+// positive). A nil client means http.DefaultClient. Documents are JSON (see FlattenValue) when the Content-Type is
+// application/json, and KEY=VALUE lines otherwise, and may be up to 32MB.
+//
+// Refreshes are conditional, so a large document that rarely changes costs a round trip rather than a download and
+// a parse: each refresh sends the ETag and Last-Modified of the current document as If-None-Match and
+// If-Modified-Since (when the server supplied them). A 304 Not Modified keeps serving the cached document and
+// restarts the wait until the next refresh, just as a 200 does; only a 200 replaces the document, and notifies
+// Watch() channels. ETag and LastRefresh report on the current document, and ModTime its Last-Modified time.
+//
+// The first document is fetched before NewHTTPGetter returns, and a failure there is returned. After that a failed
+// refresh is sent to the Errors() channel and tried again at the next interval, while the cached document stays in
+// place. Call Close to stop refreshing.
+func NewHTTPGetter(url string, client *http.Client, interval time.Duration) (*HTTPGetter, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("config: refresh interval for %s must be positive, got %v", url, interval)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &HTTPGetter{
+		url:      url,
+		client:   client,
+		interval: interval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		now:      time.Now,
+	}
+	if _, err := h.refresh(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+	go h.run(ctx)
+	return h, nil
+}
+
+func (h *HTTPGetter) run(ctx context.Context) {
+	defer close(h.done)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(h.interval):
+		}
+		changed, err := h.refresh(ctx)
+		if ctx.Err() != nil {
+			return
+		} else if err != nil {
+			h.Report(err)
+		} else if changed {
+			h.Notify()
+		}
+	}
+}
+
+// refresh makes a conditional request, swapping in the new document if there is one.
+func (h *HTTPGetter) refresh(ctx context.Context) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url, nil)
+	if err != nil {
+		return false, fmt.Errorf("config: fetching %s: %w", h.url, err)
+	}
+	h.lock.Lock()
+	if h.etag != "" {
+		req.Header.Set("If-None-Match", h.etag)
+	}
+	if h.lastModified != "" {
+		req.Header.Set("If-Modified-Since", h.lastModified)
+	}
+	h.lock.Unlock()
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("config: fetching %s: %w", h.url, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && h.values.Load() != nil:
+		h.lock.Lock()
+		h.refreshed = h.now()
+		h.lock.Unlock()
+		return false, nil
+	case resp.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("config: fetching %s: unexpected status %s", h.url, resp.Status)
+	}
+	data, err := readDocument(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("config: fetching %s: %w", h.url, err)
+	}
+	values, err := ParseFormat(documentFormat(resp.Header), data)
+	if err != nil {
+		return false, fmt.Errorf("config: fetching %s: %w", h.url, err)
+	}
+	h.lock.Lock()
+	h.values.Store(&values)
+	h.etag, h.lastModified, h.refreshed = resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"), h.now()
+	h.lock.Unlock()
+	return true, nil
+}
+
+// ETag : Return the ETag of the current document, or "" if the server didn't send one.
+func (h *HTTPGetter) ETag() string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.etag
+}
+
+// LastRefresh : Return when the current document was last confirmed fresh, by a 200 or a 304.
+func (h *HTTPGetter) LastRefresh() time.Time {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.refreshed
+}
+
+// Close : Stop refreshing, abandoning any request in flight. The Getter keeps serving the current document.
+func (h *HTTPGetter) Close() error {
+	h.closeOnce.Do(func() {
+		h.cancel()
+		<-h.done
+	})
+	return nil
+}
+
+func (h *HTTPGetter) current() Map {
+	return *h.values.Load()
+}
+
+// Snapshot : Return the values of the current document, which won't change as new documents arrive.
+func (h *HTTPGetter) Snapshot() Getter {
+	return h.current()
+}
+
+// Get : Return the value for key from the current document.
+func (h *HTTPGetter) Get(key string) string {
+	return h.current().Get(key)
+}
+
+// Lookup : Return the value for key from the current document, and whether it's present.
+func (h *HTTPGetter) Lookup(key string) (string, bool) {
+	return h.current().Lookup(key)
+}
+
+// Keys : Return the keys of the current document.
+func (h *HTTPGetter) Keys() []string {
+	return h.current().Keys()
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (h *HTTPGetter) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(h, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (h *HTTPGetter) GetStrings(key string) []string {
+	return h.current().GetStrings(key)
+}
+
+// ModTime : Return the Last-Modified time of the current document, if key is in it and the server sent one.
+func (h *HTTPGetter) ModTime(key string) (time.Time, bool) {
+	if _, ok := h.current()[key]; !ok {
+		return time.Time{}, false
+	}
+	h.lock.Lock()
+	lastModified := h.lastModified
+	h.lock.Unlock()
+	t, err := http.ParseTime(lastModified)
+	return t, err == nil
+}
+
+// MustGet will panic if the key is not present or empty.
+func (h *HTTPGetter) MustGet(key string) string {
+	return MustGet(h, key)
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHTTPGetterConditionalRefresh(t *testing.T) {
+	var lock sync.Mutex
+	body, etag, conditional, full := `{"db": {"host": "db1"}}`, `"v1"`, 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if r.Header.Get("If-None-Match") == etag && r.Header.Get("If-Modified-Since") == "Mon, 02 Jan 2006 15:04:05 GMT" {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	h, err := NewHTTPGetter(srv.URL, nil, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer h.Close()
+	if v := h.Get("db.host"); v != "db1" || h.ETag() != `"v1"` {
+		t.Errorf("Expected db1 with ETag \"v1\", got '%s' with %s", v, h.ETag())
+	}
+	if mt, ok := ModTime(h, "db.host"); !ok || !mt.Equal(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)) {
+		t.Errorf("Expected the Last-Modified time, got %v, %t", mt, ok)
+	}
+	changes := h.Watch()
+	first := h.LastRefresh()
+	deadline := time.Now().Add(2 * time.Second)
+	for !h.LastRefresh().After(first) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	lock.Lock()
+	if conditional == 0 || full != 1 {
+		t.Errorf("Expected 304s after the first fetch; got %d conditional and %d full responses", conditional, full)
+	}
+	body, etag = `{"db": {"host": "db2"}}`, `"v2"`
+	lock.Unlock()
+	if v := h.Get("db.host"); v != "db1" {
+		t.Errorf("Expected the cached document after a 304, got '%s'", v)
+	}
+
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a change notification")
+	}
+	if v := h.Get("db.host"); v != "db2" || h.ETag() != `"v2"` {
+		t.Errorf("Expected db2 with ETag \"v2\", got '%s' with %s", v, h.ETag())
+	}
+}
+
+func TestHTTPGetterErrors(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	if _, err := NewHTTPGetter(srv.URL, nil, time.Minute); err == nil {
+		t.Error("Expected an error for a 404")
+	}
+	if _, err := NewHTTPGetter(srv.URL, nil, 0); err == nil {
+		t.Error("Expected an error for a zero interval")
+	}
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// INIInterpolation selects how values in an INI file may reference other values.
+type INIInterpolation int
+
+const (
+	// INIBasicInterpolation is Python configparser's default: %(name)s is replaced by option name of the same
+	// section (or the DEFAULT section), and %% is a literal percent sign.
+	INIBasicInterpolation INIInterpolation = iota
+	// ININoInterpolation leaves values as they're written.
+	ININoInterpolation
+	// INIExtendedInterpolation is configparser's ExtendedInterpolation: ${name} is replaced by option name of the
+	// same section (or DEFAULT), ${section:name} by an option of another section, and $$ is a literal dollar sign.
+	INIExtendedInterpolation
+)
+
+// maxINIInterpolationDepth is configparser's MAX_INTERPOLATION_DEPTH.
+const maxINIInterpolationDepth = 10
+
+const iniDefaultSection = "DEFAULT"
+
+// NewINIGetter : Read an INI file, in the dialect of Python's configparser, and return a Getter holding its values,
+// with references between values resolved according to mode. The file is read once.
+//
+// Options of a [section] are keyed as "section.option"; options of [DEFAULT], and any that appear before the first
+// section, are keyed by name alone, and are also inherited by every section that doesn't set them, as in configparser.
+// Like configparser, option names are lowercased while section names keep their case. Either = or : separates an
+// option from its value, lines starting with # or ; are comments, and indented lines continue the previous value.
+//
+// Interpolation follows configparser too; see INIInterpolation for the syntax of each mode. A reference to a missing
+// option, a chain of references more than 10 deep (as with a cycle), or a value that expands to more than 32MB is an
+// error naming the option.
+func NewINIGetter(path string, mode INIInterpolation) (Getter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	values, err := ParseINI(data, mode)
+	if err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// ParseINI : Parse INI data as described for NewINIGetter. It's registered as the "ini" format, with INIBasicInterpolation.
+func ParseINI(data []byte, mode INIInterpolation) (Map, error) {
+	sections, order, err := parseINISections(data)
+	if err != nil {
+		return nil, err
+	}
+	defaults := sections[iniDefaultSection]
+	for _, section := range order {
+		if section == iniDefaultSection {
+			continue
+		}
+		for option, val := range defaults {
+			if _, ok := sections[section][option]; !ok {
+				sections[section][option] = val
+			}
+		}
+	}
+	in := &iniInterpolator{sections: sections, mode: mode, resolved: make(map[[2]string]string)}
+	rval := make(Map)
+	for _, section := range order {
+		for option := range sections[section] {
+			val, err := in.resolve(section, option, 1)
+			if err != nil {
+				return nil, err
+			}
+			if section == iniDefaultSection {
+				rval[option] = val
+			} else {
+				rval[section+"."+option] = val
+			}
+		}
+	}
+	return rval, nil
+}
+
+func parseINISections(data []byte) (map[string]map[string]string, []string, error) {
+	sections := map[string]map[string]string{iniDefaultSection: {}}
+	order := []string{iniDefaultSection}
+	section, option := iniDefaultSection, ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+			continue
+		case option != "" && (raw[0] == ' ' || raw[0] == '\t'):
+			sections[section][option] += "\n" + line
+		case line[0] == '[':
+			if !strings.HasSuffix(line, "]") || len(line) < 3 {
+				return nil, nil, fmt.Errorf("line %d: malformed section header %q", lineNo, line)
+			}
+			section, option = line[1:len(line)-1], ""
+			if _, ok := sections[section]; !ok {
+				sections[section] = make(map[string]string)
+				order = append(order, section)
+			}
+		default:
+			i := strings.IndexAny(line, "=:")
+			if i <= 0 {
+				return nil, nil, fmt.Errorf("line %d: expected option = value", lineNo)
+			}
+			option = strings.ToLower(strings.TrimSpace(line[:i]))
+			sections[section][option] = strings.TrimSpace(line[i+1:])
+		}
+	}
+	return sections, order, scanner.Err()
+}
+
+var (
+	iniBasicRef    = regexp.MustCompile(`%%|%\(([^)]*)\)s|%`)
+	iniExtendedRef = regexp.MustCompile(`\$\$|\$\{([^}]*)\}|\$`)
+)
+
+// iniInterpolator resolves references between the values of an INI file, resolving each option once: without that,
+// a few options that each reference the previous one several times would take exponential time.
+type iniInterpolator struct {
+	sections map[string]map[string]string
+	mode     INIInterpolation
+	// resolved holds the interpolated value of each option, keyed by section and option.
+	resolved map[[2]string]string
+}
+
+// resolve returns the interpolated value of option in section, which must be set there or in DEFAULT.
+func (in *iniInterpolator) resolve(section, option string, depth int) (string, error) {
+	key := [2]string{section, option}
+	if val, ok := in.resolved[key]; ok {
+		return val, nil
+	}
+	raw, ok := in.sections[section][option]
+	if !ok {
+		raw = in.sections[iniDefaultSection][option]
+	}
+	val, err := in.interpolate(section, option, raw, depth)
+	if err != nil {
+		return "", err
+	}
+	in.resolved[key] = val
+	return val, nil
+}
+
+func (in *iniInterpolator) interpolate(section, option, val string, depth int) (string, error) {
+	if in.mode == ININoInterpolation {
+		return val, nil
+	}
+	if depth > maxINIInterpolationDepth {
+		return "", fmt.Errorf("[%s] %s: interpolation deeper than %d levels (is there a reference cycle?)",
+			section, option, maxINIInterpolationDepth)
+	}
+	pattern, literal := iniBasicRef, "%"
+	if in.mode == INIExtendedInterpolation {
+		pattern, literal = iniExtendedRef, "$"
+	}
+	var err error
+	size := len(val)
+	rval := pattern.ReplaceAllStringFunc(val, func(ref string) string {
+		if err != nil {
+			return ""
+		}
+		if ref == literal+literal {
+			return literal
+		}
+		m := pattern.FindStringSubmatch(ref)
+		if ref == literal || m[1] == "" {
+			err = fmt.Errorf("[%s] %s: bad interpolation syntax in %q", section, option, val)
+			return ""
+		}
+		refSection, refOption := section, strings.ToLower(m[1])
+		if in.mode == INIExtendedInterpolation {
+			if s, o, ok := strings.Cut(m[1], ":"); ok {
+				refSection, refOption = s, strings.ToLower(o)
+			}
+		}
+		_, ok := in.sections[refSection][refOption]
+		if !ok {
+			_, ok = in.sections[iniDefaultSection][refOption]
+		}
+		if !ok {
+			err = fmt.Errorf("[%s] %s: reference to missing option %q", section, option, m[1])
+			return ""
+		}
+		var resolved string
+		if resolved, err = in.resolve(refSection, refOption, depth+1); err != nil {
+			return ""
+		}
+		if size += len(resolved); size > maxDocumentSize {
+			err = fmt.Errorf("[%s] %s: interpolated value is larger than %d bytes", section, option, maxDocumentSize)
+			return ""
+		}
+		return resolved
+	})
+	return rval, err
+}
+
+func init() {
+	RegisterFormat("ini", func(data []byte) (map[string]string, error) {
+		return ParseINI(data, INIBasicInterpolation)
+	})
+}
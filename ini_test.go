@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testINI = `
+; app settings
+[DEFAULT]
+base = /srv/app
+Log_Dir = %(base)s/logs
+
+[server]
+port: 8080
+motd = 100%% up
+description = first line
+  second line
+
+[paths]
+base = /opt/app
+data = %(base)s/data
+`
+
+func TestParseINIBasic(t *testing.T) {
+	m, err := ParseINI([]byte(testINI), INIBasicInterpolation)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tests := map[string]string{
+		"base":               "/srv/app",
+		"log_dir":            "/srv/app/logs",
+		"server.port":        "8080",
+		"server.motd":        "100% up",
+		"server.log_dir":     "/srv/app/logs",
+		"server.description": "first line\nsecond line",
+		"paths.data":         "/opt/app/data",
+		"paths.log_dir":      "/opt/app/logs",
+	}
+	for key, expected := range tests {
+		if v := m.Get(key); v != expected {
+			t.Errorf("%s: expected %q, got %q", key, expected, v)
+		}
+	}
+}
+
+func TestParseINIExtended(t *testing.T) {
+	m, err := ParseINI([]byte(`
+[common]
+root = /srv
+[app]
+logs = ${common:root}/logs
+path = ${logs}/app.log
+price = $$5
+`), INIExtendedInterpolation)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := m.Get("app.path"); v != "/srv/logs/app.log" {
+		t.Errorf("Expected '/srv/logs/app.log', got '%s'", v)
+	}
+	if v := m.Get("app.price"); v != "$5" {
+		t.Errorf("Expected '$5', got '%s'", v)
+	}
+}
+
+func TestParseINIErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		msg  string
+	}{
+		{"[a]\nx = %(y)s\ny = %(x)s\n", "deeper than 10"},
+		{"[a]\nx = %(missing)s\n", "missing option"},
+		{"[a]\nx = 50%\n", "bad interpolation"},
+		{"[a\nx = 1\n", "line 1"},
+		{"[a]\njust a line\n", "line 2"},
+	}
+	for _, test := range tests {
+		if _, err := ParseINI([]byte(test.data), INIBasicInterpolation); err == nil || !strings.Contains(err.Error(), test.msg) {
+			t.Errorf("%q: expected an error containing %q, got %v", test.data, test.msg, err)
+		}
+	}
+	if m, err := ParseINI([]byte("[a]\nx = 50%\n"), ININoInterpolation); err != nil || m.Get("a.x") != "50%" {
+		t.Errorf("Expected raw value with no interpolation, got '%s' (%v)", m.Get("a.x"), err)
+	}
+}
+
+func TestNewINIGetter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.ini")
+	os.WriteFile(path, []byte(testINI), 0600)
+	c, err := NewINIGetter(path, INIBasicInterpolation)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := c.Get("paths.data"); v != "/opt/app/data" {
+		t.Errorf("Expected '/opt/app/data', got '%s'", v)
+	}
+	if m, err := ParseFormat("ini", []byte(testINI)); err != nil || m.Get("log_dir") != "/srv/app/logs" {
+		t.Errorf("Expected the ini format to be registered, got %v (%v)", m, err)
+	}
+}
+
+func TestNewINIGetterExpansionBomb(t *testing.T) {
+	// Each option references the previous one ten times, so the last would expand to 300MB.
+	var b strings.Builder
+	b.WriteString("[lol]\na = lollollollollollollollollollol\n")
+	for c := 'b'; c <= 'h'; c++ {
+		fmt.Fprintf(&b, "%c = %s\n", c, strings.Repeat(fmt.Sprintf("%%(%c)s", c-1), 10))
+	}
+	path := filepath.Join(t.TempDir(), "lol.ini")
+	os.WriteFile(path, []byte(b.String()), 0600)
+	if _, err := NewINIGetter(path, INIBasicInterpolation); err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("Expected an error for a value that expands past the size cap, got %v", err)
+	}
+	// Up to g everything fits, and each option is expanded once rather than once per reference to it.
+	data := b.String()
+	m, err := ParseINI([]byte(data[:strings.Index(data, "h = ")]), INIBasicInterpolation)
+	if err != nil || len(m.Get("lol.g")) != 30_000_000 {
+		t.Errorf("Expected g to expand to 30MB, got %d bytes (%v)", len(m.Get("lol.g")), err)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/efixler/config/jwtconfig
+
+go 1.24.0
+
+require (
+	github.com/efixler/config v0.0.0-00010101000000-000000000000
+	github.com/go-jose/go-jose/v4 v4.1.5
+)
+
+replace github.com/efixler/config => ../
@@ -0,0 +1,283 @@
+// Package jwtconfig provides a config.Getter over config distributed as a signed JWT, verified against the signing
+// keys a control plane publishes as a JWKS. It's a separate package to keep the JOSE dependency optional.
+package jwtconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+
+	"github.com/efixler/config"
+)
+
+// DefaultRefreshInterval is how often the token is fetched again, unless overridden with WithRefreshInterval.
+const DefaultRefreshInterval = 5 * time.Minute
+
+// retryInterval is the longest wait before retrying after a failed refresh.
+const retryInterval = 10 * time.Second
+
+// maxBodySize bounds the size of a token or JWKS response.
+const maxBodySize = 1 << 20
+
+// signatureAlgorithms are the asymmetric algorithms accepted for config tokens. Symmetric (HS*) algorithms are not,
+// since the verification keys come from a public JWKS.
+var signatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// ErrExpired is returned by GetErr once the current token has expired and no valid replacement could be loaded.
+var ErrExpired = errors.New("jwtconfig: config token expired")
+
+// Option configures a Getter.
+type Option func(*Getter)
+
+// WithRefreshInterval : Fetch the token every interval instead of every DefaultRefreshInterval.
+// NewSignedConfigGetter returns an error if interval isn't positive.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(g *Getter) {
+		g.interval = interval
+	}
+}
+
+// Getter holds the verified claims of the latest config token. See NewSignedConfigGetter.
+type Getter struct {
+	config.Notifier
+	config.ErrorReporter
+	tokenURL  string
+	jwksURL   string
+	client    *http.Client
+	interval  time.Duration
+	current   atomic.Pointer[token]
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+	done      chan struct{}
+	now       func() time.Time
+}
+
+type token struct {
+	values  config.Map
+	expires time.Time
+}
+
+// NewSignedConfigGetter : Return a Getter over the claims of the config JWT served at tokenURL (as the response body,
+// in compact serialization), flattened as for config.NewClaimsGetter. A nil client means http.DefaultClient.
+//
+// Verification goes like this, and any failure rejects the token:
+//
+//  1. The JWKS at jwksURL is fetched fresh for every token, so key rotation on the control plane just works.
+//  2. The token's signature must verify against the JWKS key named by its "kid" header, with an asymmetric
+//     algorithm (RS*, PS*, ES* or EdDSA); unsigned and HMAC tokens are rejected.
+//  3. The token must have an "exp" claim, and exp and nbf (if present) must hold, with a minute of leeway for clock skew.
+//
+// The first token is loaded before NewSignedConfigGetter returns, and a failure there is returned. After that the token
+// is refreshed every DefaultRefreshInterval, or when it expires if that's sooner. A failed refresh is sent to the
+// Errors() channel and retried, and the previous claims stay in place until their token expires; from then until a
+// valid token is loaded, the Getter fails closed: Get returns "" for every key, and GetErr returns ErrExpired.
+// The Getter is a Watcher, notified when a new token changes the claims (other than exp, nbf, iat and jti) or
+// replaces an expired one. Call Close to stop refreshing.
+func NewSignedConfigGetter(tokenURL, jwksURL string, client *http.Client, opts ...Option) (*Getter, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &Getter{
+		tokenURL: tokenURL,
+		jwksURL:  jwksURL,
+		client:   client,
+		interval: DefaultRefreshInterval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.interval <= 0 {
+		cancel()
+		return nil, fmt.Errorf("jwtconfig: refresh interval must be positive, got %v", g.interval)
+	}
+	if err := g.refresh(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+	go g.run(ctx)
+	return g, nil
+}
+
+func (g *Getter) run(ctx context.Context) {
+	defer close(g.done)
+	wait := g.nextRefresh(g.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		previous := g.current.Load()
+		expired := !g.now().Before(previous.expires)
+		if err := g.refresh(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			g.Report(err)
+			wait = g.nextRefresh(retryInterval)
+			continue
+		}
+		if expired || claimsChanged(previous.values, g.current.Load().values) {
+			g.Notify()
+		}
+		wait = g.nextRefresh(g.interval)
+	}
+}
+
+// tokenClaims are registered claims that differ between tokens issued for the same config.
+var tokenClaims = []string{"exp", "nbf", "iat", "jti"}
+
+// claimsChanged reports whether the claims in a and b differ, other than in tokenClaims.
+func claimsChanged(a, b config.Map) bool {
+	a, b = maps.Clone(a), maps.Clone(b)
+	for _, key := range tokenClaims {
+		delete(a, key)
+		delete(b, key)
+	}
+	return !maps.Equal(a, b)
+}
+
+// nextRefresh is how long to wait before the next refresh: at most limit, and no later than the current token's expiry.
+func (g *Getter) nextRefresh(limit time.Duration) time.Duration {
+	wait := min(limit, g.interval)
+	if untilExpiry := g.current.Load().expires.Sub(g.now()); untilExpiry > 0 && untilExpiry < wait {
+		wait = untilExpiry
+	}
+	return wait
+}
+
+// refresh fetches the JWKS and the token, verifying and swapping in the token's claims.
+func (g *Getter) refresh(ctx context.Context) error {
+	var jwks jose.JSONWebKeySet
+	body, err := g.fetch(ctx, g.jwksURL)
+	if err == nil {
+		err = json.Unmarshal(body, &jwks)
+	}
+	if err != nil {
+		return fmt.Errorf("jwtconfig: loading JWKS from %s: %w", g.jwksURL, err)
+	}
+	body, err = g.fetch(ctx, g.tokenURL)
+	if err != nil {
+		return fmt.Errorf("jwtconfig: loading token from %s: %w", g.tokenURL, err)
+	}
+	tok, err := jwt.ParseSigned(strings.TrimSpace(string(body)), signatureAlgorithms)
+	if err != nil {
+		return fmt.Errorf("jwtconfig: parsing token: %w", err)
+	}
+	var std jwt.Claims
+	claims := make(map[string]any)
+	if err := tok.Claims(&jwks, &std, &claims); err != nil {
+		return fmt.Errorf("jwtconfig: verifying token: %w", err)
+	}
+	if std.Expiry == nil {
+		return errors.New("jwtconfig: token has no expiry")
+	}
+	if err := std.ValidateWithLeeway(jwt.Expected{Time: g.now()}, jwt.DefaultLeeway); err != nil {
+		return fmt.Errorf("jwtconfig: validating token: %w", err)
+	}
+	values := make(config.Map)
+	config.FlattenValue(values, "", claims)
+	g.current.Store(&token{values: values, expires: std.Expiry.Time().Add(jwt.DefaultLeeway)})
+	return nil
+}
+
+func (g *Getter) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxBodySize {
+		return nil, fmt.Errorf("response is larger than %d bytes", maxBodySize)
+	}
+	return body, nil
+}
+
+// Close : Stop refreshing. The Getter keeps serving the current claims until their token expires.
+func (g *Getter) Close() error {
+	g.closeOnce.Do(func() {
+		g.cancel()
+		<-g.done
+	})
+	return nil
+}
+
+// values returns the current claims, or an error if their token has expired.
+func (g *Getter) values() (config.Map, error) {
+	tok := g.current.Load()
+	if !g.now().Before(tok.expires) {
+		return config.Map{}, ErrExpired
+	}
+	return tok.values, nil
+}
+
+// GetErr : Return the claim for key, ErrExpired if the token has expired, or an error wrapping config.ErrKeyNotSet.
+func (g *Getter) GetErr(key string) (string, error) {
+	values, err := g.values()
+	if err != nil {
+		return "", err
+	}
+	val, ok := values[key]
+	if !ok {
+		return "", fmt.Errorf("jwtconfig: %s: %w", key, config.ErrKeyNotSet)
+	}
+	return val, nil
+}
+
+// Keys : Return the claim keys of the current token, or none if it has expired.
+func (g *Getter) Keys() []string {
+	values, _ := g.values()
+	return values.Keys()
+}
+
+// Get : Return the claim for key, or "" if it's not set or the token has expired.
+func (g *Getter) Get(key string) string {
+	values, _ := g.values()
+	return values.Get(key)
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (g *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(g, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited claim (or a claim array) as an []string.
+func (g *Getter) GetStrings(key string) []string {
+	return config.SplitStrings(g.Get(key))
+}
+
+// MustGet will panic if the claim is not present or empty, or the token has expired.
+func (g *Getter) MustGet(key string) string {
+	return config.MustGet(g, key)
+}
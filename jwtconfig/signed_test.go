@@ -0,0 +1,157 @@
+package jwtconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+type controlPlane struct {
+	lock  sync.Mutex
+	key   *ecdsa.PrivateKey
+	jwks  []byte
+	token string
+}
+
+func newControlPlane(t *testing.T) *controlPlane {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	jwks, _ := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &key.PublicKey, KeyID: "k1", Algorithm: string(jose.ES256), Use: "sig"}}})
+	return &controlPlane{key: key, jwks: jwks}
+}
+
+// sign issues a token with claims, signed by key, expiring at exp (none if zero).
+func (c *controlPlane) sign(t *testing.T, key *ecdsa.PrivateKey, claims map[string]any, exp time.Time) {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key}, (&jose.SignerOptions{}).WithHeader("kid", "k1"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	std := jwt.Claims{}
+	if !exp.IsZero() {
+		std.Expiry = jwt.NewNumericDate(exp)
+	}
+	tok, err := jwt.Signed(signer).Claims(std).Claims(claims).Serialize()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	c.lock.Lock()
+	c.token = tok
+	c.lock.Unlock()
+}
+
+func (c *controlPlane) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if r.URL.Path == "/jwks" {
+		w.Write(c.jwks)
+	} else {
+		w.Write([]byte(c.token))
+	}
+}
+
+func TestSignedConfigGetter(t *testing.T) {
+	cp := newControlPlane(t)
+	srv := httptest.NewServer(cp)
+	defer srv.Close()
+
+	cp.sign(t, cp.key, map[string]any{"db": map[string]any{"host": "db1"}, "regions": []string{"us", "eu"}}, time.Now().Add(time.Hour))
+	g, err := NewSignedConfigGetter(srv.URL+"/token", srv.URL+"/jwks", srv.Client(), WithRefreshInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	if v := g.Get("db.host"); v != "db1" {
+		t.Errorf("Expected 'db1', got '%s'", v)
+	}
+	if v := g.GetStrings("regions"); len(v) != 2 {
+		t.Errorf("Expected 2 regions, got %q", v)
+	}
+
+	// Refreshes that fetch the same claims (even in a re-signed token) don't notify.
+	watch := g.Watch()
+	cp.sign(t, cp.key, map[string]any{"db": map[string]any{"host": "db1"}, "regions": []string{"us", "eu"}}, time.Now().Add(2*time.Hour))
+	select {
+	case <-watch:
+		t.Error("Expected no notification while the claims are unchanged")
+	case <-time.After(100 * time.Millisecond):
+	}
+	cp.sign(t, cp.key, map[string]any{"db": map[string]any{"host": "db2"}}, time.Now().Add(time.Hour))
+	select {
+	case <-watch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a refresh")
+	}
+	if v := g.Get("db.host"); v != "db2" {
+		t.Errorf("Expected 'db2' after a refresh, got '%s'", v)
+	}
+
+	// A forged token is rejected on refresh, and the verified values stay until they expire.
+	forger, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	cp.sign(t, forger, map[string]any{"db": map[string]any{"host": "evil"}}, time.Now().Add(time.Hour))
+	select {
+	case err := <-g.Errors():
+		if err == nil {
+			t.Error("Expected a verification error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for an error")
+	}
+	if v := g.Get("db.host"); v != "db2" {
+		t.Errorf("Expected the verified 'db2' to stay, got '%s'", v)
+	}
+	g.Close()
+	g.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, err := g.GetErr("db.host"); !errors.Is(err, ErrExpired) {
+		t.Errorf("Expected ErrExpired, got %v", err)
+	}
+}
+
+func TestSignedConfigGetterRejects(t *testing.T) {
+	cp := newControlPlane(t)
+	srv := httptest.NewServer(cp)
+	defer srv.Close()
+	forger, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tests := []struct {
+		name string
+		key  *ecdsa.PrivateKey
+		exp  time.Time
+	}{
+		{"expired", cp.key, time.Now().Add(-time.Hour)},
+		{"no expiry", cp.key, time.Time{}},
+		{"wrong key", forger, time.Now().Add(time.Hour)},
+	}
+	for _, test := range tests {
+		cp.sign(t, test.key, map[string]any{"a": "b"}, test.exp)
+		if _, err := NewSignedConfigGetter(srv.URL+"/token", srv.URL+"/jwks", srv.Client()); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestSignedConfigGetterLimits(t *testing.T) {
+	cp := newControlPlane(t)
+	srv := httptest.NewServer(cp)
+	defer srv.Close()
+	cp.sign(t, cp.key, map[string]any{"a": "b"}, time.Now().Add(time.Hour))
+	if _, err := NewSignedConfigGetter(srv.URL+"/token", srv.URL+"/jwks", srv.Client(), WithRefreshInterval(0)); err == nil {
+		t.Error("Expected an error for a zero refresh interval")
+	}
+	cp.sign(t, cp.key, map[string]any{"a": strings.Repeat("b", maxBodySize)}, time.Now().Add(time.Hour))
+	_, err := NewSignedConfigGetter(srv.URL+"/token", srv.URL+"/jwks", srv.Client())
+	if err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("Expected an error for an oversized token, got %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/efixler/config/kafkaconfig
+
+go 1.25.0
+
+require (
+	github.com/confluentinc/confluent-kafka-go/v2 v2.15.1
+	github.com/efixler/config v0.0.0-00010101000000-000000000000
+)
+
+replace github.com/efixler/config => ../
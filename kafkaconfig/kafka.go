@@ -0,0 +1,226 @@
+// Package kafkaconfig provides a config.Getter over a compacted Kafka topic holding the latest config value per
+// message key, for event-sourced platforms that keep their config there. It's a separate package to keep the Kafka
+// client (and its cgo dependency) optional.
+package kafkaconfig
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"github.com/efixler/config"
+)
+
+const (
+	// catchUpTimeout bounds how long NewKafkaConfigGetter waits to read the topic up to its end.
+	catchUpTimeout = time.Minute
+	// requestTimeoutMs bounds the metadata and watermark requests.
+	requestTimeoutMs = 10000
+	pollTimeoutMs    = 100
+)
+
+// Getter serves the latest value of each key on a compacted topic from an in-memory map that it keeps consuming
+// into. It is a config.ErrorGetter, a config.Lister and a config.Watcher.
+type Getter struct {
+	config.Notifier
+	config.ErrorReporter
+	consumer  *kafka.Consumer
+	topic     string
+	lock      sync.RWMutex
+	values    map[string]string
+	fatal     error
+	stop      chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewKafkaConfigGetter : Read topic from the beginning into memory and return a Getter over it, where Get(key) is the
+// value of the latest message with that key. A message with a null value (a tombstone) deletes the key. GetStrings
+// splits values on commas, like the other getters.
+//
+// The Getter assigns itself every partition of topic rather than joining a consumer group, since every instance
+// needs every key, so consumer should be dedicated to it, and created with "enable.auto.commit" false (a
+// "group.id" is still required by the client, but nothing is committed). NewKafkaConfigGetter returns once it has
+// caught up with the end of each partition as of the call, so a ready Getter has all the config that was
+// published before it started; failing to catch up within a minute is an error.
+//
+// After that, new messages are applied as they arrive, and Watch() channels notified. Consumer errors go to the
+// Errors() channel; a fatal error stops consumption, and from then on GetErr returns it (the last values are still
+// served by Get). Call Close when you're done, then close the consumer.
+func NewKafkaConfigGetter(consumer *kafka.Consumer, topic string) (*Getter, error) {
+	meta, err := consumer.GetMetadata(&topic, false, requestTimeoutMs)
+	if err != nil {
+		return nil, fmt.Errorf("kafkaconfig: reading metadata for %s: %w", topic, err)
+	}
+	info, ok := meta.Topics[topic]
+	if !ok {
+		return nil, fmt.Errorf("kafkaconfig: topic %s not found", topic)
+	} else if info.Error.Code() != kafka.ErrNoError {
+		return nil, fmt.Errorf("kafkaconfig: topic %s: %w", topic, info.Error)
+	}
+	partitions := make([]kafka.TopicPartition, 0, len(info.Partitions))
+	ends := make(map[int32]int64)
+	for _, p := range info.Partitions {
+		low, high, err := consumer.QueryWatermarkOffsets(topic, p.ID, requestTimeoutMs)
+		if err != nil {
+			return nil, fmt.Errorf("kafkaconfig: reading offsets for %s[%d]: %w", topic, p.ID, err)
+		}
+		if high > low {
+			ends[p.ID] = high
+		}
+		partitions = append(partitions, kafka.TopicPartition{Topic: &topic, Partition: p.ID, Offset: kafka.OffsetBeginning})
+	}
+	if err := consumer.Assign(partitions); err != nil {
+		return nil, fmt.Errorf("kafkaconfig: assigning %s: %w", topic, err)
+	}
+	g := &Getter{
+		consumer: consumer,
+		topic:    topic,
+		values:   make(map[string]string),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	deadline := time.Now().Add(catchUpTimeout)
+	for len(ends) > 0 {
+		if time.Now().After(deadline) {
+			consumer.Unassign()
+			return nil, fmt.Errorf("kafkaconfig: timed out reading %s up to its end", topic)
+		}
+		switch ev := consumer.Poll(pollTimeoutMs).(type) {
+		case *kafka.Message:
+			g.apply(ev)
+		case kafka.Error:
+			if ev.IsFatal() {
+				consumer.Unassign()
+				return nil, fmt.Errorf("kafkaconfig: reading %s: %w", topic, ev)
+			}
+			log.Printf("kafkaconfig: reading %s: %v", topic, ev)
+		}
+		if err := g.caughtUp(ends); err != nil {
+			consumer.Unassign()
+			return nil, err
+		}
+	}
+	go g.follow()
+	return g, nil
+}
+
+// caughtUp removes the partitions from ends whose position has reached their end. The position is compared rather
+// than the offset of the last message, since the last offsets of a partition can hold transaction markers, which
+// aren't delivered as messages.
+func (g *Getter) caughtUp(ends map[int32]int64) error {
+	partitions := make([]kafka.TopicPartition, 0, len(ends))
+	for p := range ends {
+		partitions = append(partitions, kafka.TopicPartition{Topic: &g.topic, Partition: p})
+	}
+	positions, err := g.consumer.Position(partitions)
+	if err != nil {
+		return fmt.Errorf("kafkaconfig: reading positions for %s: %w", g.topic, err)
+	}
+	for _, p := range positions {
+		if p.Offset >= 0 && int64(p.Offset) >= ends[p.Partition] {
+			delete(ends, p.Partition)
+		}
+	}
+	return nil
+}
+
+func (g *Getter) apply(msg *kafka.Message) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if msg.Value == nil {
+		delete(g.values, string(msg.Key))
+	} else {
+		g.values[string(msg.Key)] = string(msg.Value)
+	}
+}
+
+func (g *Getter) follow() {
+	defer close(g.done)
+	for {
+		select {
+		case <-g.stop:
+			return
+		default:
+		}
+		switch ev := g.consumer.Poll(pollTimeoutMs).(type) {
+		case *kafka.Message:
+			g.apply(ev)
+			g.Notify()
+		case kafka.Error:
+			err := fmt.Errorf("kafkaconfig: reading %s: %w", g.topic, ev)
+			g.Report(err)
+			if ev.IsFatal() {
+				g.lock.Lock()
+				g.fatal = err
+				g.lock.Unlock()
+				return
+			}
+		}
+	}
+}
+
+// Close : Stop consuming and release the topic's partitions. The Getter keeps serving the values it has.
+func (g *Getter) Close() error {
+	var err error
+	g.closeOnce.Do(func() {
+		close(g.stop)
+		<-g.done
+		err = g.consumer.Unassign()
+	})
+	return err
+}
+
+// GetErr : Return the latest value for key, or the consumer's fatal error if it has had one. A key that isn't on
+// the topic is an error wrapping config.ErrKeyNotSet.
+func (g *Getter) GetErr(key string) (string, error) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	if g.fatal != nil {
+		return "", g.fatal
+	}
+	val, ok := g.values[key]
+	if !ok {
+		return "", fmt.Errorf("kafkaconfig: %s: %w", key, config.ErrKeyNotSet)
+	}
+	return val, nil
+}
+
+// Lookup : Return the latest value for key, and whether it's set.
+func (g *Getter) Lookup(key string) (string, bool) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	val, ok := g.values[key]
+	return val, ok
+}
+
+// Get : Return the latest value for key, or "".
+func (g *Getter) Get(key string) string {
+	val, _ := g.Lookup(key)
+	return val
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (g *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(g, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (g *Getter) GetStrings(key string) []string {
+	return config.SplitStrings(g.Get(key))
+}
+
+// MustGet will panic if the key is not present or empty.
+func (g *Getter) MustGet(key string) string {
+	return config.MustGet(g, key)
+}
+
+// Keys : Return the keys currently set, sorted.
+func (g *Getter) Keys() []string {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return config.Map(g.values).Keys()
+}
@@ -0,0 +1,137 @@
+package kafkaconfig
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"github.com/efixler/config"
+)
+
+func produce(t *testing.T, p *kafka.Producer, topic string, key string, val []byte) {
+	t.Helper()
+	delivery := make(chan kafka.Event, 1)
+	err := p.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Key:            []byte(key),
+		Value:          val,
+	}, delivery)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if m := (<-delivery).(*kafka.Message); m.TopicPartition.Error != nil {
+		t.Fatalf("Unexpected delivery error: %v", m.TopicPartition.Error)
+	}
+}
+
+func TestKafkaConfigGetter(t *testing.T) {
+	cluster, err := kafka.NewMockCluster(1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer cluster.Close()
+	const topic = "config"
+	if err := cluster.CreateTopic(topic, 2, 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p, err := kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": cluster.BootstrapServers()})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer p.Close()
+	produce(t, p, topic, "MODE", []byte("blue"))
+	produce(t, p, topic, "HOSTS", []byte("a, b"))
+	produce(t, p, topic, "MODE", []byte("green"))
+	produce(t, p, topic, "OLD", []byte("x"))
+	produce(t, p, topic, "OLD", nil)
+
+	c, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":  cluster.BootstrapServers(),
+		"group.id":           "config-test",
+		"enable.auto.commit": false,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer c.Close()
+	g, err := NewKafkaConfigGetter(c, topic)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	if v := g.Get("MODE"); v != "green" {
+		t.Errorf("Expected the latest value 'green' after catching up, got '%s'", v)
+	}
+	if v := g.GetStrings("HOSTS"); len(v) != 2 {
+		t.Errorf("Expected [a b], got %q", v)
+	}
+	if _, err := g.GetErr("OLD"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected a tombstoned key to be ErrKeyNotSet, got %v", err)
+	}
+
+	changes := g.Watch()
+	produce(t, p, topic, "MODE", []byte("red"))
+	select {
+	case <-changes:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected a change notification")
+	}
+	if v := g.Get("MODE"); v != "red" {
+		t.Errorf("Expected 'red', got '%s'", v)
+	}
+	if v := g.Keys(); len(v) != 2 || v[0] != "HOSTS" {
+		t.Errorf("Unexpected keys %q", v)
+	}
+}
+
+func TestKafkaConfigGetterTransactional(t *testing.T) {
+	cluster, err := kafka.NewMockCluster(1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer cluster.Close()
+	topic := "config"
+	if err := cluster.CreateTopic(topic, 1, 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p, err := kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": cluster.BootstrapServers(), "transactional.id": "config-test"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer p.Close()
+	ctx := context.Background()
+	if err := p.InitTransactions(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p.BeginTransaction()
+	produce(t, p, topic, "MODE", []byte("blue"))
+	// The commit marker is the last offset of the partition, and never reaches the consumer as a message.
+	if err := p.CommitTransaction(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	c, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":  cluster.BootstrapServers(),
+		"group.id":           "config-test",
+		"enable.auto.commit": false,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer c.Close()
+	start := time.Now()
+	g, err := NewKafkaConfigGetter(c, topic)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	if d := time.Since(start); d > 10*time.Second {
+		t.Errorf("Catching up took %v", d)
+	}
+	if v := g.Get("MODE"); v != "blue" {
+		t.Errorf("Expected 'blue', got '%s'", v)
+	}
+}
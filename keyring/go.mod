@@ -0,0 +1,16 @@
+module github.com/efixler/config/keyring
+
+go 1.22
+
+require (
+	github.com/efixler/config v0.0.0-00010101000000-000000000000
+	github.com/zalando/go-keyring v0.2.8
+)
+
+require (
+	github.com/danieljoos/wincred v1.2.3 // indirect
+	github.com/godbus/dbus/v5 v5.2.2 // indirect
+	golang.org/x/sys v0.27.0 // indirect
+)
+
+replace github.com/efixler/config => ../
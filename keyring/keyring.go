@@ -0,0 +1,76 @@
+// Package keyring provides a config.Getter over the OS keychain/credential manager, via github.com/zalando/go-keyring,
+// so that desktop and CLI tools can keep their secrets out of the environment and config files.
+//
+// Supported platforms are those of go-keyring: the macOS Keychain (through /usr/bin/security), the Windows Credential
+// Manager, and the Secret Service D-Bus API on Linux and BSDs (GNOME Keyring, KWallet), which needs a running
+// session bus. Where there is no usable keyring, lookups fail with an error from GetErr; to fall back to other
+// sources, compose the Getter with them, e.g. config.Merge(config.FirstNonEmpty, keyringGetter, config.Environment()).
+package keyring
+
+import (
+	"errors"
+	"fmt"
+
+	gokeyring "github.com/zalando/go-keyring"
+
+	"github.com/efixler/config"
+)
+
+// Getter reads secrets stored in the OS keyring under a service name. It is a config.ErrorGetter.
+type Getter struct {
+	service string
+}
+
+// NewKeyringGetter : Return a Getter where Get(key) fetches the secret stored under (service, key).
+// The keyring is not touched until the first lookup.
+func NewKeyringGetter(service string) (*Getter, error) {
+	if service == "" {
+		return nil, errors.New("keyring: service name required")
+	}
+	return &Getter{service: service}, nil
+}
+
+// GetErr : Fetch the secret for key. A secret that isn't in the keyring is an error wrapping config.ErrKeyNotSet.
+func (k *Getter) GetErr(key string) (string, error) {
+	secret, err := gokeyring.Get(k.service, key)
+	if errors.Is(err, gokeyring.ErrNotFound) {
+		return "", fmt.Errorf("keyring: %s/%s: %w", k.service, key, config.ErrKeyNotSet)
+	} else if err != nil {
+		return "", fmt.Errorf("keyring: %s/%s: %w", k.service, key, err)
+	}
+	return secret, nil
+}
+
+// Get : Fetch the secret for key, or "" if it can't be read.
+func (k *Getter) Get(key string) string {
+	secret, _ := k.GetErr(key)
+	return secret
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (k *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(k, key, dflt)
+}
+
+// GetStrings : Treat the secret as a comma-delimited list, like the other Getters.
+func (k *Getter) GetStrings(key string) []string {
+	return config.SplitStrings(k.Get(key))
+}
+
+// MustGet will panic if the secret is not in the keyring or empty.
+func (k *Getter) MustGet(key string) string {
+	return config.MustGet(k, key)
+}
+
+// Set : Store secret under (service, key).
+func (k *Getter) Set(key string, secret string) error {
+	if err := gokeyring.Set(k.service, key, secret); err != nil {
+		return fmt.Errorf("keyring: storing %s/%s: %w", k.service, key, err)
+	}
+	return nil
+}
+
+// IsSecret : Everything in the keyring is a secret.
+func (k *Getter) IsSecret(string) bool {
+	return true
+}
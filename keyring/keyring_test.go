@@ -0,0 +1,33 @@
+package keyring
+
+import (
+	"errors"
+	"testing"
+
+	gokeyring "github.com/zalando/go-keyring"
+
+	"github.com/efixler/config"
+)
+
+func TestKeyringGetter(t *testing.T) {
+	gokeyring.MockInit()
+	k, err := NewKeyringGetter("my-cli")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := k.Set("API_TOKEN", "t0ken"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := k.Get("API_TOKEN"); v != "t0ken" {
+		t.Errorf("Expected 't0ken', got '%s'", v)
+	}
+	if _, err := config.GetErr(k, "MISSING"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+	if !config.IsSecret(k, "API_TOKEN") {
+		t.Error("Keyring values should be marked secret")
+	}
+	if _, err := NewKeyringGetter(""); err == nil {
+		t.Error("Expected an error for an empty service name")
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// parseKeyValues reads KEY=VALUE lines in the style of a dotenv file. Blank lines and lines starting
+// with # are skipped, a leading "export " is ignored, and one pair of matching quotes around
+// the value is removed. A non-blank line without an = is an error.
+func parseKeyValues(r io.Reader) (map[string]string, error) {
+	rval := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, val, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+		rval[key] = unquote(strings.TrimSpace(val))
+	}
+	return rval, scanner.Err()
+}
+
+// unquote strips one pair of matching single or double quotes from around val.
+func unquote(val string) string {
+	if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+		return val[1 : len(val)-1]
+	}
+	return val
+}
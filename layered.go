@@ -0,0 +1,17 @@
+package config
+
+// NewLayeredMapGetter : Return a Getter over in-memory layers, e.g. runtime overrides, then an environment overlay,
+// then embedded defaults. Earlier layers override later ones: each lookup goes through the layers in order and
+// the first layer that has the key wins, even if its value is "" (this is Merge with FirstPresent).
+// Keys() returns the union of the keys of all the layers. To build lists up across layers rather than have one layer
+// replace another, compose Maps with MergeLists instead.
+//
+// It's the dependency-free counterpart of composing file getters, handy for tests and embedded defaults.
+// The layers should not be modified once they're in use.
+func NewLayeredMapGetter(layers ...map[string]string) Getter {
+	getters := make([]Getter, len(layers))
+	for i, layer := range layers {
+		getters[i] = Map(layer)
+	}
+	return Merge(FirstPresent, getters...)
+}
@@ -0,0 +1,23 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewLayeredMapGetter(t *testing.T) {
+	c := NewLayeredMapGetter(
+		map[string]string{"LOG_LEVEL": "debug", "BANNER": ""},
+		map[string]string{"LOG_LEVEL": "info", "PORT": "8080", "BANNER": "hello"},
+		map[string]string{"PORT": "80", "HOST": "localhost"},
+	)
+	tests := map[string]string{"LOG_LEVEL": "debug", "PORT": "8080", "HOST": "localhost", "BANNER": ""}
+	for key, expected := range tests {
+		if v := c.Get(key); v != expected {
+			t.Errorf("%s: expected '%s', got '%s'", key, expected, v)
+		}
+	}
+	if keys := c.(Lister).Keys(); !reflect.DeepEqual(keys, []string{"BANNER", "HOST", "LOG_LEVEL", "PORT"}) {
+		t.Errorf("Expected the union of layer keys, got %q", keys)
+	}
+}
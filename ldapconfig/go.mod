@@ -0,0 +1,17 @@
+module github.com/efixler/config/ldapconfig
+
+go 1.25.0
+
+require (
+	github.com/efixler/config v0.0.0-00010101000000-000000000000
+	github.com/go-ldap/ldap/v3 v3.4.14
+)
+
+require (
+	github.com/Azure/go-ntlmssp v0.1.1 // indirect
+	github.com/go-asn1-ber/asn1-ber v1.5.8 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	golang.org/x/crypto v0.54.0 // indirect
+)
+
+replace github.com/efixler/config => ../
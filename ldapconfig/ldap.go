@@ -0,0 +1,157 @@
+// Package ldapconfig provides a config.Getter over the attributes of an LDAP directory entry, for enterprise
+// environments that keep service config centrally in the directory.
+package ldapconfig
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/efixler/config"
+)
+
+// DefaultTTL is how long looked-up values are cached, unless overridden with WithTTL.
+const DefaultTTL = time.Minute
+
+// LDAPConn is the part of *ldap.Conn the Getter uses. The connection should already be bound.
+type LDAPConn interface {
+	Search(*ldap.SearchRequest) (*ldap.SearchResult, error)
+}
+
+// Option configures a Getter.
+type Option func(*Getter)
+
+// WithAttributeMap : Map config keys to LDAP attribute names. Keys that aren't in the map are used as attribute names as is.
+func WithAttributeMap(attrs map[string]string) Option {
+	return func(g *Getter) {
+		g.attrs = attrs
+	}
+}
+
+// WithTTL : Cache looked-up values (and misses) for ttl instead of DefaultTTL. A zero ttl disables caching.
+func WithTTL(ttl time.Duration) Option {
+	return func(g *Getter) {
+		g.ttl = ttl
+	}
+}
+
+// Getter reads config values from the attributes of a single directory entry. It is a config.ErrorGetter.
+type Getter struct {
+	conn   LDAPConn
+	baseDN string
+	attrs  map[string]string
+	ttl    time.Duration
+	lock   sync.Mutex
+	cache  map[string]cached
+	now    func() time.Time
+}
+
+type cached struct {
+	vals    []string
+	expires time.Time
+}
+
+// NewLDAPGetter : Return a Getter where Get(key) reads the attribute for key (see WithAttributeMap) from the entry
+// at baseDN. Multi-valued attributes are joined with commas by Get; GetStrings returns their values as they are.
+// Values are cached for DefaultTTL.
+//
+// Search errors (including bind problems, which show up on the first search) are returned by GetErr; Get returns ""
+// and logs them. An entry or attribute that doesn't exist is an error wrapping config.ErrKeyNotSet.
+func NewLDAPGetter(conn LDAPConn, baseDN string, opts ...Option) (*Getter, error) {
+	if _, err := ldap.ParseDN(baseDN); err != nil {
+		return nil, fmt.Errorf("ldapconfig: invalid base DN %q: %w", baseDN, err)
+	}
+	g := &Getter{conn: conn, baseDN: baseDN, ttl: DefaultTTL, cache: make(map[string]cached), now: time.Now}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g, nil
+}
+
+func (g *Getter) attribute(key string) string {
+	if attr, ok := g.attrs[key]; ok {
+		return attr
+	}
+	return key
+}
+
+// values returns the values of the attribute for key, from the cache if they're fresh. The lock isn't held during
+// the search, so one slow lookup doesn't hold up the others.
+func (g *Getter) values(key string) ([]string, error) {
+	g.lock.Lock()
+	entry, ok := g.cache[key]
+	g.lock.Unlock()
+	now := g.now()
+	if !ok || !now.Before(entry.expires) {
+		attr := g.attribute(key)
+		res, err := g.conn.Search(ldap.NewSearchRequest(
+			g.baseDN, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 1, 0, false,
+			"(objectClass=*)", []string{attr}, nil,
+		))
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, fmt.Errorf("ldapconfig: %s: %w", g.baseDN, config.ErrKeyNotSet)
+		} else if err != nil {
+			return nil, fmt.Errorf("ldapconfig: searching %s for %s: %w", g.baseDN, attr, err)
+		}
+		entry = cached{expires: now.Add(g.ttl)}
+		if len(res.Entries) > 0 {
+			entry.vals = res.Entries[0].GetAttributeValues(attr)
+		}
+		if g.ttl > 0 {
+			g.lock.Lock()
+			g.cache[key] = entry
+			g.lock.Unlock()
+		}
+	}
+	if len(entry.vals) == 0 || (len(entry.vals) == 1 && entry.vals[0] == "") {
+		return nil, fmt.Errorf("ldapconfig: %s: %s: %w", g.baseDN, g.attribute(key), config.ErrKeyNotSet)
+	}
+	return entry.vals, nil
+}
+
+// GetErr : Read the attribute for key, from the cache if it's fresh. The values of a multi-valued attribute are
+// joined with commas.
+func (g *Getter) GetErr(key string) (string, error) {
+	vals, err := g.values(key)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(vals, ","), nil
+}
+
+// Get : Read the attribute for key, or "" if it's missing or the search fails.
+func (g *Getter) Get(key string) string {
+	val, err := g.GetErr(key)
+	if err != nil && !errors.Is(err, config.ErrKeyNotSet) {
+		log.Print(err)
+	}
+	return val
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (g *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(g, key, dflt)
+}
+
+// GetStrings : Return the values of a (possibly multi-valued) attribute as they are in the directory, so values
+// that contain commas (like DNs) stay whole. A single value is not split on commas.
+func (g *Getter) GetStrings(key string) []string {
+	vals, err := g.values(key)
+	if err != nil {
+		if !errors.Is(err, config.ErrKeyNotSet) {
+			log.Print(err)
+		}
+		return config.SplitStrings("")
+	}
+	return append([]string(nil), vals...)
+}
+
+// MustGet will panic if the attribute is not present or empty.
+func (g *Getter) MustGet(key string) string {
+	return config.MustGet(g, key)
+}
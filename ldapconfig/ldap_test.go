@@ -0,0 +1,85 @@
+package ldapconfig
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/efixler/config"
+)
+
+type fakeConn struct {
+	attrs    map[string][]string
+	searches int
+	err      error
+}
+
+func (f *fakeConn) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	f.searches++
+	if f.err != nil {
+		return nil, f.err
+	}
+	entry := ldap.NewEntry(req.BaseDN, map[string][]string{})
+	for _, attr := range req.Attributes {
+		if vals, ok := f.attrs[attr]; ok {
+			entry.Attributes = append(entry.Attributes, ldap.NewEntryAttribute(attr, vals))
+		}
+	}
+	return &ldap.SearchResult{Entries: []*ldap.Entry{entry}}, nil
+}
+
+func TestLDAPGetter(t *testing.T) {
+	conn := &fakeConn{attrs: map[string][]string{
+		"serviceTimeout": {"30s"},
+		"serviceHost":    {"a.example.com", "b.example.com"},
+	}}
+	g, err := NewLDAPGetter(conn, "cn=billing,ou=services,dc=example,dc=com",
+		WithAttributeMap(map[string]string{"TIMEOUT": "serviceTimeout", "HOSTS": "serviceHost"}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := g.Get("TIMEOUT"); v != "30s" {
+		t.Errorf("Expected '30s', got '%s'", v)
+	}
+	if v := g.GetStrings("HOSTS"); !reflect.DeepEqual(v, []string{"a.example.com", "b.example.com"}) {
+		t.Errorf("Expected the attribute's values, got %q", v)
+	}
+	g.Get("TIMEOUT")
+	if conn.searches != 2 {
+		t.Errorf("Expected the second lookup to be cached; %d searches", conn.searches)
+	}
+	if _, err := config.GetErr(g, "MISSING"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+}
+
+func TestLDAPGetterErrors(t *testing.T) {
+	if _, err := NewLDAPGetter(&fakeConn{}, "not a dn"); err == nil {
+		t.Error("Expected an error for an invalid base DN")
+	}
+	g, _ := NewLDAPGetter(&fakeConn{err: errors.New("connection reset")}, "dc=example,dc=com", WithTTL(0))
+	if _, err := g.GetErr("ANY"); err == nil || errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected the search error, got %v", err)
+	}
+}
+
+func TestLDAPGetterCachedMiss(t *testing.T) {
+	conn := &fakeConn{attrs: map[string][]string{
+		"member": {"cn=a,ou=people,dc=example,dc=com", "cn=b,ou=people,dc=example,dc=com"},
+	}}
+	g, _ := NewLDAPGetter(conn, "cn=admins,dc=example,dc=com")
+	for i := 0; i < 2; i++ {
+		if _, err := g.GetErr("MISSING"); !errors.Is(err, config.ErrKeyNotSet) {
+			t.Errorf("Lookup %d: expected ErrKeyNotSet, got %v", i, err)
+		}
+	}
+	if conn.searches != 1 {
+		t.Errorf("Expected the miss to be cached; %d searches", conn.searches)
+	}
+	expected := []string{"cn=a,ou=people,dc=example,dc=com", "cn=b,ou=people,dc=example,dc=com"}
+	if v := g.GetStrings("member"); !reflect.DeepEqual(v, expected) {
+		t.Errorf("Expected DN values to stay whole, got %q", v)
+	}
+}
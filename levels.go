@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GetSlogLevel : Parse the value of key as a slog.Level. The accepted names, in any case, are debug, info,
+// warn (or warning) and error, optionally followed by a numeric offset as in slog: "info+2", "DEBUG-4". A bare
+// integer is taken as the level's numeric value, so "-4" is debug and "8" is error. Surrounding whitespace is ignored.
+// An unknown level is an error, and an unset key is an error wrapping ErrKeyNotSet.
+func (e *Env) GetSlogLevel(key string) (slog.Level, error) {
+	return parseSlogLevel(e, key)
+}
+
+// GetSlogLevelOrDefault : Return GetSlogLevel(key), or dflt if the key is unset or invalid (which is logged).
+func (e *Env) GetSlogLevelOrDefault(key string, dflt slog.Level) slog.Level {
+	level, err := parseSlogLevel(e, key)
+	if err != nil {
+		if e.Get(key) != "" {
+			log.Printf("config: %s is not a valid log level, using the default", key)
+		}
+		return dflt
+	}
+	return level
+}
+
+func parseSlogLevel(g Getter, key string) (slog.Level, error) {
+	raw := strings.TrimSpace(g.Get(key))
+	if raw == "" {
+		return 0, fmt.Errorf("config: %s: %w", key, ErrKeyNotSet)
+	}
+	if n, err := strconv.Atoi(raw); err == nil {
+		return slog.Level(n), nil
+	}
+	name, offset := raw, ""
+	if i := strings.IndexAny(raw, "+-"); i > 0 {
+		name, offset = raw[:i], raw[i:]
+	}
+	if strings.EqualFold(name, "warning") {
+		name = "warn"
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name + offset)); err != nil {
+		return 0, fmt.Errorf("config: %s value %s is not a valid log level (use debug, info, warn or error)", key, displayValue(g, key, raw))
+	}
+	return level, nil
+}
+
+// GetLevel : Look up the value of key in mapping, ignoring case and surrounding whitespace, for log level types
+// other than slog's:
+//
+//	level, err := config.GetLevel(g, "LOG_LEVEL", map[string]zapcore.Level{"debug": zap.DebugLevel, "info": zap.InfoLevel})
+//
+// A value that isn't in mapping is an error listing the names that are, and an unset key is an error wrapping
+// ErrKeyNotSet.
+func GetLevel[T any](g Getter, key string, mapping map[string]T) (T, error) {
+	var zero T
+	raw := strings.TrimSpace(g.Get(key))
+	if raw == "" {
+		return zero, fmt.Errorf("config: %s: %w", key, ErrKeyNotSet)
+	}
+	names := make([]string, 0, len(mapping))
+	for name, level := range mapping {
+		if strings.EqualFold(name, raw) {
+			return level, nil
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return zero, fmt.Errorf("config: %s value %s is not a valid log level (use %s)", key, displayValue(g, key, raw), strings.Join(names, ", "))
+}
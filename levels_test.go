@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGetSlogLevel(t *testing.T) {
+	defer os.Unsetenv("CONFIG_TEST_LEVEL")
+	e := &Env{}
+	for raw, expected := range map[string]slog.Level{
+		"debug":   slog.LevelDebug,
+		"INFO":    slog.LevelInfo,
+		" Warn ":  slog.LevelWarn,
+		"warning": slog.LevelWarn,
+		"error":   slog.LevelError,
+		"info+2":  slog.LevelInfo + 2,
+		"DEBUG-4": slog.LevelDebug - 4,
+		"-4":      slog.LevelDebug,
+		"8":       slog.LevelError,
+	} {
+		os.Setenv("CONFIG_TEST_LEVEL", raw)
+		if v, err := e.GetSlogLevel("CONFIG_TEST_LEVEL"); err != nil || v != expected {
+			t.Errorf("Expected %v for '%s', got %v (%v)", expected, raw, v, err)
+		}
+	}
+	os.Setenv("CONFIG_TEST_LEVEL", "loud")
+	if _, err := e.GetSlogLevel("CONFIG_TEST_LEVEL"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+	if v := e.GetSlogLevelOrDefault("CONFIG_TEST_LEVEL", slog.LevelWarn); v != slog.LevelWarn {
+		t.Errorf("Expected the default for an unknown level, got %v", v)
+	}
+	os.Unsetenv("CONFIG_TEST_LEVEL")
+	if _, err := e.GetSlogLevel("CONFIG_TEST_LEVEL"); !errors.Is(err, ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+}
+
+func TestGetLevel(t *testing.T) {
+	mapping := map[string]int{"trace": -1, "info": 1, "fatal": 5}
+	g := Map{"LEVEL": "FATAL", "BAD": "loud"}
+	if v, err := GetLevel(g, "LEVEL", mapping); err != nil || v != 5 {
+		t.Errorf("Expected 5, got %d (%v)", v, err)
+	}
+	if _, err := GetLevel(g, "BAD", mapping); err == nil || !strings.Contains(err.Error(), "fatal, info, trace") {
+		t.Errorf("Expected an error listing the levels, got %v", err)
+	}
+	if _, err := GetLevel(g, "MISSING", mapping); !errors.Is(err, ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+}
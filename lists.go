@@ -0,0 +1,370 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// GetStringsTrim splits a comma-delimited config value like GetStrings, but strips the characters in
+// cutset from each element (via strings.Trim) instead of whitespace. Elements left empty by the trim are dropped.
+//
+// GetStrings instead trims whitespace with strings.TrimSpace (so Unicode spaces too), and keeps empty elements.
+// To strip surrounding spaces and quotes use a cutset like ` "'`.
+func (e *Env) GetStringsTrim(key string, cutset string) []string {
+	return splitTrim(e.Get(key), ",", cutset)
+}
+
+// GetStringsExpandEnv splits a comma-delimited config value, then expands ${VAR} and $VAR references in each
+// element with os.ExpandEnv before trimming whitespace, e.g. HOSTS="${PRIMARY},${SECONDARY}".
+// Since expansion happens after the split, commas inside a referenced variable do not create new elements.
+// Elements that expand to empty are dropped.
+func (e *Env) GetStringsExpandEnv(key string) []string {
+	rval := make([]string, 0)
+	for _, val := range strings.Split(e.Get(key), ",") {
+		if val = strings.TrimSpace(os.ExpandEnv(val)); val != "" {
+			rval = append(rval, val)
+		}
+	}
+	return rval
+}
+
+// GetIncludeExclude splits and trims a comma-delimited list of allow/deny rules like "+a,+b,-c", partitioning the
+// elements by their leading "+" (include) or "-" (exclude), with the prefix stripped. Unprefixed elements are
+// included. Empty elements, and elements that are just "+" or "-", are ignored.
+func (e *Env) GetIncludeExclude(key string) (include []string, exclude []string) {
+	include, exclude = make([]string, 0), make([]string, 0)
+	for _, val := range splitTrimSpace(e.Get(key)) {
+		switch val[0] {
+		case '-':
+			if val = strings.TrimSpace(val[1:]); val != "" {
+				exclude = append(exclude, val)
+			}
+		case '+':
+			val = val[1:]
+			fallthrough
+		default:
+			if val = strings.TrimSpace(val); val != "" {
+				include = append(include, val)
+			}
+		}
+	}
+	return include, exclude
+}
+
+// GetStringsLower splits and trims like GetStrings, dropping empty elements, then lowercases each element with
+// strings.ToLower. The folding is Unicode-aware, not ASCII-only.
+func (e *Env) GetStringsLower(key string) []string {
+	return mapStrings(splitTrimSpace(e.Get(key)), strings.ToLower)
+}
+
+// GetStringsUpper is GetStringsLower, but uppercasing each element with strings.ToUpper.
+func (e *Env) GetStringsUpper(key string) []string {
+	return mapStrings(splitTrimSpace(e.Get(key)), strings.ToUpper)
+}
+
+// GetStringsMerged builds a list incrementally: it starts from the elements of baseKey, appends the elements
+// of addKey, then removes any elements listed in removeKey, in that order. Elements are split, trimmed and
+// empties dropped as in GetStringsLower; the result is deduped, keeping the first occurrence's position.
+// This lets operators add one host or remove another without copying and editing the whole base list.
+func (e *Env) GetStringsMerged(baseKey string, addKey string, removeKey string) []string {
+	vals := append(splitTrimSpace(e.Get(baseKey)), splitTrimSpace(e.Get(addKey))...)
+	removed := make(map[string]bool)
+	for _, val := range splitTrimSpace(e.Get(removeKey)) {
+		removed[val] = true
+	}
+	rval := make([]string, 0, len(vals))
+	for _, val := range uniqueStrings(vals) {
+		if !removed[val] {
+			rval = append(rval, val)
+		}
+	}
+	return rval
+}
+
+// GetStringsUnique splits, trims and drops empty elements like GetStringsLower, then removes repeated elements, keeping
+// the first occurrence of each, in order. Comparison is case-sensitive.
+func (e *Env) GetStringsUnique(key string) []string {
+	return uniqueStrings(splitTrimSpace(e.Get(key)))
+}
+
+// GetStringsUniqueFold is GetStringsUnique, but elements are compared case-insensitively (with strings.EqualFold), so
+// "Example.com" and "example.com" collapse into one. The first occurrence's casing and position are kept.
+func (e *Env) GetStringsUniqueFold(key string) []string {
+	vals := splitTrimSpace(e.Get(key))
+	rval := vals[:0]
+	for _, val := range vals {
+		dup := false
+		for _, kept := range rval {
+			if dup = strings.EqualFold(kept, val); dup {
+				break
+			}
+		}
+		if !dup {
+			rval = append(rval, val)
+		}
+	}
+	return rval
+}
+
+// GetOrderedSet returns the elements of GetStringsUnique as an OrderedSet, for lists where both the order
+// and membership checks matter, like priority-ordered allowlists. Like GetStringsUnique, it's case-sensitive.
+func (e *Env) GetOrderedSet(key string) *OrderedSet {
+	return NewOrderedSet(e.GetStringsUnique(key)...)
+}
+
+// maxIncludeDepth bounds how deeply GetStringsWithIncludes follows includes within includes.
+const maxIncludeDepth = 8
+
+// GetStringsWithIncludes splits, trims and drops empty elements like GetStringsLower, but any element starting with "@"
+// is replaced by the lines of the file it names, e.g. HOSTS="a,b,@/etc/extra-hosts". Lines in the file are trimmed,
+// and blank lines and lines starting with # are dropped. A line that itself starts with "@" includes another file.
+//
+// An unreadable file is an error naming the file and the element (or line) that referenced it. So is an include cycle,
+// or includes more than 8 levels deep.
+func (e *Env) GetStringsWithIncludes(key string) ([]string, error) {
+	rval := make([]string, 0)
+	for _, val := range splitTrimSpace(e.Get(key)) {
+		vals, err := expandInclude(val, nil)
+		if err != nil {
+			return nil, fmt.Errorf("config: %s: %w", key, err)
+		}
+		rval = append(rval, vals...)
+	}
+	return rval, nil
+}
+
+// expandInclude returns val, or the lines of the file if it's an @include. stack is the chain of files being included.
+func expandInclude(val string, stack []string) ([]string, error) {
+	path, ok := strings.CutPrefix(val, "@")
+	if !ok {
+		return []string{val}, nil
+	}
+	for _, p := range stack {
+		if p == path {
+			return nil, fmt.Errorf("include cycle: %s -> %s", strings.Join(stack, " -> "), path)
+		}
+	}
+	if len(stack) >= maxIncludeDepth {
+		return nil, fmt.Errorf("includes nested more than %d deep at %s", maxIncludeDepth, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("include %s: %w", val, err)
+	}
+	rval := make([]string, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		vals, err := expandInclude(line, append(stack[:len(stack):len(stack)], path))
+		if err != nil {
+			return nil, err
+		}
+		rval = append(rval, vals...)
+	}
+	return rval, nil
+}
+
+// GetQueryMap parses a query-string-style config value, e.g. OPTS="a=1&b=2&b=3", with url.ParseQuery. Keys and values
+// are percent-decoded per query-string rules ("+" is a space), and repeated keys are preserved in the url.Values slices.
+// Malformed input returns an error naming the key, along with the pairs that did parse. An unset key is an empty url.Values.
+func (e *Env) GetQueryMap(key string) (url.Values, error) {
+	rval, err := url.ParseQuery(e.Get(key))
+	if err != nil {
+		return rval, fmt.Errorf("config: parsing %s as a query string: %w", key, err)
+	}
+	return rval, nil
+}
+
+// GetStringsTemplated overlays a comma-delimited config value onto defaults, position by position: each non-empty
+// element (after trimming whitespace) replaces the default at its position, and an empty element keeps it, so with
+// defaults [a b c] the value ",x" is [a x c]. A list shorter than defaults keeps the trailing defaults, and a longer
+// one appends its extra elements (including empty ones, which have no default to fall back on).
+// An unset key returns a copy of defaults. defaults itself is never modified.
+func (e *Env) GetStringsTemplated(key string, defaults []string) []string {
+	rval := append([]string(nil), defaults...)
+	raw := e.Get(key)
+	if raw == "" {
+		return rval
+	}
+	for i, val := range strings.Split(raw, ",") {
+		val = strings.TrimSpace(val)
+		switch {
+		case i >= len(rval):
+			rval = append(rval, val)
+		case val != "":
+			rval[i] = val
+		}
+	}
+	return rval
+}
+
+// GetPathListQuoted splits a PATH-style config value on the platform's os.PathListSeparator (":" on Unix, ";" on
+// Windows), then trims whitespace from each element and strips one pair of matching surrounding quotes (single or
+// double), as Windows puts around entries with spaces, e.g. `"C:\Program Files\Go\bin";C:\tools`. Elements that end
+// up empty are dropped. Quotes don't protect a separator inside them: the split happens first.
+func (e *Env) GetPathListQuoted(key string) []string {
+	rval := make([]string, 0)
+	for _, val := range strings.Split(e.Get(key), string(os.PathListSeparator)) {
+		if val = strings.TrimSpace(unquote(strings.TrimSpace(val))); val != "" {
+			rval = append(rval, val)
+		}
+	}
+	return rval
+}
+
+// GetCIDRs splits, trims and drops empty elements like GetStringsLower, then parses each element as an IPv4 or IPv6
+// CIDR with net.ParseCIDR, e.g. ALLOW="10.0.0.0/8, 2001:db8::/32". Bare IPs without a prefix length are rejected
+// rather than guessed at (write 192.0.2.1/32 for a single address). An invalid element is an error naming it.
+// Use IPInAnyCIDR to check addresses against the result.
+func (e *Env) GetCIDRs(key string) ([]*net.IPNet, error) {
+	rval := make([]*net.IPNet, 0)
+	for _, val := range splitTrimSpace(e.Get(key)) {
+		_, ipNet, err := net.ParseCIDR(val)
+		if err != nil {
+			return nil, fmt.Errorf("config: %s: invalid CIDR %q", key, val)
+		}
+		rval = append(rval, ipNet)
+	}
+	return rval, nil
+}
+
+// IPInAnyCIDR : Report whether ip is in any of nets, e.g. an allowlist from GetCIDRs.
+func IPInAnyCIDR(ip net.IP, nets []*net.IPNet) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxRangeSize bounds the total number of values GetIntRangeSlice will expand a config value into.
+const maxRangeSize = 65536
+
+// GetIntRangeSlice splits a comma-delimited list of integers and inclusive ranges, expanding each "a-b" element into
+// a, a+1, ..., b, e.g. PORTS="8000-8002,9000" is [8000 8001 8002 9000]. Whitespace around elements and around the "-"
+// is trimmed and empty elements are dropped; order and repeats are kept. A leading "-" is a sign, so "-2-2" is -2 to 2.
+//
+// A non-numeric element, or a range that runs backwards (b < a), is an error naming the key and element. So is a value
+// that expands to more than 65536 integers in all, so a typo like "1-9999999999" can't exhaust memory.
+func (e *Env) GetIntRangeSlice(key string) ([]int, error) {
+	rval := make([]int, 0)
+	for _, val := range splitTrimSpace(e.Get(key)) {
+		lo, hi, err := parseIntRange(val)
+		if err != nil {
+			return nil, fmt.Errorf("config: %s: %w", key, err)
+		}
+		// hi-lo can overflow to a negative int, which is a huge uint and so over the limit.
+		if uint(hi-lo) >= uint(maxRangeSize-len(rval)) {
+			return nil, fmt.Errorf("config: %s: expands to more than %d values at %q", key, maxRangeSize, val)
+		}
+		// Stop at hi rather than testing i <= hi, which is always true when hi is math.MaxInt.
+		for i := lo; ; i++ {
+			rval = append(rval, i)
+			if i == hi {
+				break
+			}
+		}
+	}
+	return rval, nil
+}
+
+// parseIntRange parses "n" or "a-b" into its inclusive bounds.
+func parseIntRange(val string) (int, int, error) {
+	loStr, hiStr, isRange := val, val, false
+	if i := strings.Index(val[1:], "-"); i >= 0 {
+		loStr, hiStr, isRange = strings.TrimSpace(val[:i+1]), strings.TrimSpace(val[i+2:]), true
+	}
+	lo, err := strconv.Atoi(loStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid integer in %q", val)
+	}
+	hi, err := strconv.Atoi(hiStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid integer in %q", val)
+	}
+	if isRange && hi < lo {
+		return 0, 0, fmt.Errorf("range %q runs backwards", val)
+	}
+	return lo, hi, nil
+}
+
+// WeightedString is an element of GetWeightedStrings.
+type WeightedString struct {
+	Value  string
+	Weight int
+}
+
+// GetWeightedStrings splits, trims and drops empty elements like GetStringsLower, then parses each "value:weight" element,
+// e.g. BACKENDS="a:3,b:1,c" is a with weight 3, b with weight 1 and c with the default weight of 1. The weight is the
+// part after the last colon, so a value that contains colons itself (like "host:8080") needs an explicit weight.
+//
+// A weight of 0 is allowed, and is the usual way to drain an entry without removing it. A negative or non-numeric
+// weight, or an empty value, is an error naming the element.
+func (e *Env) GetWeightedStrings(key string) ([]WeightedString, error) {
+	rval := make([]WeightedString, 0)
+	for _, val := range splitTrimSpace(e.Get(key)) {
+		entry := WeightedString{Value: val, Weight: 1}
+		if i := strings.LastIndex(val, ":"); i >= 0 {
+			weight, err := strconv.Atoi(strings.TrimSpace(val[i+1:]))
+			if err != nil || weight < 0 {
+				return nil, fmt.Errorf("config: %s: invalid weight in %q", key, val)
+			}
+			entry = WeightedString{Value: strings.TrimSpace(val[:i]), Weight: weight}
+		}
+		if entry.Value == "" {
+			return nil, fmt.Errorf("config: %s: missing value in %q", key, val)
+		}
+		rval = append(rval, entry)
+	}
+	return rval, nil
+}
+
+// uniqueStrings drops repeats from vals, keeping the first occurrence of each, in place.
+func uniqueStrings(vals []string) []string {
+	seen := make(map[string]bool, len(vals))
+	rval := vals[:0]
+	for _, val := range vals {
+		if !seen[val] {
+			seen[val] = true
+			rval = append(rval, val)
+		}
+	}
+	return rval
+}
+
+// mapStrings applies fn to each element of vals, in place.
+func mapStrings(vals []string, fn func(string) string) []string {
+	for i, val := range vals {
+		vals[i] = fn(val)
+	}
+	return vals
+}
+
+// splitTrimSpace splits raw on commas, trims each element with strings.TrimSpace as SplitStrings does, and drops the
+// empties.
+func splitTrimSpace(raw string) []string {
+	rval := make([]string, 0)
+	for _, val := range strings.Split(raw, ",") {
+		if val = strings.TrimSpace(val); val != "" {
+			rval = append(rval, val)
+		}
+	}
+	return rval
+}
+
+// splitTrim splits raw on sep, trims cutset from each element, and drops the empties.
+func splitTrim(raw string, sep string, cutset string) []string {
+	rval := make([]string, 0)
+	for _, val := range strings.Split(raw, sep) {
+		if val = strings.Trim(val, cutset); val != "" {
+			rval = append(rval, val)
+		}
+	}
+	return rval
+}
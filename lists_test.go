@@ -0,0 +1,279 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetStringsTrim(t *testing.T) {
+	os.Setenv("CONFIG_TEST_TRIM", ` "a", 'b' ,,[c], "" `)
+	defer os.Unsetenv("CONFIG_TEST_TRIM")
+	e := &Env{}
+	tests := []struct {
+		cutset   string
+		expected []string
+	}{
+		{` "'`, []string{"a", "b", "[c]"}},
+		{` "'[]`, []string{"a", "b", "c"}},
+	}
+	for _, test := range tests {
+		got := e.GetStringsTrim("CONFIG_TEST_TRIM", test.cutset)
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("GetStringsTrim with cutset %q: expected %q, got %q", test.cutset, test.expected, got)
+		}
+	}
+	if got := e.GetStringsTrim("CONFIG_TEST_TRIM_UNSET", " "); len(got) != 0 {
+		t.Errorf("GetStringsTrim on unset key: expected empty slice, got %q", got)
+	}
+}
+
+func TestGetStringsExpandEnv(t *testing.T) {
+	os.Setenv("CONFIG_TEST_PRIMARY", "db1:5432")
+	os.Setenv("CONFIG_TEST_HOSTS", "${CONFIG_TEST_PRIMARY}, $CONFIG_TEST_SECONDARY ,db3")
+	defer func() {
+		os.Unsetenv("CONFIG_TEST_PRIMARY")
+		os.Unsetenv("CONFIG_TEST_HOSTS")
+	}()
+	expected := []string{"db1:5432", "db3"}
+	if got := (&Env{}).GetStringsExpandEnv("CONFIG_TEST_HOSTS"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestGetIncludeExclude(t *testing.T) {
+	os.Setenv("CONFIG_TEST_RULES", "+a, b,-c,+,-, - d,,")
+	defer os.Unsetenv("CONFIG_TEST_RULES")
+	include, exclude := (&Env{}).GetIncludeExclude("CONFIG_TEST_RULES")
+	if !reflect.DeepEqual(include, []string{"a", "b"}) {
+		t.Errorf("Expected include [a b], got %q", include)
+	}
+	if !reflect.DeepEqual(exclude, []string{"c", "d"}) {
+		t.Errorf("Expected exclude [c d], got %q", exclude)
+	}
+}
+
+func TestGetStringsCaseFolding(t *testing.T) {
+	os.Setenv("CONFIG_TEST_CASE", "Example.COM, ,ÉTÉ")
+	defer os.Unsetenv("CONFIG_TEST_CASE")
+	e := &Env{}
+	if got := e.GetStringsLower("CONFIG_TEST_CASE"); !reflect.DeepEqual(got, []string{"example.com", "été"}) {
+		t.Errorf("Expected lowercased elements, got %q", got)
+	}
+	if got := e.GetStringsUpper("CONFIG_TEST_CASE"); !reflect.DeepEqual(got, []string{"EXAMPLE.COM", "ÉTÉ"}) {
+		t.Errorf("Expected uppercased elements, got %q", got)
+	}
+}
+
+func TestGetStringsUnicodeSpace(t *testing.T) {
+	os.Setenv("CONFIG_TEST_SPACES", "\u00a0a\u00a0,\vb\f, \u2003 ")
+	defer os.Unsetenv("CONFIG_TEST_SPACES")
+	e := &Env{}
+	if got := e.GetStringsUnique("CONFIG_TEST_SPACES"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Expected elements trimmed like GetStrings, got %q", got)
+	}
+}
+
+func TestGetStringsMerged(t *testing.T) {
+	os.Setenv("CONFIG_TEST_BASE", "a, b, c")
+	os.Setenv("CONFIG_TEST_ADD", "d,a, e")
+	os.Setenv("CONFIG_TEST_REMOVE", "b, e")
+	defer func() {
+		os.Unsetenv("CONFIG_TEST_BASE")
+		os.Unsetenv("CONFIG_TEST_ADD")
+		os.Unsetenv("CONFIG_TEST_REMOVE")
+	}()
+	e := &Env{}
+	if got := e.GetStringsMerged("CONFIG_TEST_BASE", "CONFIG_TEST_ADD", "CONFIG_TEST_REMOVE"); !reflect.DeepEqual(got, []string{"a", "c", "d"}) {
+		t.Errorf("Expected [a c d], got %q", got)
+	}
+	if got := e.GetStringsMerged("CONFIG_TEST_BASE", "CONFIG_TEST_UNSET", "CONFIG_TEST_UNSET"); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("Expected the base list when nothing is added or removed, got %q", got)
+	}
+}
+
+func TestGetQueryMap(t *testing.T) {
+	os.Setenv("CONFIG_TEST_OPTS", "sslmode=require&host=a&host=b&app=my+app%21")
+	os.Setenv("CONFIG_TEST_BAD_OPTS", "a=1&b=%zz")
+	defer func() {
+		os.Unsetenv("CONFIG_TEST_OPTS")
+		os.Unsetenv("CONFIG_TEST_BAD_OPTS")
+	}()
+	e := &Env{}
+	got, err := e.GetQueryMap("CONFIG_TEST_OPTS")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := url.Values{"sslmode": {"require"}, "host": {"a", "b"}, "app": {"my app!"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if _, err := e.GetQueryMap("CONFIG_TEST_BAD_OPTS"); err == nil || !strings.Contains(err.Error(), "CONFIG_TEST_BAD_OPTS") {
+		t.Errorf("Expected an error naming the key, got %v", err)
+	}
+}
+
+func TestGetStringsUnique(t *testing.T) {
+	os.Setenv("CONFIG_TEST_UNIQUE", "b, a,b, A,,a")
+	defer os.Unsetenv("CONFIG_TEST_UNIQUE")
+	e := &Env{}
+	if got := e.GetStringsUnique("CONFIG_TEST_UNIQUE"); !reflect.DeepEqual(got, []string{"b", "a", "A"}) {
+		t.Errorf("Expected [b a A], got %q", got)
+	}
+	set := e.GetOrderedSet("CONFIG_TEST_UNIQUE")
+	if !set.Contains("A") || set.Contains("c") || set.Len() != 3 {
+		t.Errorf("Unexpected set contents %q", set.Slice())
+	}
+}
+
+func TestGetStringsWithIncludes(t *testing.T) {
+	dir := t.TempDir()
+	extra, nested, loop := filepath.Join(dir, "extra"), filepath.Join(dir, "nested"), filepath.Join(dir, "loop")
+	os.WriteFile(extra, []byte("# extra hosts\nc\n\n  d  \n@"+nested+"\n"), 0600)
+	os.WriteFile(nested, []byte("e\n"), 0600)
+	os.WriteFile(loop, []byte("@"+loop+"\n"), 0600)
+	e := &Env{}
+
+	os.Setenv("CONFIG_TEST_INCLUDES", "a, b,@"+extra)
+	defer os.Unsetenv("CONFIG_TEST_INCLUDES")
+	got, err := e.GetStringsWithIncludes("CONFIG_TEST_INCLUDES")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	for _, val := range []string{"@" + loop, "@" + filepath.Join(dir, "missing")} {
+		os.Setenv("CONFIG_TEST_INCLUDES", val)
+		if _, err := e.GetStringsWithIncludes("CONFIG_TEST_INCLUDES"); err == nil {
+			t.Errorf("%s: expected an error", val)
+		}
+	}
+}
+
+func TestGetStringsUniqueFold(t *testing.T) {
+	os.Setenv("CONFIG_TEST_FOLD", "Example.com, api.example.com,example.COM,API.example.com, b")
+	defer os.Unsetenv("CONFIG_TEST_FOLD")
+	expected := []string{"Example.com", "api.example.com", "b"}
+	if got := (&Env{}).GetStringsUniqueFold("CONFIG_TEST_FOLD"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestGetIntRangeSlice(t *testing.T) {
+	defer os.Unsetenv("CONFIG_TEST_RANGES")
+	e := &Env{}
+	os.Setenv("CONFIG_TEST_RANGES", "8000-8002, 9000,, 7 - 7,-2--1")
+	got, err := e.GetIntRangeSlice("CONFIG_TEST_RANGES")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := []int{8000, 8001, 8002, 9000, 7, -2, -1}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	for _, val := range []string{"5-3", "a-3", "80x", "1-", "0-65536", "0-60000,1-6000"} {
+		os.Setenv("CONFIG_TEST_RANGES", val)
+		if _, err := e.GetIntRangeSlice("CONFIG_TEST_RANGES"); err == nil {
+			t.Errorf("%s: expected an error", val)
+		}
+	}
+	os.Setenv("CONFIG_TEST_RANGES", "0-65535")
+	if got, err := e.GetIntRangeSlice("CONFIG_TEST_RANGES"); err != nil || len(got) != maxRangeSize {
+		t.Errorf("Expected %d values, got %d (%v)", maxRangeSize, len(got), err)
+	}
+	os.Setenv("CONFIG_TEST_RANGES", fmt.Sprintf("%d-%d,%d", math.MaxInt-1, math.MaxInt, math.MinInt))
+	if got, err := e.GetIntRangeSlice("CONFIG_TEST_RANGES"); err != nil || !reflect.DeepEqual(got, []int{math.MaxInt - 1, math.MaxInt, math.MinInt}) {
+		t.Errorf("Expected the ends of the int range, got %v (%v)", got, err)
+	}
+	os.Setenv("CONFIG_TEST_RANGES", fmt.Sprintf("%d-%d", math.MinInt, math.MaxInt))
+	if _, err := e.GetIntRangeSlice("CONFIG_TEST_RANGES"); err == nil {
+		t.Error("Expected an error for the whole int range")
+	}
+}
+
+func TestGetWeightedStrings(t *testing.T) {
+	defer os.Unsetenv("CONFIG_TEST_WEIGHTED")
+	e := &Env{}
+	os.Setenv("CONFIG_TEST_WEIGHTED", "a:3, b : 0,c,,host:8080:2")
+	got, err := e.GetWeightedStrings("CONFIG_TEST_WEIGHTED")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []WeightedString{{"a", 3}, {"b", 0}, {"c", 1}, {"host:8080", 2}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	for _, val := range []string{"a:-1", "a:x", ":2", "host:8080:"} {
+		os.Setenv("CONFIG_TEST_WEIGHTED", "ok,"+val)
+		if _, err := e.GetWeightedStrings("CONFIG_TEST_WEIGHTED"); err == nil || !strings.Contains(err.Error(), val) {
+			t.Errorf("%s: expected an error naming the element, got %v", val, err)
+		}
+	}
+}
+
+func TestGetStringsTemplated(t *testing.T) {
+	defer os.Unsetenv("CONFIG_TEST_TEMPLATED")
+	e := &Env{}
+	defaults := []string{"a", "b", "c"}
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{"", []string{"a", "b", "c"}},
+		{" , x", []string{"a", "x", "c"}},
+		{"x,,z", []string{"x", "b", "z"}},
+		{"x,y,z,w,", []string{"x", "y", "z", "w", ""}},
+	}
+	for _, test := range tests {
+		os.Setenv("CONFIG_TEST_TEMPLATED", test.value)
+		if got := e.GetStringsTemplated("CONFIG_TEST_TEMPLATED", defaults); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%q: expected %q, got %q", test.value, test.expected, got)
+		}
+	}
+	if !reflect.DeepEqual(defaults, []string{"a", "b", "c"}) {
+		t.Errorf("Defaults were modified: %q", defaults)
+	}
+}
+
+func TestGetPathListQuoted(t *testing.T) {
+	sep := string(os.PathListSeparator)
+	os.Setenv("CONFIG_TEST_PATHS", `"/opt/my tools/bin"`+sep+" /usr/bin "+sep+sep+`'/x'`+sep+`""`+sep+`"/unbalanced`)
+	defer os.Unsetenv("CONFIG_TEST_PATHS")
+	expected := []string{"/opt/my tools/bin", "/usr/bin", "/x", `"/unbalanced`}
+	if got := (&Env{}).GetPathListQuoted("CONFIG_TEST_PATHS"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestGetCIDRs(t *testing.T) {
+	defer os.Unsetenv("CONFIG_TEST_CIDRS")
+	e := &Env{}
+	os.Setenv("CONFIG_TEST_CIDRS", "10.0.0.0/8, 2001:db8::/32,,192.0.2.1/32")
+	nets, err := e.GetCIDRs("CONFIG_TEST_CIDRS")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(nets) != 3 {
+		t.Fatalf("Expected 3 networks, got %v", nets)
+	}
+	for _, ip := range []string{"10.1.2.3", "2001:db8::1", "192.0.2.1"} {
+		if !IPInAnyCIDR(net.ParseIP(ip), nets) {
+			t.Errorf("Expected %s to be allowed", ip)
+		}
+	}
+	if IPInAnyCIDR(net.ParseIP("192.0.2.2"), nets) {
+		t.Error("Expected 192.0.2.2 to be denied")
+	}
+	for _, val := range []string{"192.0.2.1", "10.0.0.0/33", "nope/8"} {
+		os.Setenv("CONFIG_TEST_CIDRS", "10.0.0.0/8,"+val)
+		if _, err := e.GetCIDRs("CONFIG_TEST_CIDRS"); err == nil || !strings.Contains(err.Error(), val) {
+			t.Errorf("%s: expected an error naming the element, got %v", val, err)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	longPollMinBackoff = time.Second
+	longPollMaxBackoff = time.Minute
+)
+
+// LongPollGetter holds the latest config document received from a long-polling HTTP endpoint. See NewLongPollGetter.
+type LongPollGetter struct {
+	Notifier
+	ErrorReporter
+	url        string
+	client     *http.Client
+	values     atomic.Pointer[Map]
+	version    string
+	cancel     context.CancelFunc
+	closeOnce  sync.Once
+	done       chan struct{}
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// NewLongPollGetter : Return a Getter that follows the config document served at url, long-polling for updates so
+// changes propagate within moments over plain HTTP. A nil client means http.DefaultClient; a client with a Timeout
+// must allow longer than the server holds requests open.
+//
+// The server contract is ETag-based. Each request is a GET carrying the version the client has in If-None-Match
+// (the first request has none, and should be answered right away). The server holds the request until it has a newer
+// version, then responds 200 with the whole document and its version in the ETag header; if nothing changes before
+// its own timeout it responds 304 Not Modified, and the client asks again. A 200 without an ETag is an error.
+// Documents are JSON (see FlattenValue) when the Content-Type is application/json, and KEY=VALUE lines otherwise.
+//
+// The first document is fetched before NewLongPollGetter returns, and a failure there is returned. After that the
+// Getter is a Watcher, notified after each new document. Failed polls are retried with exponential backoff (1s up to
+// a minute) while the previous values stay in place; each failure is sent to the Errors() channel, with a count of
+// consecutive failures so persistent problems can be told from blips. Polls are at least a second apart, even if the
+// server answers without holding the request. Call Close to stop polling.
+func NewLongPollGetter(url string, client *http.Client) (*LongPollGetter, error) {
+	return newLongPollGetter(url, client, longPollMinBackoff)
+}
+
+func newLongPollGetter(url string, client *http.Client, minBackoff time.Duration) (*LongPollGetter, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &LongPollGetter{
+		url:        url,
+		client:     client,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+		minBackoff: minBackoff,
+		maxBackoff: longPollMaxBackoff,
+	}
+	if _, err := l.poll(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+	go l.run(ctx)
+	return l, nil
+}
+
+func (l *LongPollGetter) run(ctx context.Context) {
+	defer close(l.done)
+	backoff, failures := l.minBackoff, 0
+	for ctx.Err() == nil {
+		start := time.Now()
+		changed, err := l.poll(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			failures++
+			l.Report(fmt.Errorf("%w (%d consecutive failures)", err, failures))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, l.maxBackoff)
+			continue
+		}
+		backoff, failures = l.minBackoff, 0
+		if changed {
+			l.Notify()
+		}
+		// A server that answers right away instead of holding the request would otherwise be polled in a tight loop.
+		if wait := l.minBackoff - time.Since(start); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+	}
+}
+
+// poll makes one long-poll request, swapping in the new document if there is one.
+func (l *LongPollGetter) poll(ctx context.Context) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.url, nil)
+	if err != nil {
+		return false, fmt.Errorf("config: long-poll %s: %w", l.url, err)
+	}
+	if l.version != "" {
+		req.Header.Set("If-None-Match", l.version)
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("config: long-poll %s: %w", l.url, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && l.values.Load() != nil:
+		return false, nil
+	case resp.StatusCode != http.StatusOK:
+		// Includes a 304 to the first request, which has no version for the server to compare.
+		return false, fmt.Errorf("config: long-poll %s: unexpected status %s", l.url, resp.Status)
+	}
+	version := resp.Header.Get("ETag")
+	if version == "" {
+		// Without a version the next request can't be held until there's a change.
+		return false, fmt.Errorf("config: long-poll %s: response has no ETag", l.url)
+	}
+	data, err := readDocument(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("config: long-poll %s: %w", l.url, err)
+	}
+	values, err := ParseFormat(documentFormat(resp.Header), data)
+	if err != nil {
+		return false, fmt.Errorf("config: long-poll %s: %w", l.url, err)
+	}
+	l.values.Store(&values)
+	l.version = version
+	return true, nil
+}
+
+// Close : Stop polling, abandoning any request in flight. The Getter keeps serving the last document it received.
+func (l *LongPollGetter) Close() error {
+	l.closeOnce.Do(func() {
+		l.cancel()
+		<-l.done
+	})
+	return nil
+}
+
+func (l *LongPollGetter) current() Map {
+	return *l.values.Load()
+}
+
+// Snapshot : Return the values of the latest document, which won't change as new documents arrive.
+func (l *LongPollGetter) Snapshot() Getter {
+	return l.current()
+}
+
+// Get : Return the value for key from the latest document.
+func (l *LongPollGetter) Get(key string) string {
+	return l.current().Get(key)
+}
+
+// Lookup : Return the value for key from the latest document, and whether it's present.
+func (l *LongPollGetter) Lookup(key string) (string, bool) {
+	return l.current().Lookup(key)
+}
+
+// Keys : Return the keys of the latest document.
+func (l *LongPollGetter) Keys() []string {
+	return l.current().Keys()
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (l *LongPollGetter) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(l, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (l *LongPollGetter) GetStrings(key string) []string {
+	return l.current().GetStrings(key)
+}
+
+// MustGet will panic if the key is not present or empty.
+func (l *LongPollGetter) MustGet(key string) string {
+	return MustGet(l, key)
+}
+
+// documentFormat is the format of an HTTP config document: JSON when the Content-Type says so, otherwise env.
+func documentFormat(h http.Header) string {
+	if mediaType, _, _ := mime.ParseMediaType(h.Get("Content-Type")); mediaType == "application/json" {
+		return "json"
+	}
+	return "env"
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLongPollGetter(t *testing.T) {
+	updates := make(chan string)
+	fail := make(chan bool, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == "" {
+			w.Header().Set("ETag", `"1"`)
+			w.Write([]byte("HOST=db1\n"))
+			return
+		}
+		select {
+		case <-fail:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		case doc := <-updates:
+			w.Header().Set("ETag", `"2"`)
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Write([]byte(doc))
+		case <-time.After(10 * time.Millisecond):
+			w.WriteHeader(http.StatusNotModified)
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	l, err := newLongPollGetter(srv.URL, srv.Client(), time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer l.Close()
+	if v := l.Get("HOST"); v != "db1" {
+		t.Errorf("Expected 'db1', got '%s'", v)
+	}
+	watch := l.Watch()
+	fail <- true
+	select {
+	case err := <-l.Errors():
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for an error")
+	}
+	updates <- `{"HOST": "db2", "PORTS": [1, 2]}`
+	select {
+	case <-watch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for an update")
+	}
+	if v := l.Get("HOST"); v != "db2" {
+		t.Errorf("Expected 'db2', got '%s'", v)
+	}
+	if v := l.GetStrings("PORTS"); len(v) != 2 {
+		t.Errorf("Expected 2 ports, got %q", v)
+	}
+
+	if _, err := NewLongPollGetter(srv.URL+"/%zz", nil); err == nil {
+		t.Error("Expected an error for a bad URL")
+	}
+}
+
+func TestLongPollGetterNotModifiedFirst(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer srv.Close()
+	if _, err := newLongPollGetter(srv.URL, srv.Client(), time.Millisecond); err == nil {
+		t.Error("Expected an error when the first poll has no document")
+	}
+}
+
+func TestLongPollGetterServerDoesNotHold(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == "" {
+			w.Header().Set("ETag", `"1"`)
+			w.Write([]byte("HOST=db1\n"))
+			return
+		}
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer srv.Close()
+	l, err := newLongPollGetter(srv.URL, srv.Client(), 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	time.Sleep(250 * time.Millisecond)
+	l.Close()
+	if n := requests.Load(); n > 8 {
+		t.Errorf("Expected polls to be spaced out, got %d requests in 250ms", n)
+	}
+}
+
+func TestLongPollGetterNoETag(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("HOST=db1\n"))
+	}))
+	defer srv.Close()
+	if _, err := newLongPollGetter(srv.URL, srv.Client(), time.Millisecond); err == nil {
+		t.Error("Expected an error for a document without an ETag")
+	}
+}
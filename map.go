@@ -0,0 +1,51 @@
+package config
+
+import (
+	"sort"
+)
+
+// Map is a Getter backed by a map of keys to values. It's the base for getters that load
+// their configuration up front, and is handy for tests and embedded defaults.
+// A Map should not be modified once it's in use.
+type Map map[string]string
+
+// Get : Return the value for key, or "" if it's not present.
+func (m Map) Get(key string) string {
+	return m[key]
+}
+
+// Lookup : Return the value for key and whether it's present in the map.
+func (m Map) Lookup(key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+// Keys : Return the map's keys, sorted.
+func (m Map) Keys() []string {
+	rval := make([]string, 0, len(m))
+	for key := range m {
+		rval = append(rval, key)
+	}
+	sort.Strings(rval)
+	return rval
+}
+
+// Snapshot : A Map doesn't change, so it's its own snapshot.
+func (m Map) Snapshot() Getter {
+	return m
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (m Map) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(m, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (m Map) GetStrings(key string) []string {
+	return SplitStrings(m.Get(key))
+}
+
+// MustGet will panic if the key is not present or empty.
+func (m Map) MustGet(key string) string {
+	return MustGet(m, key)
+}
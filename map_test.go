@@ -0,0 +1,22 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	m := Map{"HOSTS": "a, b", "EMPTY": ""}
+	if v := m.GetOrDefault("EMPTY", "x"); v != "x" {
+		t.Errorf("Expected 'x', got '%s'", v)
+	}
+	if v := m.GetStrings("HOSTS"); !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %q", v)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("MustGet on a missing key should panic")
+		}
+	}()
+	m.MustGet("MISSING")
+}
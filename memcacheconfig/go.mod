@@ -0,0 +1,10 @@
+module github.com/efixler/config/memcacheconfig
+
+go 1.22
+
+require (
+	github.com/bradfitz/gomemcache v0.0.0-20260422231931-4d751bb6e37c
+	github.com/efixler/config v0.0.0-00010101000000-000000000000
+)
+
+replace github.com/efixler/config => ../
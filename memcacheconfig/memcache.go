@@ -0,0 +1,111 @@
+// Package memcacheconfig provides a config.Getter over values kept in memcached, for services whose
+// infrastructure already standardized on it. It's a separate package to keep the memcache client dependency optional.
+package memcacheconfig
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+
+	"github.com/efixler/config"
+)
+
+// DefaultTTL is how long looked-up values are cached, unless overridden with WithTTL.
+const DefaultTTL = 5 * time.Second
+
+// Client is the part of *memcache.Client the Getter uses.
+type Client interface {
+	Get(key string) (*memcache.Item, error)
+}
+
+// Option configures a Getter.
+type Option func(*Getter)
+
+// WithTTL : Cache looked-up values (and misses) for ttl instead of DefaultTTL. A zero ttl disables caching.
+// Memcached can't notify anyone of changes, so the ttl is also how stale a value can get.
+func WithTTL(ttl time.Duration) Option {
+	return func(g *Getter) {
+		g.ttl = ttl
+	}
+}
+
+// Getter reads config values from memcached. It is a config.ErrorGetter.
+type Getter struct {
+	client Client
+	prefix string
+	ttl    time.Duration
+	lock   sync.Mutex
+	cache  map[string]cached
+	now    func() time.Time
+}
+
+type cached struct {
+	val     string
+	expires time.Time
+}
+
+// NewMemcachedGetter : Return a Getter where Get(key) reads the item keyPrefix+key from memcached. Values are cached in
+// process for DefaultTTL, to bound how often memcached is read.
+//
+// A missing item is "" from Get, and an error wrapping config.ErrKeyNotSet from GetErr. Connection and protocol
+// errors (and keys memcached can't store, like ones with spaces) are returned by GetErr; Get returns "" and logs them.
+func NewMemcachedGetter(client Client, keyPrefix string, opts ...Option) *Getter {
+	g := &Getter{client: client, prefix: keyPrefix, ttl: DefaultTTL, cache: make(map[string]cached), now: time.Now}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// GetErr : Read the item for key, from the cache if it's fresh.
+func (g *Getter) GetErr(key string) (string, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	now := g.now()
+	entry, ok := g.cache[key]
+	if !ok || !now.Before(entry.expires) {
+		item, err := g.client.Get(g.prefix + key)
+		if err != nil && !errors.Is(err, memcache.ErrCacheMiss) {
+			return "", fmt.Errorf("memcacheconfig: getting %s: %w", g.prefix+key, err)
+		}
+		entry = cached{expires: now.Add(g.ttl)}
+		if item != nil {
+			entry.val = string(item.Value)
+		}
+		if g.ttl > 0 {
+			g.cache[key] = entry
+		}
+	}
+	if entry.val == "" {
+		return "", fmt.Errorf("memcacheconfig: %s: %w", g.prefix+key, config.ErrKeyNotSet)
+	}
+	return entry.val, nil
+}
+
+// Get : Read the item for key, or "" if it's missing or memcached can't be reached.
+func (g *Getter) Get(key string) string {
+	val, err := g.GetErr(key)
+	if err != nil && !errors.Is(err, config.ErrKeyNotSet) {
+		log.Print(err)
+	}
+	return val
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (g *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(g, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (g *Getter) GetStrings(key string) []string {
+	return config.SplitStrings(g.Get(key))
+}
+
+// MustGet will panic if the item is not present or empty.
+func (g *Getter) MustGet(key string) string {
+	return config.MustGet(g, key)
+}
@@ -0,0 +1,61 @@
+package memcacheconfig
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+
+	"github.com/efixler/config"
+)
+
+type fakeClient struct {
+	items map[string]string
+	err   error
+	calls int
+}
+
+func (f *fakeClient) Get(key string) (*memcache.Item, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	val, ok := f.items[key]
+	if !ok {
+		return nil, memcache.ErrCacheMiss
+	}
+	return &memcache.Item{Key: key, Value: []byte(val)}, nil
+}
+
+func TestMemcachedGetter(t *testing.T) {
+	now := time.Now()
+	client := &fakeClient{items: map[string]string{"app/HOSTS": "a, b"}}
+	g := NewMemcachedGetter(client, "app/", WithTTL(time.Minute))
+	g.now = func() time.Time { return now }
+
+	if v := g.GetStrings("HOSTS"); len(v) != 2 || v[1] != "b" {
+		t.Errorf("Expected [a b], got %q", v)
+	}
+	if _, err := g.GetErr("MISSING"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+	client.items["app/HOSTS"] = "c"
+	g.Get("HOSTS")
+	g.Get("MISSING")
+	if client.calls != 2 {
+		t.Errorf("Expected cached lookups, got %d calls", client.calls)
+	}
+	now = now.Add(2 * time.Minute)
+	if v := g.Get("HOSTS"); v != "c" {
+		t.Errorf("Expected the refreshed 'c', got '%s'", v)
+	}
+
+	client.err = errors.New("connection refused")
+	if _, err := g.GetErr("OTHER"); err == nil || errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected a connection error, got %v", err)
+	}
+	if v := g.GetOrDefault("OTHER", "x"); v != "x" {
+		t.Errorf("Expected the default, got '%s'", v)
+	}
+}
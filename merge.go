@@ -0,0 +1,247 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// MergeStrategy selects which of several Getters supplies a value in Merge().
+type MergeStrategy int
+
+const (
+	// FirstNonEmpty takes the value from the first getter with a non-empty value.
+	FirstNonEmpty MergeStrategy = iota
+	// LastNonEmpty takes the value from the last getter with a non-empty value.
+	LastNonEmpty
+	// FirstPresent takes the value from the first getter where the key is set, even if it's set to "".
+	FirstPresent
+	// LastPresent takes the value from the last getter where the key is set, even if it's set to "".
+	LastPresent
+)
+
+// Merge : Compose getters into one Getter, with strategy making the precedence explicit at the call site.
+// Presence is determined with Lookup(), so getters that aren't Lookers treat "" as not present.
+//
+// For example, merging getters a and b where a has KEY="" and b has KEY="x":
+//
+//	FirstNonEmpty  "x"  (a's value is empty, so b wins)
+//	LastNonEmpty   "x"
+//	FirstPresent   ""   (a has the key, so a wins)
+//	LastPresent    "x"
+//
+// If neither has KEY, every strategy returns "". GetStrings() is delegated to the getter that supplies the
+// value, and Keys() returns the union of the keys of the getters that are Listers.
+func Merge(strategy MergeStrategy, getters ...Getter) Getter {
+	return &merged{strategy: strategy, getters: getters}
+}
+
+type merged struct {
+	strategy MergeStrategy
+	getters  []Getter
+}
+
+// resolve returns the getter that supplies key under the merge strategy, or nil if none does.
+func (m *merged) resolve(key string) (Getter, string) {
+	for i := range m.getters {
+		g := m.getters[i]
+		if m.strategy == LastNonEmpty || m.strategy == LastPresent {
+			g = m.getters[len(m.getters)-1-i]
+		}
+		v, ok := Lookup(g, key)
+		if m.strategy == FirstNonEmpty || m.strategy == LastNonEmpty {
+			ok = v != ""
+		}
+		if ok {
+			return g, v
+		}
+	}
+	return nil, ""
+}
+
+func (m *merged) Get(key string) string {
+	_, v := m.resolve(key)
+	return v
+}
+
+func (m *merged) Lookup(key string) (string, bool) {
+	g, v := m.resolve(key)
+	return v, g != nil
+}
+
+func (m *merged) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(m, key, dflt)
+}
+
+func (m *merged) GetStrings(key string) []string {
+	if g, _ := m.resolve(key); g != nil {
+		return g.GetStrings(key)
+	}
+	return SplitStrings("")
+}
+
+func (m *merged) MustGet(key string) string {
+	return MustGet(m, key)
+}
+
+func (m *merged) Keys() []string {
+	return unionKeys(m.getters...)
+}
+
+// AppendPrefix marks a key in an overlay whose list is appended to the base's list for the key without the prefix,
+// e.g. "+ALLOWED_HOSTS" in an override adds to ALLOWED_HOSTS. See MergeLists.
+const AppendPrefix = "+"
+
+// ListMerge configures how MergeLists combines list values.
+type ListMerge struct {
+	// Append are keys whose lists are concatenated across every getter that sets them, instead of being replaced.
+	Append []string
+	// Dedup drops repeated elements from appended lists, keeping the first occurrence of each.
+	Dedup bool
+}
+
+// MergeLists : Like Merge, but some list values are assembled across the getters instead of being taken from
+// just one of them, e.g. an allowlist built up from a file, then an environment overlay, then runtime overrides.
+// Keys are appended in either of two ways:
+//
+//   - Keys named in lists.Append are concatenated from every getter that sets them.
+//   - A getter that sets "+KEY" (see AppendPrefix) appends that list to KEY, whatever the getters below it said.
+//     A getter that sets both provides KEY and then appends "+KEY" to it.
+//
+// Lists are assembled from the lowest-precedence getter up, so the base's elements come first and each overlay's
+// follow: with FirstPresent or FirstNonEmpty that's the last getter first, and with LastPresent or LastNonEmpty it's
+// the first getter first. Whether a getter sets a key follows the strategy, exactly as in Merge. A getter that sets
+// a key not marked for appending still replaces everything below it, so replacing stays the default, and a key
+// that's never appended behaves just as it would in Merge.
+//
+// Each getter's list is read with its GetStrings(), and empty elements are dropped. Appended lists keep every
+// element, in order, unless lists.Dedup is set. Get returns an appended list joined with commas, so GetStrings()
+// splits it back into the same elements. Keys() lists KEY for a "+KEY" and never the prefixed form.
+func MergeLists(strategy MergeStrategy, lists ListMerge, getters ...Getter) Getter {
+	m := &listMerged{
+		merged: merged{strategy: strategy, getters: getters},
+		append: make(map[string]bool, len(lists.Append)),
+		dedup:  lists.Dedup,
+	}
+	for _, key := range lists.Append {
+		m.append[key] = true
+	}
+	return m
+}
+
+type listMerged struct {
+	merged
+	append map[string]bool
+	dedup  bool
+}
+
+// has reports whether g sets key under the merge strategy.
+func (m *listMerged) has(g Getter, key string) bool {
+	v, ok := Lookup(g, key)
+	if m.strategy == FirstNonEmpty || m.strategy == LastNonEmpty {
+		return v != ""
+	}
+	return ok
+}
+
+// assemble returns the list for key built up from the lowest-precedence getter, whether any getter sets it, and
+// whether anything was appended. When nothing was, the caller should defer to the plain merge.
+func (m *listMerged) assemble(key string) ([]string, bool, bool) {
+	var list []string
+	found, appended := false, false
+	for i := range m.getters {
+		g := m.getters[len(m.getters)-1-i]
+		if m.strategy == LastNonEmpty || m.strategy == LastPresent {
+			g = m.getters[i]
+		}
+		if m.has(g, key) {
+			if found && m.append[key] {
+				list, appended = append(list, listElements(g, key)...), true
+			} else {
+				list, appended = listElements(g, key), false
+			}
+			found = true
+		}
+		if m.has(g, AppendPrefix+key) {
+			list = append(list, listElements(g, AppendPrefix+key)...)
+			found, appended = true, true
+		}
+	}
+	if appended && m.dedup {
+		list = uniqueStrings(list)
+	}
+	return list, found, appended
+}
+
+// listElements returns g's list for key without empty elements.
+func listElements(g Getter, key string) []string {
+	rval := make([]string, 0)
+	for _, val := range g.GetStrings(key) {
+		if val != "" {
+			rval = append(rval, val)
+		}
+	}
+	return rval
+}
+
+func (m *listMerged) Get(key string) string {
+	v, _ := m.Lookup(key)
+	return v
+}
+
+func (m *listMerged) Lookup(key string) (string, bool) {
+	if list, found, appended := m.assemble(key); appended {
+		return strings.Join(list, ","), true
+	} else if !found {
+		return "", false
+	}
+	return m.merged.Lookup(key)
+}
+
+func (m *listMerged) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(m, key, dflt)
+}
+
+func (m *listMerged) GetStrings(key string) []string {
+	if list, _, appended := m.assemble(key); appended {
+		return list
+	}
+	return m.merged.GetStrings(key)
+}
+
+func (m *listMerged) MustGet(key string) string {
+	return MustGet(m, key)
+}
+
+func (m *listMerged) Keys() []string {
+	seen := make(map[string]bool)
+	rval := make([]string, 0)
+	for _, key := range unionKeys(m.getters...) {
+		key = strings.TrimPrefix(key, AppendPrefix)
+		if !seen[key] {
+			seen[key] = true
+			rval = append(rval, key)
+		}
+	}
+	sort.Strings(rval)
+	return rval
+}
+
+// unionKeys returns the sorted union of the keys of any Listers in getters.
+func unionKeys(getters ...Getter) []string {
+	seen := make(map[string]bool)
+	rval := make([]string, 0)
+	for _, g := range getters {
+		l, ok := g.(Lister)
+		if !ok {
+			continue
+		}
+		for _, key := range l.Keys() {
+			if !seen[key] {
+				seen[key] = true
+				rval = append(rval, key)
+			}
+		}
+	}
+	sort.Strings(rval)
+	return rval
+}
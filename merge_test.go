@@ -0,0 +1,87 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeStrategies(t *testing.T) {
+	a := Map{"KEY": "", "A": "a1", "BOTH": "from-a"}
+	b := Map{"KEY": "x", "B": "b1", "BOTH": "from-b"}
+	tests := []struct {
+		strategy MergeStrategy
+		key      string
+		expected string
+	}{
+		{FirstNonEmpty, "KEY", "x"},
+		{LastNonEmpty, "KEY", "x"},
+		{FirstPresent, "KEY", ""},
+		{LastPresent, "KEY", "x"},
+		{FirstNonEmpty, "BOTH", "from-a"},
+		{LastNonEmpty, "BOTH", "from-b"},
+		{LastPresent, "A", "a1"},
+		{FirstPresent, "MISSING", ""},
+	}
+	for _, test := range tests {
+		if v := Merge(test.strategy, a, b).Get(test.key); v != test.expected {
+			t.Errorf("Strategy %d, key %s: expected '%s', got '%s'", test.strategy, test.key, test.expected, v)
+		}
+	}
+	if _, ok := Lookup(Merge(FirstPresent, a, b), "MISSING"); ok {
+		t.Error("Missing key should not be present in merged getter")
+	}
+}
+
+func TestMergeKeys(t *testing.T) {
+	m := Merge(FirstNonEmpty, Map{"A": "1", "C": "3"}, Map{"B": "2", "A": "4"}).(Lister)
+	if keys := m.Keys(); !reflect.DeepEqual(keys, []string{"A", "B", "C"}) {
+		t.Errorf("Expected union of keys, got %q", keys)
+	}
+}
+
+func TestMergeLists(t *testing.T) {
+	base := Map{"HOSTS": "a, b", "PORTS": "80", "NAME": "base"}
+	env := Map{"HOSTS": "c,a", "+PORTS": "443", "NAME": "env"}
+	overrides := Map{"+HOSTS": "d", "EMPTY": ""}
+	tests := []struct {
+		name     string
+		getter   Getter
+		key      string
+		expected []string
+	}{
+		{"append key", MergeLists(FirstPresent, ListMerge{Append: []string{"HOSTS"}}, overrides, env, base),
+			"HOSTS", []string{"a", "b", "c", "a", "d"}},
+		{"dedup", MergeLists(FirstPresent, ListMerge{Append: []string{"HOSTS"}, Dedup: true}, overrides, env, base),
+			"HOSTS", []string{"a", "b", "c", "d"}},
+		{"last strategies run the other way", MergeLists(LastPresent, ListMerge{Append: []string{"HOSTS"}}, base, env),
+			"HOSTS", []string{"a", "b", "c", "a"}},
+		{"prefix convention", MergeLists(FirstPresent, ListMerge{}, env, base), "PORTS", []string{"80", "443"}},
+		{"replace by default", MergeLists(FirstPresent, ListMerge{}, env, base), "HOSTS", []string{"c", "a"}},
+		{"replace over an append", MergeLists(FirstPresent, ListMerge{}, Map{"PORTS": "8080"}, env, base),
+			"PORTS", []string{"8080"}},
+		{"prefix without a base", MergeLists(FirstPresent, ListMerge{}, overrides), "HOSTS", []string{"d"}},
+	}
+	for _, test := range tests {
+		if v := test.getter.GetStrings(test.key); !reflect.DeepEqual(v, test.expected) {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expected, v)
+		}
+	}
+
+	m := MergeLists(FirstPresent, ListMerge{Append: []string{"HOSTS"}}, overrides, env, base)
+	if v := m.Get("HOSTS"); v != "a,b,c,a,d" {
+		t.Errorf("Expected the appended list joined with commas, got %q", v)
+	}
+	if v := m.Get("NAME"); v != "env" {
+		t.Errorf("Expected a plain key to merge as usual, got %q", v)
+	}
+	if v, ok := Lookup(m, "EMPTY"); !ok || v != "" {
+		t.Errorf("Expected EMPTY to be present and empty, got %q, %v", v, ok)
+	}
+	if _, ok := Lookup(m, "MISSING"); ok {
+		t.Error("Missing key should not be present")
+	}
+	expected := []string{"EMPTY", "HOSTS", "NAME", "PORTS"}
+	if keys := m.(Lister).Keys(); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Expected keys %q, got %q", expected, keys)
+	}
+}
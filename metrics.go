@@ -0,0 +1,83 @@
+package config
+
+import (
+	"context"
+	"errors"
+)
+
+// Metrics receives a callback for each lookup made through WithMetrics, to feed counters in a metrics system.
+// Implementations must be safe for concurrent use, and fast: they're called inline on every lookup.
+type Metrics interface {
+	// Read is called for every lookup.
+	Read(key string)
+	// Miss is called for lookups that found no value.
+	Miss(key string)
+	// Error is called for lookups that failed, e.g. because the value couldn't be fetched, decrypted or parsed.
+	// Not-set errors (ErrKeyNotSet) are counted as misses instead.
+	Error(key string)
+}
+
+// WithMetrics : Wrap a Getter so that its lookups are reported to m. Every Get, GetErr, GetContext, Lookup and
+// GetStrings counts as a read; a lookup that returns no value is also a miss, and one that fails is also an error.
+// GetOrDefault and MustGet count through Get.
+func WithMetrics(g Getter, m Metrics) Getter {
+	return &metered{g: g, m: m}
+}
+
+type metered struct {
+	g Getter
+	m Metrics
+}
+
+func (w *metered) record(key string, val string, err error) {
+	w.m.Read(key)
+	switch {
+	case err != nil && !errors.Is(err, ErrKeyNotSet):
+		w.m.Error(key)
+	case val == "":
+		w.m.Miss(key)
+	}
+}
+
+func (w *metered) GetErr(key string) (string, error) {
+	val, err := GetErr(w.g, key)
+	w.record(key, val, err)
+	return val, err
+}
+
+func (w *metered) GetContext(ctx context.Context, key string) (string, error) {
+	val, err := GetContext(ctx, w.g, key)
+	w.record(key, val, err)
+	return val, err
+}
+
+func (w *metered) Lookup(key string) (string, bool) {
+	val, ok := Lookup(w.g, key)
+	w.m.Read(key)
+	if !ok {
+		w.m.Miss(key)
+	}
+	return val, ok
+}
+
+func (w *metered) Get(key string) string {
+	val, _ := w.GetErr(key)
+	return val
+}
+
+func (w *metered) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(w, key, dflt)
+}
+
+func (w *metered) GetStrings(key string) []string {
+	vals := w.g.GetStrings(key)
+	w.m.Read(key)
+	if isEmptyList(vals) {
+		w.m.Miss(key)
+	}
+	return vals
+}
+
+func (w *metered) MustGet(key string) string {
+	return MustGet(w, key)
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type countingMetrics struct {
+	reads, misses, errs map[string]int
+}
+
+func newCountingMetrics() *countingMetrics {
+	return &countingMetrics{reads: map[string]int{}, misses: map[string]int{}, errs: map[string]int{}}
+}
+
+func (c *countingMetrics) Read(key string)  { c.reads[key]++ }
+func (c *countingMetrics) Miss(key string)  { c.misses[key]++ }
+func (c *countingMetrics) Error(key string) { c.errs[key]++ }
+
+type failingGetter struct {
+	Map
+}
+
+func (f failingGetter) GetErr(key string) (string, error) {
+	if key == "BAD" {
+		return "", errors.New("decrypt failed")
+	}
+	return f.Map.Get(key), nil
+}
+
+func TestWithMetrics(t *testing.T) {
+	m := newCountingMetrics()
+	g := WithMetrics(failingGetter{Map{"HOST": "db1"}}, m)
+	g.Get("HOST")
+	g.GetOrDefault("PORT", "80")
+	g.GetStrings("HOST")
+	Lookup(g, "PORT")
+	if _, err := GetErr(g, "BAD"); err == nil {
+		t.Error("Expected the error to pass through")
+	}
+	if expected := map[string]int{"HOST": 2, "PORT": 2, "BAD": 1}; !reflect.DeepEqual(m.reads, expected) {
+		t.Errorf("Expected reads %v, got %v", expected, m.reads)
+	}
+	if expected := map[string]int{"PORT": 2}; !reflect.DeepEqual(m.misses, expected) {
+		t.Errorf("Expected misses %v, got %v", expected, m.misses)
+	}
+	if expected := map[string]int{"BAD": 1}; !reflect.DeepEqual(m.errs, expected) {
+		t.Errorf("Expected errors %v, got %v", expected, m.errs)
+	}
+}
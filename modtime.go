@@ -0,0 +1,37 @@
+package config
+
+import (
+	"time"
+)
+
+// TimestampedGetter is implemented by Getters that know when a value last changed.
+// ModTime returns that time, and false if it isn't known for key.
+//
+// Of the getters in this package, the one returned by NewDirGetter supplies real timestamps (each file's mtime);
+// NewFileGetter and NewCompressedFileGetter report the mtime of the file their values were read from, and
+// HTTPGetter the Last-Modified time of its current document, when the server sends one. s3config's Getter reports
+// the LastModified time of its object.
+type TimestampedGetter interface {
+	ModTime(key string) (time.Time, bool)
+}
+
+// ModTime : Return g.ModTime(key) if g is a TimestampedGetter, otherwise the zero time and false.
+func ModTime(g Getter, key string) (time.Time, bool) {
+	if tg, ok := g.(TimestampedGetter); ok {
+		return tg.ModTime(key)
+	}
+	return time.Time{}, false
+}
+
+// fileValues holds the values parsed from a file, along with the file's mtime.
+type fileValues struct {
+	Map
+	modTime time.Time
+}
+
+func (f *fileValues) ModTime(key string) (time.Time, bool) {
+	if _, ok := f.Map[key]; !ok {
+		return time.Time{}, false
+	}
+	return f.modTime, true
+}
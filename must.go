@@ -0,0 +1,64 @@
+package config
+
+import (
+	"log"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+var secretPredicate atomic.Pointer[func(key string) bool]
+
+// SetSecretPredicate : Declare, package-wide, which keys hold secrets, so that their values never appear in a panic
+// from a Must* accessor; Redacted is shown instead. Keys that a Getter itself marks as secret (see SecretMarker)
+// are always masked. A nil predicate clears it. Call it once at startup, e.g. with a suffix match on "_PASSWORD".
+func SetSecretPredicate(isSecret func(key string) bool) {
+	if isSecret == nil {
+		secretPredicate.Store(nil)
+		return
+	}
+	secretPredicate.Store(&isSecret)
+}
+
+// isSecretKey reports whether key matches the package-wide secret predicate.
+func isSecretKey(key string) bool {
+	p := secretPredicate.Load()
+	return p != nil && (*p)(key)
+}
+
+// displayValue is val as it can appear in a panic or log message: Redacted if key is secret.
+func displayValue(g Getter, key string, val string) string {
+	if isSecretKey(key) || IsSecret(g, key) {
+		return Redacted
+	}
+	return strconv.Quote(val)
+}
+
+// mustParse panics if key is unset or its value doesn't parse, masking the value if the key is secret.
+func mustParse[T any](g Getter, key string, kind string, parse func(string) (T, error)) T {
+	val, err := parse(MustGet(g, key))
+	if err != nil {
+		log.Panicf("%s config value %s is not a valid %s.", key, displayValue(g, key, g.Get(key)), kind)
+	}
+	return val
+}
+
+// MustGetInt : Return key's value as an int, panicking if it's unset or not an int.
+func MustGetInt(g Getter, key string) int {
+	return mustParse(g, key, "int", strconv.Atoi)
+}
+
+// MustGetBool : Return key's value as a bool (per strconv.ParseBool), panicking if it's unset or invalid.
+func MustGetBool(g Getter, key string) bool {
+	return mustParse(g, key, "bool", strconv.ParseBool)
+}
+
+// MustGetDuration : Return key's value as a time.Duration, panicking if it's unset or invalid.
+func MustGetDuration(g Getter, key string) time.Duration {
+	return mustParse(g, key, "duration", time.ParseDuration)
+}
+
+// MustGetFloat : Return key's value as a float64, panicking if it's unset or invalid.
+func MustGetFloat(g Getter, key string) float64 {
+	return mustParse(g, key, "float", func(s string) (float64, error) { return strconv.ParseFloat(s, 64) })
+}
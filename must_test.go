@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recoverPanic returns the panic message from fn, or "" if it didn't panic.
+func recoverPanic(fn func()) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = fmt.Sprint(r)
+		}
+	}()
+	fn()
+	return ""
+}
+
+func TestMustGetMasksSecrets(t *testing.T) {
+	SetSecretPredicate(func(key string) bool { return strings.HasSuffix(key, "_TOKEN") })
+	defer SetSecretPredicate(nil)
+	g := Map{"PORT": "80x", "API_TOKEN": "hunter2", "TIMEOUT": "5s"}
+
+	msg := recoverPanic(func() { MustGetInt(g, "API_TOKEN") })
+	if msg == "" || strings.Contains(msg, "hunter2") || !strings.Contains(msg, Redacted) {
+		t.Errorf("Expected a masked panic, got '%s'", msg)
+	}
+	if msg := recoverPanic(func() { MustGetInt(g, "PORT") }); !strings.Contains(msg, `"80x"`) {
+		t.Errorf("Expected the non-secret value in the panic, got '%s'", msg)
+	}
+	if msg := recoverPanic(func() { MustGetBool(g, "UNSET") }); !strings.Contains(msg, "not set") {
+		t.Errorf("Expected a not set panic, got '%s'", msg)
+	}
+	if v := MustGetDuration(g, "TIMEOUT"); v != 5*time.Second {
+		t.Errorf("Expected 5s, got %v", v)
+	}
+}
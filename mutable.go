@@ -0,0 +1,110 @@
+package config
+
+import (
+	"sync"
+)
+
+// MutableGetter layers runtime overrides, set through an admin panel or the like, over a base Getter. See NewMutableGetter.
+type MutableGetter struct {
+	Notifier
+	base      Getter
+	lock      sync.RWMutex
+	overrides map[string]string
+	onWrite   []func(key string)
+}
+
+// NewMutableGetter : Return a Getter where values set with Set override those of base, until they're Unset.
+// It's safe for concurrent use, and a Watcher, notified after every Set and Unset.
+func NewMutableGetter(base Getter) *MutableGetter {
+	return &MutableGetter{base: base, overrides: make(map[string]string)}
+}
+
+// Set : Override the value for key.
+func (m *MutableGetter) Set(key string, val string) {
+	m.lock.Lock()
+	m.overrides[key] = val
+	m.lock.Unlock()
+	m.written(key)
+}
+
+// Unset : Drop the override for key, so its value comes from the base Getter again.
+func (m *MutableGetter) Unset(key string) {
+	m.lock.Lock()
+	delete(m.overrides, key)
+	m.lock.Unlock()
+	m.written(key)
+}
+
+// written runs the write hooks (synchronously, so they're done before Set returns) and notifies watchers.
+func (m *MutableGetter) written(key string) {
+	m.lock.RLock()
+	hooks := m.onWrite
+	m.lock.RUnlock()
+	for _, hook := range hooks {
+		hook(key)
+	}
+	m.Notify()
+}
+
+// Lookup : Return the override for key if there is one, otherwise the base Getter's value.
+func (m *MutableGetter) Lookup(key string) (string, bool) {
+	m.lock.RLock()
+	val, ok := m.overrides[key]
+	m.lock.RUnlock()
+	if ok {
+		return val, true
+	}
+	return Lookup(m.base, key)
+}
+
+// Keys : Return the sorted union of the overridden keys and the base Getter's keys, if it's a Lister.
+func (m *MutableGetter) Keys() []string {
+	m.lock.RLock()
+	overrides := Map(copyValues(m.overrides))
+	m.lock.RUnlock()
+	return unionKeys(overrides, m.base)
+}
+
+// Get : Return the override for key if there is one, otherwise the base Getter's value.
+func (m *MutableGetter) Get(key string) string {
+	val, _ := m.Lookup(key)
+	return val
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (m *MutableGetter) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(m, key, dflt)
+}
+
+// GetStrings : Split the override for key like Env.GetStrings, or return the base Getter's GetStrings.
+func (m *MutableGetter) GetStrings(key string) []string {
+	m.lock.RLock()
+	val, ok := m.overrides[key]
+	m.lock.RUnlock()
+	if ok {
+		return SplitStrings(val)
+	}
+	return m.base.GetStrings(key)
+}
+
+// MustGet will panic if the key is not present or empty.
+func (m *MutableGetter) MustGet(key string) string {
+	return MustGet(m, key)
+}
+
+// WithReadYourWrites : Return cached, a caching Getter over mutable (like Cached(mutable, ...)), arranging for every
+// Set and Unset on mutable to invalidate cached's entry for the key before it returns. Without that, a Get right after
+// a Set can return the stale cached value until the TTL runs out. Other keys stay cached.
+//
+// cached has to have an Invalidate(key string) method, like *CachedGetter. If it doesn't, there's no way to keep it
+// consistent, so mutable itself is returned and reads aren't cached.
+func WithReadYourWrites(mutable *MutableGetter, cached Getter) Getter {
+	inv, ok := cached.(interface{ Invalidate(string) })
+	if !ok {
+		return mutable
+	}
+	mutable.lock.Lock()
+	defer mutable.lock.Unlock()
+	mutable.onWrite = append(mutable.onWrite, inv.Invalidate)
+	return cached
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMutableGetter(t *testing.T) {
+	m := NewMutableGetter(Map{"HOST": "db1", "PORT": "5432"})
+	watch := m.Watch()
+	m.Set("HOST", "db2")
+	m.Set("MODE", "")
+	if v := m.Get("HOST"); v != "db2" {
+		t.Errorf("Expected the override 'db2', got '%s'", v)
+	}
+	if _, ok := m.Lookup("MODE"); !ok {
+		t.Error("Expected an empty override to be present")
+	}
+	if keys := m.Keys(); !reflect.DeepEqual(keys, []string{"HOST", "MODE", "PORT"}) {
+		t.Errorf("Unexpected keys %q", keys)
+	}
+	m.Unset("HOST")
+	if v := m.Get("HOST"); v != "db1" {
+		t.Errorf("Expected the base value 'db1' after Unset, got '%s'", v)
+	}
+	select {
+	case <-watch:
+	default:
+		t.Error("Expected a notification")
+	}
+}
+
+func TestWithReadYourWrites(t *testing.T) {
+	m := NewMutableGetter(Map{"HOST": "db1", "PORT": "5432"})
+	g := WithReadYourWrites(m, Cached(m, time.Hour, time.Hour))
+	g.Get("HOST")
+	g.GetStrings("HOST")
+	g.Get("MISSING")
+	m.Set("HOST", "db2")
+	m.Set("MISSING", "here")
+	if v := g.Get("HOST"); v != "db2" {
+		t.Errorf("Expected the write to be visible through the cache, got '%s'", v)
+	}
+	if v := g.GetStrings("HOST"); !reflect.DeepEqual(v, []string{"db2"}) {
+		t.Errorf("Expected the write to be visible to GetStrings, got %q", v)
+	}
+	if v := g.Get("MISSING"); v != "here" {
+		t.Errorf("Expected a cached miss to be invalidated, got '%s'", v)
+	}
+	if g := WithReadYourWrites(m, WithURLDecode(m)); g != Getter(m) {
+		t.Error("Expected the mutable getter for a cache that can't be invalidated")
+	}
+}
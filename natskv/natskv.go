@@ -0,0 +1,139 @@
+// Package natskv provides a config.Getter over a NATS JetStream key/value bucket, for NATS-native services that
+// already keep their config there.
+package natskv
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/efixler/config"
+)
+
+// Getter serves the values of a KV bucket from an in-memory cache that a KV watch keeps current.
+// It is a config.ErrorGetter, a config.Lister and a config.Watcher.
+type Getter struct {
+	config.Notifier
+	config.ErrorReporter
+	kv       nats.KeyValue
+	watcher  nats.KeyWatcher
+	lock     sync.RWMutex
+	values   map[string]string
+	stopping bool
+	stopped  error
+}
+
+// NewNatsKVGetter : Load the bucket and return a Getter serving its values locally. A watch on the whole bucket
+// keeps the values current, notifying Watch() channels after each update; NewNatsKVGetter returns once the
+// watch has delivered the bucket's initial contents. GetStrings splits values on commas, like the other getters.
+//
+// If the watch ends other than through Stop (the connection is closed, for instance), the Getter falls back to
+// reading each key from the bucket: the end of the watch is sent to the Errors() channel, and connection errors
+// surface through GetErr. Call Stop when you're done with the Getter.
+func NewNatsKVGetter(kv nats.KeyValue) (*Getter, error) {
+	watcher, err := kv.WatchAll()
+	if err != nil {
+		return nil, fmt.Errorf("natskv: watching %s: %w", kv.Bucket(), err)
+	}
+	g := &Getter{kv: kv, watcher: watcher, values: make(map[string]string)}
+	for entry := range watcher.Updates() {
+		if entry == nil {
+			go g.follow()
+			return g, nil
+		}
+		g.apply(entry)
+	}
+	return nil, fmt.Errorf("natskv: watch of %s ended before the initial values were loaded", kv.Bucket())
+}
+
+func (g *Getter) apply(entry nats.KeyValueEntry) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	switch entry.Operation() {
+	case nats.KeyValuePut:
+		g.values[entry.Key()] = string(entry.Value())
+	default:
+		delete(g.values, entry.Key())
+	}
+}
+
+func (g *Getter) follow() {
+	for entry := range g.watcher.Updates() {
+		if entry != nil {
+			g.apply(entry)
+			g.Notify()
+		}
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.stopping {
+		return
+	}
+	g.stopped = fmt.Errorf("natskv: watch of %s ended, reading from the bucket directly", g.kv.Bucket())
+	g.Report(g.stopped)
+}
+
+// Stop : Stop watching the bucket. The Getter keeps serving the values it has.
+func (g *Getter) Stop() error {
+	g.lock.Lock()
+	g.stopping = true
+	g.lock.Unlock()
+	return g.watcher.Stop()
+}
+
+// GetErr : Return the latest value for key. A key that isn't in the bucket is an error wrapping config.ErrKeyNotSet.
+func (g *Getter) GetErr(key string) (string, error) {
+	g.lock.RLock()
+	val, ok := g.values[key]
+	stopped := g.stopped
+	g.lock.RUnlock()
+	if stopped == nil {
+		if !ok {
+			return "", fmt.Errorf("natskv: %s: %w", key, config.ErrKeyNotSet)
+		}
+		return val, nil
+	}
+	entry, err := g.kv.Get(key)
+	if errors.Is(err, nats.ErrKeyNotFound) {
+		return "", fmt.Errorf("natskv: %s: %w", key, config.ErrKeyNotSet)
+	} else if err != nil {
+		return "", fmt.Errorf("natskv: %s: %w", key, err)
+	}
+	return string(entry.Value()), nil
+}
+
+// Get : Return the latest value for key, or "".
+func (g *Getter) Get(key string) string {
+	val, _ := g.GetErr(key)
+	return val
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (g *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(g, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (g *Getter) GetStrings(key string) []string {
+	return config.SplitStrings(g.Get(key))
+}
+
+// MustGet will panic if the key is not present or empty.
+func (g *Getter) MustGet(key string) string {
+	return config.MustGet(g, key)
+}
+
+// Keys : Return the keys currently in the cache, sorted.
+func (g *Getter) Keys() []string {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	rval := make([]string, 0, len(g.values))
+	for key := range g.values {
+		rval = append(rval, key)
+	}
+	sort.Strings(rval)
+	return rval
+}
@@ -0,0 +1,119 @@
+package natskv
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/efixler/config"
+)
+
+type fakeEntry struct {
+	nats.KeyValueEntry
+	key string
+	val string
+	op  nats.KeyValueOp
+}
+
+func (e *fakeEntry) Key() string                { return e.key }
+func (e *fakeEntry) Value() []byte              { return []byte(e.val) }
+func (e *fakeEntry) Operation() nats.KeyValueOp { return e.op }
+
+type fakeWatcher struct {
+	nats.KeyWatcher
+	updates chan nats.KeyValueEntry
+}
+
+func (w *fakeWatcher) Updates() <-chan nats.KeyValueEntry { return w.updates }
+func (w *fakeWatcher) Stop() error                        { close(w.updates); return nil }
+
+type fakeKV struct {
+	nats.KeyValue
+	watcher *fakeWatcher
+}
+
+func (kv *fakeKV) Bucket() string { return "config" }
+
+func (kv *fakeKV) WatchAll(...nats.WatchOpt) (nats.KeyWatcher, error) { return kv.watcher, nil }
+
+func (kv *fakeKV) Get(string) (nats.KeyValueEntry, error) {
+	return nil, nats.ErrConnectionClosed
+}
+
+func TestNatsKVGetter(t *testing.T) {
+	updates := make(chan nats.KeyValueEntry, 10)
+	updates <- &fakeEntry{key: "HOSTS", val: "a, b", op: nats.KeyValuePut}
+	updates <- &fakeEntry{key: "MODE", val: "blue", op: nats.KeyValuePut}
+	updates <- nil
+	kv := &fakeKV{watcher: &fakeWatcher{updates: updates}}
+
+	g, err := NewNatsKVGetter(kv)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := g.GetStrings("HOSTS"); len(v) != 2 || v[1] != "b" {
+		t.Errorf("Expected [a b], got %q", v)
+	}
+	changes := g.Watch()
+	updates <- &fakeEntry{key: "MODE", val: "green", op: nats.KeyValuePut}
+	updates <- &fakeEntry{key: "HOSTS", op: nats.KeyValueDelete}
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a change notification")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for g.Get("HOSTS") != "" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if v := g.Get("MODE"); v != "green" {
+		t.Errorf("Expected updated value 'green', got '%s'", v)
+	}
+	if _, err := g.GetErr("HOSTS"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected deleted key to be ErrKeyNotSet, got %v", err)
+	}
+
+	g.Stop()
+	time.Sleep(20 * time.Millisecond)
+	if v, err := g.GetErr("MODE"); v != "green" || err != nil {
+		t.Errorf("Expected the cached value after Stop, got '%s' (%v)", v, err)
+	}
+}
+
+func TestNatsKVGetterWatchEnded(t *testing.T) {
+	updates := make(chan nats.KeyValueEntry, 10)
+	updates <- &fakeEntry{key: "MODE", val: "blue", op: nats.KeyValuePut}
+	updates <- &fakeEntry{key: "HOSTS", val: "a", op: nats.KeyValuePut}
+	updates <- nil
+	g, err := NewNatsKVGetter(&fakeKV{watcher: &fakeWatcher{updates: updates}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := g.Keys(); !reflect.DeepEqual(v, []string{"HOSTS", "MODE"}) {
+		t.Errorf("Expected sorted keys [HOSTS MODE], got %q", v)
+	}
+	// The connection closes, ending the watch without a Stop.
+	close(updates)
+	select {
+	case err := <-g.Errors():
+		if err == nil || !strings.Contains(err.Error(), "watch of") {
+			t.Errorf("Expected the end of the watch to be reported, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the end of the watch to be reported")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := g.GetErr("MODE"); errors.Is(err, nats.ErrConnectionClosed) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected connection errors once the watch ends")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
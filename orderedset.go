@@ -0,0 +1,39 @@
+package config
+
+// OrderedSet is a set of strings that remembers insertion order.
+type OrderedSet struct {
+	order   []string
+	members map[string]bool
+}
+
+// NewOrderedSet : Return an OrderedSet holding vals, in order, without repeats.
+func NewOrderedSet(vals ...string) *OrderedSet {
+	s := &OrderedSet{order: make([]string, 0, len(vals)), members: make(map[string]bool, len(vals))}
+	for _, val := range vals {
+		s.Add(val)
+	}
+	return s
+}
+
+// Add : Append val to the set, if it isn't already a member.
+func (s *OrderedSet) Add(val string) {
+	if !s.members[val] {
+		s.members[val] = true
+		s.order = append(s.order, val)
+	}
+}
+
+// Contains : Report whether val is in the set (case-sensitive).
+func (s *OrderedSet) Contains(val string) bool {
+	return s.members[val]
+}
+
+// Len : The number of members in the set.
+func (s *OrderedSet) Len() int {
+	return len(s.order)
+}
+
+// Slice : Return the members of the set, in insertion order.
+func (s *OrderedSet) Slice() []string {
+	return append([]string(nil), s.order...)
+}
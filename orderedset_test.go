@@ -0,0 +1,19 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrderedSet(t *testing.T) {
+	s := NewOrderedSet("c", "a", "c")
+	s.Add("b")
+	s.Add("a")
+	if got := s.Slice(); !reflect.DeepEqual(got, []string{"c", "a", "b"}) {
+		t.Errorf("Expected [c a b], got %q", got)
+	}
+	s.Slice()[0] = "mutated"
+	if !s.Contains("c") || s.Contains("mutated") {
+		t.Error("Slice() should return a copy")
+	}
+}
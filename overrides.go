@@ -0,0 +1,141 @@
+package config
+
+import (
+	"sync"
+	"time"
+)
+
+// TemporaryOverrides layers time-boxed overrides over a base Getter. See NewTemporaryOverrides.
+type TemporaryOverrides struct {
+	Notifier
+	base      Getter
+	lock      sync.RWMutex
+	overrides map[string]temporaryOverride
+	now       func() time.Time
+}
+
+type temporaryOverride struct {
+	val     string
+	expires time.Time
+	timer   *time.Timer
+}
+
+// NewTemporaryOverrides : Return a Getter where a value set with SetFor overrides base's for a while, then reverts
+// by itself, for operational changes like "debug-log this service for the next 10 minutes". Unlike Cached(), which
+// remembers base's values, it's the override that expires here.
+//
+// Expiry is checked on every read, so an override never outlives its duration; a timer per override also drops it
+// when it expires, and notifies Watch() channels then, so watchers see the revert without polling. SetFor and Clear
+// notify too. It's safe for concurrent use.
+func NewTemporaryOverrides(base Getter) *TemporaryOverrides {
+	return &TemporaryOverrides{base: base, overrides: make(map[string]temporaryOverride), now: time.Now}
+}
+
+// SetFor : Override the value of key with val for d, replacing any override key already has.
+func (t *TemporaryOverrides) SetFor(key string, val string, d time.Duration) {
+	t.lock.Lock()
+	if old, ok := t.overrides[key]; ok {
+		old.timer.Stop()
+	}
+	o := temporaryOverride{val: val, expires: t.now().Add(d)}
+	o.timer = time.AfterFunc(d, func() { t.expire(key, o.expires) })
+	t.overrides[key] = o
+	t.lock.Unlock()
+	t.Notify()
+}
+
+// Clear : Drop the override for key before it expires.
+func (t *TemporaryOverrides) Clear(key string) {
+	t.lock.Lock()
+	o, ok := t.overrides[key]
+	if ok {
+		o.timer.Stop()
+		delete(t.overrides, key)
+	}
+	t.lock.Unlock()
+	if ok {
+		t.Notify()
+	}
+}
+
+// expire drops key's override if it's still the one that expires at expires.
+func (t *TemporaryOverrides) expire(key string, expires time.Time) {
+	t.lock.Lock()
+	o, ok := t.overrides[key]
+	ok = ok && o.expires.Equal(expires)
+	if ok {
+		delete(t.overrides, key)
+	}
+	t.lock.Unlock()
+	if ok {
+		t.Notify()
+	}
+}
+
+// override returns key's override, if it has one that hasn't expired.
+func (t *TemporaryOverrides) override(key string) (string, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	o, ok := t.overrides[key]
+	if !ok || !t.now().Before(o.expires) {
+		return "", false
+	}
+	return o.val, true
+}
+
+// Expires : Return when key's override expires, and whether it has one.
+func (t *TemporaryOverrides) Expires(key string) (time.Time, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	o, ok := t.overrides[key]
+	if !ok || !t.now().Before(o.expires) {
+		return time.Time{}, false
+	}
+	return o.expires, true
+}
+
+// Lookup : Return the override for key if there's one in effect, otherwise the base Getter's value.
+func (t *TemporaryOverrides) Lookup(key string) (string, bool) {
+	if val, ok := t.override(key); ok {
+		return val, true
+	}
+	return Lookup(t.base, key)
+}
+
+// Keys : Return the sorted union of the overridden keys and the base Getter's keys, if it's a Lister.
+func (t *TemporaryOverrides) Keys() []string {
+	t.lock.RLock()
+	now := t.now()
+	overrides := make(Map, len(t.overrides))
+	for key, o := range t.overrides {
+		if now.Before(o.expires) {
+			overrides[key] = o.val
+		}
+	}
+	t.lock.RUnlock()
+	return unionKeys(overrides, t.base)
+}
+
+// Get : Return the override for key if there's one in effect, otherwise the base Getter's value.
+func (t *TemporaryOverrides) Get(key string) string {
+	val, _ := t.Lookup(key)
+	return val
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (t *TemporaryOverrides) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(t, key, dflt)
+}
+
+// GetStrings : Split the override for key like Env.GetStrings, or return the base Getter's GetStrings.
+func (t *TemporaryOverrides) GetStrings(key string) []string {
+	if val, ok := t.override(key); ok {
+		return SplitStrings(val)
+	}
+	return t.base.GetStrings(key)
+}
+
+// MustGet will panic if the key is not present or empty.
+func (t *TemporaryOverrides) MustGet(key string) string {
+	return MustGet(t, key)
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTemporaryOverrides(t *testing.T) {
+	now := time.Now()
+	o := NewTemporaryOverrides(Map{"LOG_LEVEL": "info", "HOST": "db1"})
+	o.now = func() time.Time { return now }
+	o.SetFor("LOG_LEVEL", "debug", 10*time.Minute)
+	o.SetFor("EXTRA", "a, b", time.Minute)
+	if v := o.Get("LOG_LEVEL"); v != "debug" {
+		t.Errorf("Expected the override 'debug', got '%s'", v)
+	}
+	if v := o.GetStrings("EXTRA"); !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %q", v)
+	}
+	if v := o.Keys(); !reflect.DeepEqual(v, []string{"EXTRA", "HOST", "LOG_LEVEL"}) {
+		t.Errorf("Unexpected keys %q", v)
+	}
+	if at, ok := o.Expires("LOG_LEVEL"); !ok || !at.Equal(now.Add(10*time.Minute)) {
+		t.Errorf("Unexpected expiry %v", at)
+	}
+
+	now = now.Add(5 * time.Minute)
+	if v := o.Get("LOG_LEVEL"); v != "debug" {
+		t.Errorf("Expected the override to still apply, got '%s'", v)
+	}
+	if _, ok := o.Lookup("EXTRA"); ok {
+		t.Error("Expected the expired override to fall through to the unset base value")
+	}
+	o.Clear("LOG_LEVEL")
+	if v := o.Get("LOG_LEVEL"); v != "info" {
+		t.Errorf("Expected the base value after Clear, got '%s'", v)
+	}
+}
+
+func TestTemporaryOverridesTimer(t *testing.T) {
+	o := NewTemporaryOverrides(Map{"LOG_LEVEL": "info"})
+	changes := o.Watch()
+	o.SetFor("LOG_LEVEL", "debug", 20*time.Millisecond)
+	<-changes
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a notification when the override expired")
+	}
+	if v := o.Get("LOG_LEVEL"); v != "info" {
+		t.Errorf("Expected the override to revert, got '%s'", v)
+	}
+	if _, ok := o.Expires("LOG_LEVEL"); ok {
+		t.Error("Expected no expiry after the revert")
+	}
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseOptions controls how ParseStringsDetailed splits a value.
+type ParseOptions struct {
+	// Delimiter separates elements. Defaults to ',' if zero.
+	Delimiter rune
+	// Quote, if non-zero, wraps an element that may contain the delimiter. Whitespace outside the quotes
+	// is ignored; anything else outside the quotes is an error.
+	Quote rune
+	// Escape, if non-zero, makes the following character literal. If Escape is the same as Quote,
+	// a doubled quote inside a quoted element is a literal quote (CSV-style).
+	Escape rune
+	// TrimSpace strips whitespace from around unquoted elements.
+	TrimSpace bool
+}
+
+// ParseError describes a malformed value passed to ParseStringsDetailed.
+type ParseError struct {
+	// Offset is the byte offset of the problem in the raw value.
+	Offset int
+	// Snippet is the part of the raw value around Offset.
+	Snippet string
+	Msg     string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s at offset %d: %q", e.Msg, e.Offset, e.Snippet)
+}
+
+// ParseStringsDetailed : Split raw into elements according to opts. On malformed input the error is a
+// *ParseError with the byte offset of the problem and a snippet of the surrounding text, for tooling
+// that wants to point at the bad spot.
+//
+// The list accessors don't use it, since their values have no quoting or escaping to get wrong. With the default
+// delimiter and TrimSpace (and no Quote or Escape) it can't fail, and splits like GetStrings (see SplitStrings).
+func ParseStringsDetailed(raw string, opts ParseOptions) ([]string, error) {
+	delim := opts.Delimiter
+	if delim == 0 {
+		delim = ','
+	}
+	rval := make([]string, 0)
+	var b strings.Builder
+	quoted, inQuote, afterQuote, quoteStart := false, false, false, 0
+	finish := func() {
+		v := b.String()
+		if opts.TrimSpace && !quoted {
+			v = strings.TrimSpace(v)
+		}
+		rval = append(rval, v)
+		b.Reset()
+		quoted, afterQuote = false, false
+	}
+	// Elements are built from slices of raw rather than from decoded runes, so bytes that aren't valid UTF-8 pass
+	// through unchanged instead of becoming U+FFFD.
+	for i := 0; i < len(raw); {
+		r, size := utf8.DecodeRuneInString(raw[i:])
+		next, nextSize := utf8.DecodeRuneInString(raw[i+size:])
+		switch {
+		case inQuote && r == opts.Quote:
+			if opts.Escape == opts.Quote && next == opts.Quote {
+				b.WriteString(raw[i+size : i+size+nextSize])
+				size += nextSize
+			} else {
+				inQuote, afterQuote = false, true
+			}
+		case opts.Escape != 0 && r == opts.Escape && opts.Escape != opts.Quote:
+			if i+size >= len(raw) {
+				return nil, newParseError(raw, i, "escape at end of input")
+			}
+			if afterQuote {
+				return nil, newParseError(raw, i, "unexpected character after closing quote")
+			}
+			b.WriteString(raw[i+size : i+size+nextSize])
+			size += nextSize
+		case inQuote:
+			b.WriteString(raw[i : i+size])
+		case r == delim:
+			finish()
+		case opts.Quote != 0 && r == opts.Quote:
+			if afterQuote || strings.TrimSpace(b.String()) != "" {
+				return nil, newParseError(raw, i, "unexpected quote")
+			}
+			b.Reset()
+			quoted, inQuote, quoteStart = true, true, i
+		case afterQuote:
+			if !unicode.IsSpace(r) {
+				return nil, newParseError(raw, i, "unexpected character after closing quote")
+			}
+		default:
+			b.WriteString(raw[i : i+size])
+		}
+		i += size
+	}
+	if inQuote {
+		return nil, newParseError(raw, quoteStart, "unterminated quote")
+	}
+	finish()
+	return rval, nil
+}
+
+const snippetRadius = 10
+
+func newParseError(raw string, offset int, msg string) *ParseError {
+	start, end := offset-snippetRadius, offset+snippetRadius
+	if start < 0 {
+		start = 0
+	}
+	if end > len(raw) {
+		end = len(raw)
+	}
+	return &ParseError{Offset: offset, Snippet: strings.ToValidUTF8(raw[start:end], ""), Msg: msg}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseStringsDetailed(t *testing.T) {
+	csv := ParseOptions{Quote: '"', Escape: '"', TrimSpace: true}
+	tests := []struct {
+		raw      string
+		opts     ParseOptions
+		expected []string
+	}{
+		{" a, b ,,c", ParseOptions{TrimSpace: true}, []string{"a", "b", "", "c"}},
+		{" a; b", ParseOptions{Delimiter: ';'}, []string{" a", " b"}},
+		{`a, "b, c" ,"say ""hi"""`, csv, []string{"a", "b, c", `say "hi"`}},
+		{`a\,b,c`, ParseOptions{Escape: '\\'}, []string{"a,b", "c"}},
+		{`"x\"y",z`, ParseOptions{Quote: '"', Escape: '\\'}, []string{`x"y`, "z"}},
+		{"", ParseOptions{TrimSpace: true}, []string{""}},
+		{"a\xffb, \xfe", ParseOptions{TrimSpace: true}, []string{"a\xffb", "\xfe"}},
+		{"\"\xff,\"\"\",\\\xfe", csv, []string{"\xff,\"", "\\\xfe"}},
+	}
+	for _, test := range tests {
+		got, err := ParseStringsDetailed(test.raw, test.opts)
+		if err != nil {
+			t.Errorf("Unexpected error parsing %q: %v", test.raw, err)
+		} else if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Parsing %q: expected %q, got %q", test.raw, test.expected, got)
+		}
+	}
+}
+
+func TestParseStringsDetailedErrors(t *testing.T) {
+	opts := ParseOptions{Quote: '"', Escape: '\\', TrimSpace: true}
+	tests := []struct {
+		raw    string
+		offset int
+	}{
+		{`a, "unterminated`, 3},
+		{`a, "b"c`, 6},
+		{`a, b"c"`, 4},
+		{`a, b\`, 4},
+	}
+	for _, test := range tests {
+		_, err := ParseStringsDetailed(test.raw, opts)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("Parsing %q: expected a *ParseError, got %v", test.raw, err)
+		} else if perr.Offset != test.offset {
+			t.Errorf("Parsing %q: expected offset %d, got %d (%v)", test.raw, test.offset, perr.Offset, err)
+		}
+	}
+}
+
+func TestParseStringsDetailedLikeSplitStrings(t *testing.T) {
+	for _, raw := range []string{"", "a", " a , b ,,c ", "a,\tb\n", "\"a,b\"", "a\\,b"} {
+		got, err := ParseStringsDetailed(raw, ParseOptions{TrimSpace: true})
+		if err != nil || !reflect.DeepEqual(got, SplitStrings(raw)) {
+			t.Errorf("%q: expected %q, got %q (%v)", raw, SplitStrings(raw), got, err)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+// Package pgconfig provides a config.Getter over a PostgreSQL table, kept current with LISTEN/NOTIFY, for
+// Postgres-centric apps that want DB-backed config with push updates. It's a separate package to keep the pgx
+// dependency optional.
+package pgconfig
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/efixler/config"
+)
+
+const (
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+	// queryTimeout bounds each query of the table.
+	queryTimeout = 10 * time.Second
+)
+
+// Getter serves the rows of a config table from memory. It is a config.ErrorGetter, a config.Lister and a
+// config.Watcher.
+type Getter struct {
+	config.Notifier
+	config.ErrorReporter
+	load       func(ctx context.Context, key string) (map[string]string, error)
+	listen     func(ctx context.Context, ready func() error, notify func(payload string)) error
+	lock       sync.RWMutex
+	values     map[string]string
+	err        error
+	cancel     context.CancelFunc
+	closeOnce  sync.Once
+	done       chan struct{}
+	minBackoff time.Duration
+}
+
+// NewPostgresGetter : Load table into memory and return a Getter over it that follows changes pushed with NOTIFY.
+// db must use the pgx driver (import github.com/jackc/pgx/v5/stdlib and open it as "pgx"), because listening needs
+// the underlying connection; a listening connection is held out of the pool for the Getter's lifetime.
+//
+// The table (which may be schema-qualified) has to have text "key" and "value" columns, with key unique:
+//
+//	CREATE TABLE app_config (key text PRIMARY KEY, value text NOT NULL);
+//
+// Changes are announced with NOTIFY on a channel named after the table (without any schema), with the changed key
+// as the payload; an empty payload means reload the whole table. A trigger does it for every write:
+//
+//	CREATE FUNCTION app_config_notify() RETURNS trigger AS $$
+//	BEGIN
+//		PERFORM pg_notify(TG_TABLE_NAME, CASE WHEN TG_OP = 'DELETE' THEN OLD.key ELSE NEW.key END);
+//		RETURN NULL;
+//	END $$ LANGUAGE plpgsql;
+//	CREATE TRIGGER app_config_notify AFTER INSERT OR UPDATE OR DELETE ON app_config
+//		FOR EACH ROW EXECUTE FUNCTION app_config_notify();
+//
+// (A key that's renamed by an UPDATE should notify with an empty payload, or both keys.) Each notification re-reads
+// the key's row, and Watch() channels are notified after each one.
+//
+// A failure of the initial load is returned. After that, when the listening connection fails, or a re-read does,
+// the error goes to the Errors() channel, and GetErr returns it while the values might be stale; Get keeps serving
+// the last values it has. The connection is re-established with exponential backoff (1s up to a minute), and the
+// whole table reloaded, since notifications sent in the meantime are lost. Call Close to stop listening.
+func NewPostgresGetter(db *sql.DB, table string) (*Getter, error) {
+	parts := strings.Split(table, ".")
+	ident := pgx.Identifier(parts).Sanitize()
+	channel := pgx.Identifier{parts[len(parts)-1]}.Sanitize()
+	load := func(ctx context.Context, key string) (map[string]string, error) {
+		query := "SELECT key, value FROM " + ident
+		var args []any
+		if key != "" {
+			query, args = query+" WHERE key = $1", []any{key}
+		}
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		rval := make(map[string]string)
+		for rows.Next() {
+			var k, v string
+			if err := rows.Scan(&k, &v); err != nil {
+				return nil, err
+			}
+			rval[k] = v
+		}
+		return rval, rows.Err()
+	}
+	listen := func(ctx context.Context, ready func() error, notify func(string)) error {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		return conn.Raw(func(dc any) error {
+			sc, ok := dc.(*stdlib.Conn)
+			if !ok {
+				return errors.New("db must use the pgx driver (github.com/jackc/pgx/v5/stdlib)")
+			}
+			pc := sc.Conn()
+			if _, err := pc.Exec(ctx, "LISTEN "+channel); err != nil {
+				return err
+			}
+			if err := ready(); err != nil {
+				return err
+			}
+			for {
+				n, err := pc.WaitForNotification(ctx)
+				if err != nil {
+					// Don't hand a connection that's still listening back to the pool.
+					return errors.Join(err, driver.ErrBadConn)
+				}
+				notify(n.Payload)
+			}
+		})
+	}
+	return newPostgresGetter(load, listen, minBackoff)
+}
+
+func newPostgresGetter(load func(context.Context, string) (map[string]string, error), listen func(context.Context, func() error, func(string)) error, backoff time.Duration) (*Getter, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &Getter{
+		load:       load,
+		listen:     listen,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+		minBackoff: backoff,
+	}
+	if err := g.reload(ctx, ""); err != nil {
+		cancel()
+		return nil, err
+	}
+	go g.run(ctx)
+	return g, nil
+}
+
+func (g *Getter) run(ctx context.Context) {
+	defer close(g.done)
+	backoff, failures := g.minBackoff, 0
+	for {
+		err := g.listen(ctx, func() error {
+			// Listening again: catch up on anything missed, then carry on as healthy.
+			if err := g.reload(ctx, ""); err != nil {
+				return err
+			}
+			backoff, failures = g.minBackoff, 0
+			g.Notify()
+			return nil
+		}, func(payload string) {
+			if err := g.reload(ctx, payload); err != nil {
+				g.fail(err)
+				return
+			}
+			g.Notify()
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		failures++
+		g.fail(fmt.Errorf("pgconfig: listening: %w (%d consecutive failures)", err, failures))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// reload re-reads key's row, or the whole table if key is "", and clears any error on success.
+func (g *Getter) reload(ctx context.Context, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	rows, err := g.load(ctx, key)
+	if err != nil {
+		return fmt.Errorf("pgconfig: loading config: %w", err)
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	switch val, ok := rows[key]; {
+	case key == "":
+		g.values = rows
+	case ok:
+		g.values[key] = val
+	default:
+		delete(g.values, key)
+	}
+	g.err = nil
+	return nil
+}
+
+// fail records err for GetErr and reports it.
+func (g *Getter) fail(err error) {
+	g.lock.Lock()
+	g.err = err
+	g.lock.Unlock()
+	g.Report(err)
+}
+
+// Close : Stop listening and release the connection. The Getter keeps serving the values it has.
+func (g *Getter) Close() error {
+	g.closeOnce.Do(func() {
+		g.cancel()
+		<-g.done
+	})
+	return nil
+}
+
+// GetErr : Return the value for key, or the latest error if the values might be stale. A key that isn't in the
+// table is an error wrapping config.ErrKeyNotSet.
+func (g *Getter) GetErr(key string) (string, error) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	if g.err != nil {
+		return "", g.err
+	}
+	val, ok := g.values[key]
+	if !ok {
+		return "", fmt.Errorf("pgconfig: %s: %w", key, config.ErrKeyNotSet)
+	}
+	return val, nil
+}
+
+// Lookup : Return the value for key, and whether it's in the table.
+func (g *Getter) Lookup(key string) (string, bool) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	val, ok := g.values[key]
+	return val, ok
+}
+
+// Get : Return the value for key, or "".
+func (g *Getter) Get(key string) string {
+	val, _ := g.Lookup(key)
+	return val
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (g *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(g, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (g *Getter) GetStrings(key string) []string {
+	return config.SplitStrings(g.Get(key))
+}
+
+// MustGet will panic if the key is not present or empty.
+func (g *Getter) MustGet(key string) string {
+	return config.MustGet(g, key)
+}
+
+// Keys : Return the keys in the table, sorted.
+func (g *Getter) Keys() []string {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return config.Map(g.values).Keys()
+}
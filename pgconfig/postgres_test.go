@@ -0,0 +1,140 @@
+package pgconfig
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/efixler/config"
+)
+
+// fakeDB stands in for the table and the listening connection.
+type fakeDB struct {
+	lock     sync.Mutex
+	rows     map[string]string
+	down     bool
+	notifies chan string
+	listens  chan struct{}
+}
+
+func (f *fakeDB) load(_ context.Context, key string) (map[string]string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.down {
+		return nil, errors.New("connection refused")
+	}
+	rval := make(map[string]string)
+	for k, v := range f.rows {
+		if key == "" || k == key {
+			rval[k] = v
+		}
+	}
+	return rval, nil
+}
+
+// listen delivers payloads until an empty one, which drops the connection.
+func (f *fakeDB) listen(ctx context.Context, ready func() error, notify func(string)) error {
+	select {
+	case f.listens <- struct{}{}:
+	default:
+	}
+	if err := ready(); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case payload := <-f.notifies:
+			if payload == "" {
+				return errors.New("connection lost")
+			}
+			notify(payload)
+		}
+	}
+}
+
+func (f *fakeDB) write(key, val string, deleted bool) {
+	f.lock.Lock()
+	if deleted {
+		delete(f.rows, key)
+	} else {
+		f.rows[key] = val
+	}
+	f.lock.Unlock()
+	f.notifies <- key
+}
+
+func waitFor(t *testing.T, ch <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out")
+	}
+}
+
+func TestPostgresGetter(t *testing.T) {
+	db := &fakeDB{rows: map[string]string{"HOSTS": "a, b", "MODE": "blue"}, notifies: make(chan string), listens: make(chan struct{}, 4)}
+	g, err := newPostgresGetter(db.load, db.listen, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	waitFor(t, db.listens)
+	if v := g.GetStrings("HOSTS"); len(v) != 2 {
+		t.Errorf("Expected [a b], got %q", v)
+	}
+
+	changes := g.Watch()
+	db.write("MODE", "green", false)
+	waitFor(t, changes)
+	if v, err := g.GetErr("MODE"); err != nil || v != "green" {
+		t.Errorf("Expected 'green', got '%s' (%v)", v, err)
+	}
+	db.write("HOSTS", "", true)
+	waitFor(t, changes)
+	if _, err := g.GetErr("HOSTS"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected a deleted row to be ErrKeyNotSet, got %v", err)
+	}
+
+	// Drop the connection, and change a row while nobody's listening.
+	db.lock.Lock()
+	db.down = true
+	db.lock.Unlock()
+	db.notifies <- ""
+	select {
+	case err := <-g.Errors():
+		if err == nil {
+			t.Error("Expected a listening error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a listening error")
+	}
+	if _, err := g.GetErr("MODE"); err == nil {
+		t.Error("Expected GetErr to report the outage")
+	}
+	if v := g.Get("MODE"); v != "green" {
+		t.Errorf("Expected the last value to be served, got '%s'", v)
+	}
+	db.lock.Lock()
+	db.down = false
+	db.rows["MODE"] = "red"
+	db.lock.Unlock()
+	deadline := time.Now().Add(2 * time.Second)
+	for g.Get("MODE") != "red" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if v, err := g.GetErr("MODE"); err != nil || v != "red" {
+		t.Errorf("Expected the table to be reloaded after reconnecting, got '%s' (%v)", v, err)
+	}
+}
+
+func TestPostgresGetterLoadError(t *testing.T) {
+	db := &fakeDB{down: true}
+	if _, err := newPostgresGetter(db.load, db.listen, time.Millisecond); err == nil {
+		t.Error("Expected the initial load error")
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+// WithPipelines : Wrap a Getter so that the value of each key in pipelines is passed through that key's transforms,
+// in order, each one getting the output of the one before, e.g. DB_HOST: trim, then lowercase, then strip the port.
+// Keys without a pipeline pass through untouched.
+//
+// Transforms run on every read, including on empty (unset) values, so a transform can supply a value. If the
+// pipeline's result is empty, the key is treated as unset: GetOrDefault returns the default and MustGet panics.
+// GetStrings splits the transformed value. Lookup errors from an ErrorGetter skip the pipeline.
+func WithPipelines(g Getter, pipelines map[string][]func(string) string) Getter {
+	copied := make(map[string][]func(string) string, len(pipelines))
+	for key, fns := range pipelines {
+		copied[key] = append([]func(string) string(nil), fns...)
+	}
+	return &pipelined{g: g, pipelines: copied}
+}
+
+type pipelined struct {
+	g         Getter
+	pipelines map[string][]func(string) string
+}
+
+func (p *pipelined) GetErr(key string) (string, error) {
+	val, err := GetErr(p.g, key)
+	if err != nil {
+		return val, err
+	}
+	for _, fn := range p.pipelines[key] {
+		val = fn(val)
+	}
+	return val, nil
+}
+
+func (p *pipelined) Get(key string) string {
+	val, _ := p.GetErr(key)
+	return val
+}
+
+func (p *pipelined) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(p, key, dflt)
+}
+
+func (p *pipelined) GetStrings(key string) []string {
+	if _, ok := p.pipelines[key]; !ok {
+		return p.g.GetStrings(key)
+	}
+	return SplitStrings(p.Get(key))
+}
+
+func (p *pipelined) MustGet(key string) string {
+	return MustGet(p, key)
+}
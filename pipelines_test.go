@@ -0,0 +1,37 @@
+package config
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithPipelines(t *testing.T) {
+	stripPort := func(s string) string {
+		if host, _, err := net.SplitHostPort(s); err == nil {
+			return host
+		}
+		return s
+	}
+	pipelines := map[string][]func(string) string{
+		"DB_HOST": {strings.TrimSpace, strings.ToLower, stripPort},
+		"REGIONS": {strings.ToUpper},
+		"BLANKED": {func(string) string { return "" }},
+	}
+	g := WithPipelines(Map{"DB_HOST": "  DB1.Example.COM:5432 ", "REGIONS": "us, eu", "BLANKED": "x", "OTHER": " As Is "}, pipelines)
+	pipelines["OTHER"] = []func(string) string{strings.TrimSpace}
+
+	if v := g.Get("DB_HOST"); v != "db1.example.com" {
+		t.Errorf("Expected 'db1.example.com', got '%s'", v)
+	}
+	if v := g.GetStrings("REGIONS"); !reflect.DeepEqual(v, []string{"US", "EU"}) {
+		t.Errorf("Expected [US EU], got %q", v)
+	}
+	if v := g.Get("OTHER"); v != " As Is " {
+		t.Errorf("Expected keys without a pipeline to pass through, got '%s'", v)
+	}
+	if v := g.GetOrDefault("BLANKED", "dflt"); v != "dflt" {
+		t.Errorf("Expected an empty pipeline result to take the default, got '%s'", v)
+	}
+}
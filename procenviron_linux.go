@@ -0,0 +1,33 @@
+//go:build linux
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+)
+
+// NewProcEnvironGetter : Return a Getter holding the environment as it was when the process was exec'd, parsed
+// once from /proc/self/environ. Later os.Setenv() and os.Unsetenv() calls are not reflected, which makes this
+// useful for debugging environment discrepancies or in sandboxes where os.Getenv() can't be trusted.
+//
+// Returns an error if /proc isn't mounted, or (from the non-Linux build) on any other OS.
+func NewProcEnvironGetter() (Getter, error) {
+	data, err := os.ReadFile("/proc/self/environ")
+	if err != nil {
+		return nil, fmt.Errorf("config: reading process environment: %w", err)
+	}
+	return parseEnviron(data), nil
+}
+
+// parseEnviron converts NUL-delimited KEY=VALUE entries into a Map.
+func parseEnviron(data []byte) Map {
+	rval := make(Map)
+	for _, entry := range bytes.Split(data, []byte{0}) {
+		if key, val, ok := bytes.Cut(entry, []byte("=")); ok && len(key) > 0 {
+			rval[string(key)] = string(val)
+		}
+	}
+	return rval
+}
@@ -0,0 +1,33 @@
+//go:build linux
+
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestProcEnvironGetter(t *testing.T) {
+	os.Setenv("CONFIG_TEST_SET_AFTER_EXEC", "1")
+	defer os.Unsetenv("CONFIG_TEST_SET_AFTER_EXEC")
+	c, err := NewProcEnvironGetter()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := c.Get("CONFIG_TEST_SET_AFTER_EXEC"); v != "" {
+		t.Errorf("Variables set after exec should not be visible, got '%s'", v)
+	}
+	if v, expected := c.Get("PATH"), os.Getenv("PATH"); v != expected {
+		t.Errorf("Expected PATH '%s', got '%s'", expected, v)
+	}
+}
+
+func TestParseEnviron(t *testing.T) {
+	m := parseEnviron([]byte("A=1\x00B=x=y\x00\x00C=\x00"))
+	if m.Get("A") != "1" || m.Get("B") != "x=y" {
+		t.Errorf("Unexpected parse result: %v", m)
+	}
+	if _, ok := m.Lookup("C"); !ok {
+		t.Error("Empty values should still be present")
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package config
+
+import (
+	"errors"
+)
+
+// NewProcEnvironGetter : /proc/self/environ is Linux-only, so this always returns an error.
+func NewProcEnvironGetter() (Getter, error) {
+	return nil, errors.New("config: /proc/self/environ is only available on linux")
+}
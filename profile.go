@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+)
+
+// NoProfile is the profile name that ProfileGetter.SelectProfile takes to deselect profiles, leaving just the base values.
+const NoProfile = "none"
+
+// ProfileGetter overlays one of a set of named value bundles on a base Getter. See NewProfileGetter.
+type ProfileGetter struct {
+	Notifier
+	base     Getter
+	profiles map[string]Map
+	lock     sync.RWMutex
+	name     string
+	active   Map
+}
+
+// NewProfileGetter : Return a Getter over base where the values of the selected profile, e.g. "low-memory" or
+// "high-throughput", override base's, so operators can switch a whole coherent bundle of settings at once.
+// No profile is selected to begin with (the profile is NoProfile). A profile can't be named NoProfile; if profiles
+// has one, it's never selected. The profiles are copied.
+//
+// The Getter is safe for concurrent use, and a Watcher, notified each time a different profile is selected.
+func NewProfileGetter(profiles map[string]map[string]string, base Getter) *ProfileGetter {
+	p := &ProfileGetter{base: base, profiles: make(map[string]Map, len(profiles)), name: NoProfile, active: Map{}}
+	for name, values := range profiles {
+		p.profiles[name] = Map(copyValues(values))
+	}
+	return p
+}
+
+// SelectProfile : Make name the active profile, or select none with NoProfile. An unknown name is an error,
+// and leaves the active profile as it was.
+func (p *ProfileGetter) SelectProfile(name string) error {
+	values, ok := p.profiles[name]
+	if name == NoProfile {
+		values, ok = Map{}, true
+	}
+	if !ok {
+		return fmt.Errorf("config: unknown profile %q", name)
+	}
+	p.lock.Lock()
+	changed := p.name != name
+	p.name, p.active = name, values
+	p.lock.Unlock()
+	if changed {
+		p.Notify()
+	}
+	return nil
+}
+
+// Profile : Return the name of the active profile, or NoProfile.
+func (p *ProfileGetter) Profile() string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.name
+}
+
+func (p *ProfileGetter) current() Map {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.active
+}
+
+// Lookup : Return the active profile's value for key, if it has one, otherwise the base Getter's.
+func (p *ProfileGetter) Lookup(key string) (string, bool) {
+	if val, ok := p.current()[key]; ok {
+		return val, true
+	}
+	return Lookup(p.base, key)
+}
+
+// Keys : Return the sorted union of the active profile's keys and the base Getter's keys, if it's a Lister.
+func (p *ProfileGetter) Keys() []string {
+	return unionKeys(p.current(), p.base)
+}
+
+// Get : Return the active profile's value for key, if it has one, otherwise the base Getter's.
+func (p *ProfileGetter) Get(key string) string {
+	val, _ := p.Lookup(key)
+	return val
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (p *ProfileGetter) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(p, key, dflt)
+}
+
+// GetStrings : Split the active profile's value for key like Env.GetStrings, or return the base Getter's GetStrings.
+func (p *ProfileGetter) GetStrings(key string) []string {
+	if val, ok := p.current()[key]; ok {
+		return SplitStrings(val)
+	}
+	return p.base.GetStrings(key)
+}
+
+// MustGet will panic if the key is not present or empty.
+func (p *ProfileGetter) MustGet(key string) string {
+	return MustGet(p, key)
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProfileGetter(t *testing.T) {
+	profiles := map[string]map[string]string{
+		"low-memory":      {"CACHE_SIZE": "16", "WORKERS": "1"},
+		"high-throughput": {"CACHE_SIZE": "4096", "BATCH": "500"},
+	}
+	p := NewProfileGetter(profiles, Map{"CACHE_SIZE": "256", "HOST": "db1"})
+	watch := p.Watch()
+	if v := p.Get("CACHE_SIZE"); v != "256" || p.Profile() != NoProfile {
+		t.Errorf("Expected the base value with no profile, got '%s'", v)
+	}
+	if err := p.SelectProfile("low-memory"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := p.Get("CACHE_SIZE"); v != "16" {
+		t.Errorf("Expected the profile value '16', got '%s'", v)
+	}
+	if keys := p.Keys(); !reflect.DeepEqual(keys, []string{"CACHE_SIZE", "HOST", "WORKERS"}) {
+		t.Errorf("Unexpected keys %q", keys)
+	}
+	select {
+	case <-watch:
+	default:
+		t.Error("Expected a notification")
+	}
+	if err := p.SelectProfile("turbo"); err == nil || p.Profile() != "low-memory" {
+		t.Errorf("Expected an error, and the profile left alone; got %v, %s", err, p.Profile())
+	}
+	p.SelectProfile(NoProfile)
+	if v := p.Get("WORKERS"); v != "" {
+		t.Errorf("Expected no profile values, got '%s'", v)
+	}
+}
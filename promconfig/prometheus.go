@@ -0,0 +1,110 @@
+// Package promconfig provides a config.Metrics implementation backed by Prometheus counters, for use with
+// config.WithMetrics. It's a separate package to keep the Prometheus client dependency optional.
+//
+// The counters, each labeled by "key", are:
+//
+//	<namespace>_config_reads_total         lookups
+//	<namespace>_config_misses_total        lookups that found no value
+//	<namespace>_config_parse_errors_total  lookups that failed (fetch, decode or parse errors)
+//
+// With an empty namespace the names start at "config_".
+package promconfig
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/efixler/config"
+)
+
+// KeyLabel is the label holding the config key (or its bucket, see WithKeyBucketer).
+const KeyLabel = "key"
+
+// Option configures the Metrics.
+type Option func(*Metrics)
+
+// WithRegisterer : Register the counters with reg instead of prometheus.DefaultRegisterer.
+func WithRegisterer(reg prometheus.Registerer) Option {
+	return func(m *Metrics) {
+		m.reg = reg
+	}
+}
+
+// WithKeyBucketer : Label the counters with bucket(key) instead of the key, to bound the label's cardinality
+// when keys are generated (per tenant, per host and so on), e.g. by mapping "tenant.1234.limit" to "tenant.*.limit".
+func WithKeyBucketer(bucket func(key string) string) Option {
+	return func(m *Metrics) {
+		m.bucket = bucket
+	}
+}
+
+// Metrics counts config lookups in Prometheus. See the package doc for the metric names.
+type Metrics struct {
+	reg    prometheus.Registerer
+	bucket func(string) string
+	reads  *prometheus.CounterVec
+	misses *prometheus.CounterVec
+	errs   *prometheus.CounterVec
+}
+
+// NewPrometheusMetrics : Return a config.Metrics that registers the counters described in the package doc. If the
+// same namespace is registered twice (e.g. by two getters), the counters are shared.
+func NewPrometheusMetrics(namespace string, opts ...Option) (config.Metrics, error) {
+	m := &Metrics{reg: prometheus.DefaultRegisterer}
+	for _, opt := range opts {
+		opt(m)
+	}
+	var err error
+	if m.reads, err = m.counter(namespace, "reads_total", "Config lookups, by key."); err != nil {
+		return nil, err
+	}
+	if m.misses, err = m.counter(namespace, "misses_total", "Config lookups that found no value, by key."); err != nil {
+		return nil, err
+	}
+	if m.errs, err = m.counter(namespace, "parse_errors_total", "Config lookups that failed, by key."); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *Metrics) counter(namespace string, name string, help string) (*prometheus.CounterVec, error) {
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "config",
+		Name:      name,
+		Help:      help,
+	}, []string{KeyLabel})
+	if err := m.reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(*prometheus.CounterVec); ok {
+				return existing, nil
+			}
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func (m *Metrics) label(key string) string {
+	if m.bucket != nil {
+		return m.bucket(key)
+	}
+	return key
+}
+
+// Read : Count a lookup.
+func (m *Metrics) Read(key string) {
+	m.reads.WithLabelValues(m.label(key)).Inc()
+}
+
+// Miss : Count a lookup that found no value.
+func (m *Metrics) Miss(key string) {
+	m.misses.WithLabelValues(m.label(key)).Inc()
+}
+
+// Error : Count a failed lookup.
+func (m *Metrics) Error(key string) {
+	m.errs.WithLabelValues(m.label(key)).Inc()
+}
@@ -0,0 +1,48 @@
+package promconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/efixler/config"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	bucket := func(key string) string {
+		if strings.HasPrefix(key, "tenant.") {
+			return "tenant.*"
+		}
+		return key
+	}
+	m, err := NewPrometheusMetrics("app", WithRegisterer(reg), WithKeyBucketer(bucket))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g := config.WithMetrics(config.Map{"HOST": "db1", "tenant.1.limit": "5"}, m)
+	g.Get("HOST")
+	g.Get("PORT")
+	g.Get("tenant.1.limit")
+	g.Get("tenant.2.limit")
+
+	expected := `
+# HELP app_config_misses_total Config lookups that found no value, by key.
+# TYPE app_config_misses_total counter
+app_config_misses_total{key="PORT"} 1
+app_config_misses_total{key="tenant.*"} 1
+# HELP app_config_reads_total Config lookups, by key.
+# TYPE app_config_reads_total counter
+app_config_reads_total{key="HOST"} 1
+app_config_reads_total{key="PORT"} 1
+app_config_reads_total{key="tenant.*"} 2
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "app_config_reads_total", "app_config_misses_total"); err != nil {
+		t.Error(err)
+	}
+	if _, err := NewPrometheusMetrics("app", WithRegisterer(reg)); err != nil {
+		t.Errorf("Expected re-registration to share the counters, got %v", err)
+	}
+}
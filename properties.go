@@ -0,0 +1,104 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parsePropertiesFormat parses a Java .properties file, following java.util.Properties.load: a key is separated
+// from its value by =, : or whitespace; lines starting with # or ! are comments; a line ending in a backslash
+// continues on the next (whose leading whitespace is dropped); and \t, \n, \r, \f and \uXXXX escapes are decoded,
+// while a backslash before any other character stands for that character.
+func parsePropertiesFormat(data []byte) (map[string]string, error) {
+	rval := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		start := lineNo
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		for continues(line) && scanner.Scan() {
+			lineNo++
+			line = line[:len(line)-1] + strings.TrimLeft(scanner.Text(), " \t\f")
+		}
+		if continues(line) {
+			line = line[:len(line)-1]
+		}
+		key, val := splitProperty(line)
+		var err error
+		if key, err = unescapeProperty(key); err == nil {
+			val, err = unescapeProperty(val)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", start, err)
+		}
+		rval[key] = val
+	}
+	return rval, scanner.Err()
+}
+
+// continues reports whether line ends in an odd number of backslashes.
+func continues(line string) bool {
+	n := len(line) - len(strings.TrimRight(line, `\`))
+	return n%2 == 1
+}
+
+// splitProperty splits line at the first unescaped separator, still escaped.
+func splitProperty(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':', ' ', '\t', '\f':
+			key, rest := line[:i], strings.TrimLeft(line[i:], " \t\f")
+			if rest != "" && (rest[0] == '=' || rest[0] == ':') && (line[i] == ' ' || line[i] == '\t' || line[i] == '\f') {
+				rest = rest[1:]
+			} else if line[i] == '=' || line[i] == ':' {
+				rest = line[i+1:]
+			}
+			return key, strings.TrimLeft(rest, " \t\f")
+		}
+	}
+	return line, ""
+}
+
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("malformed \\uXXXX escape")
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\uXXXX escape %q", s[i-1:i+5])
+			}
+			b.WriteRune(rune(r))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestParseProperties(t *testing.T) {
+	data := `# comment
+! also a comment
+db.host = db1
+db.port:5432
+greeting Hello, world
+path=c:\\temp\\app
+multi = first, \
+        second
+key\ with\ spaces = spaced
+unicode = caf\u00e9
+tabbed = a\tb
+empty
+`
+	m, err := ParseFormat("properties", []byte(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tests := map[string]string{
+		"db.host":         "db1",
+		"db.port":         "5432",
+		"greeting":        "Hello, world",
+		"path":            `c:\temp\app`,
+		"multi":           "first, second",
+		"key with spaces": "spaced",
+		"unicode":         "café",
+		"tabbed":          "a\tb",
+		"empty":           "",
+	}
+	for key, expected := range tests {
+		if v, ok := m.Lookup(key); !ok || v != expected {
+			t.Errorf("%s: expected %q, got %q (%v)", key, expected, v, ok)
+		}
+	}
+	if len(m) != len(tests) {
+		t.Errorf("Expected %d keys, got %v", len(tests), m.Keys())
+	}
+	if _, err := ParseFormat("properties", []byte("a=1\nbad = \\u12")); err == nil {
+		t.Error("Expected an error for a malformed escape")
+	}
+}
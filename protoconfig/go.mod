@@ -0,0 +1,10 @@
+module github.com/efixler/config/protoconfig
+
+go 1.23
+
+require (
+	github.com/efixler/config v0.0.0-00010101000000-000000000000
+	google.golang.org/protobuf v1.36.12
+)
+
+replace github.com/efixler/config => ../
@@ -0,0 +1,97 @@
+// Package protoconfig provides a config.Getter over a Protobuf message, for services that receive their config
+// as a typed message from a control plane, or embed it as one.
+package protoconfig
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/efixler/config"
+)
+
+// NewProtoGetter : Return a Getter over msg, where fieldMap maps each config key to a field path in the message.
+// Paths are dot-separated field names (as in the .proto), descending into nested messages, e.g.
+// "server.tls.cert_file". The values are read from msg once, here; an invalid path is an error.
+//
+// Scalars are stringified with their natural text form; enums use the value's name and bytes are standard
+// base64. Repeated scalar fields are joined with commas, so they feed GetStrings. Fields that aren't set
+// (including proto3 fields holding their zero value, which proto3 doesn't distinguish from unset) are
+// left out, so GetOrDefault and friends supply the default.
+func NewProtoGetter(msg proto.Message, fieldMap map[string]string) (config.Getter, error) {
+	rval := make(config.Map, len(fieldMap))
+	root := msg.ProtoReflect()
+	for key, path := range fieldMap {
+		val, ok, err := readPath(root, path)
+		if err != nil {
+			return nil, fmt.Errorf("protoconfig: %s: %w", key, err)
+		}
+		if ok {
+			rval[key] = val
+		}
+	}
+	return rval, nil
+}
+
+// readPath returns the stringified value at path in m, and whether it's set.
+func readPath(m protoreflect.Message, path string) (string, bool, error) {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		fd := m.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			return "", false, fmt.Errorf("%s has no field %q", m.Descriptor().FullName(), name)
+		}
+		last := i == len(names)-1
+		switch {
+		case fd.IsMap():
+			return "", false, fmt.Errorf("map field %s is not supported", fd.FullName())
+		case !last && (fd.Kind() != protoreflect.MessageKind || fd.IsList()):
+			return "", false, fmt.Errorf("%s is not a singular message field, so can't contain %s", fd.FullName(), names[i+1])
+		case !last:
+			// An unset message reads as an empty one, so the rest of the path is still validated.
+			m = m.Get(fd).Message()
+		case !m.Has(fd):
+			return "", false, validateLeaf(fd)
+		case fd.IsList():
+			if err := validateLeaf(fd); err != nil {
+				return "", false, err
+			}
+			list := m.Get(fd).List()
+			vals := make([]string, list.Len())
+			for j := range vals {
+				vals[j] = scalarString(fd, list.Get(j))
+			}
+			return strings.Join(vals, ","), true, nil
+		default:
+			if err := validateLeaf(fd); err != nil {
+				return "", false, err
+			}
+			return scalarString(fd, m.Get(fd)), true, nil
+		}
+	}
+	return "", false, fmt.Errorf("empty field path")
+}
+
+func validateLeaf(fd protoreflect.FieldDescriptor) error {
+	if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+		return fmt.Errorf("%s is a message; map a key to one of its fields instead", fd.FullName())
+	}
+	return nil
+}
+
+func scalarString(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return fmt.Sprint(v.Enum())
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	default:
+		return v.String()
+	}
+}
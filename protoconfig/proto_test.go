@@ -0,0 +1,52 @@
+package protoconfig
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+func TestProtoGetter(t *testing.T) {
+	msg := &typepb.Type{
+		Name:          "svc.Config",
+		Oneofs:        []string{"a", "b"},
+		SourceContext: &sourcecontextpb.SourceContext{FileName: "config.proto"},
+		Syntax:        typepb.Syntax_SYNTAX_EDITIONS,
+	}
+	c, err := NewProtoGetter(msg, map[string]string{
+		"NAME":    "name",
+		"ONEOFS":  "oneofs",
+		"FILE":    "source_context.file_name",
+		"SYNTAX":  "syntax",
+		"EDITION": "edition",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tests := map[string]string{
+		"NAME":   "svc.Config",
+		"FILE":   "config.proto",
+		"SYNTAX": "SYNTAX_EDITIONS",
+	}
+	for key, expected := range tests {
+		if v := c.Get(key); v != expected {
+			t.Errorf("%s: expected '%s', got '%s'", key, expected, v)
+		}
+	}
+	if v := c.GetStrings("ONEOFS"); !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("Expected repeated field as a list, got %q", v)
+	}
+	if v := c.GetOrDefault("EDITION", "2023"); v != "2023" {
+		t.Errorf("Unset fields should be absent; expected the default, got '%s'", v)
+	}
+}
+
+func TestProtoGetterBadPaths(t *testing.T) {
+	for _, path := range []string{"nope", "name.length", "fields.name", "source_context", "source_context.nope"} {
+		if _, err := NewProtoGetter(&typepb.Type{}, map[string]string{"KEY": path}); err == nil {
+			t.Errorf("%s: expected an error", path)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RefPrefix marks a value that is a reference to another key, e.g. REPLICA_HOST="@ref:DB_HOST". See WithReferences.
+const RefPrefix = "@ref:"
+
+// WithReferences : Wrap a Getter so that a value of the form "@ref:KEY" is replaced by the value of KEY. Place it
+// over a composed getter (Merge, NewLayeredMapGetter and the like) to let a key in one layer refer to a key set in
+// another: references are looked up through the wrapper itself, so they resolve with the same precedence as any
+// other lookup on the composed getter, and a referenced value that's a reference too is followed in turn.
+//
+// The whole value must be the reference; "@ref:" elsewhere in a value is left alone. A reference to a key that isn't
+// set is an error, as is a cycle (A="@ref:B", B="@ref:A") or a chain of more than 8 references; the error names the
+// chain, e.g. "A -> B -> A". The returned Getter is an ErrorGetter; resolution errors surface through GetErr, and Get
+// returns "". GetStrings splits the referenced value, and Keys are those of g.
+func WithReferences(g Getter) Getter {
+	return &referencing{g: g}
+}
+
+type referencing struct {
+	g Getter
+}
+
+// resolve returns key's value with references followed; chain holds the keys that led here.
+func (r *referencing) resolve(key string, chain []string) (string, bool, error) {
+	for _, k := range chain {
+		if k == key {
+			return "", false, fmt.Errorf("config: resolving %s: %w", strings.Join(append(chain, key), " -> "), errResolveCycle)
+		}
+	}
+	if len(chain) > maxResolveDepth {
+		return "", false, fmt.Errorf("config: resolving %s: %w", strings.Join(append(chain, key), " -> "), errResolveCycle)
+	}
+	val, err := GetErr(r.g, key)
+	if errors.Is(err, ErrKeyNotSet) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	if val == "" {
+		_, ok := Lookup(r.g, key)
+		return "", ok, nil
+	}
+	ref, ok := strings.CutPrefix(val, RefPrefix)
+	if !ok {
+		return val, true, nil
+	}
+	chain = append(chain, key)
+	rval, ok, err := r.resolve(strings.TrimSpace(ref), chain)
+	if err == nil && !ok {
+		err = fmt.Errorf("config: resolving %s: %s is not set", strings.Join(append(chain, ref), " -> "), ref)
+	}
+	return rval, ok, err
+}
+
+func (r *referencing) isRef(key string) bool {
+	return strings.HasPrefix(r.g.Get(key), RefPrefix)
+}
+
+func (r *referencing) GetErr(key string) (string, error) {
+	val, ok, err := r.resolve(key, nil)
+	if err == nil && !ok {
+		err = fmt.Errorf("config: %s: %w", key, ErrKeyNotSet)
+	}
+	return val, err
+}
+
+func (r *referencing) Lookup(key string) (string, bool) {
+	val, ok, err := r.resolve(key, nil)
+	return val, ok && err == nil
+}
+
+func (r *referencing) Get(key string) string {
+	val, _ := r.GetErr(key)
+	return val
+}
+
+func (r *referencing) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(r, key, dflt)
+}
+
+func (r *referencing) GetStrings(key string) []string {
+	if !r.isRef(key) {
+		return r.g.GetStrings(key)
+	}
+	return SplitStrings(r.Get(key))
+}
+
+func (r *referencing) MustGet(key string) string {
+	return MustGet(r, key)
+}
+
+func (r *referencing) Keys() []string {
+	return unionKeys(r.g)
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithReferences(t *testing.T) {
+	env := Map{"DB_HOST": "db2", "PRIMARY": "@ref:DB_HOST"}
+	file := Map{
+		"DB_HOST":   "db1",
+		"REPLICA":   "@ref:PRIMARY",
+		"HOSTS":     "@ref:HOST_LIST",
+		"HOST_LIST": "a, b",
+		"NOTE":      "see @ref:DB_HOST",
+		"DANGLING":  "@ref:NOWHERE",
+		"EMPTY":     "",
+		"EMPTY_REF": "@ref:EMPTY",
+		"LOOP_A":    "@ref:LOOP_B",
+		"LOOP_B":    "@ref:LOOP_A",
+		"SELF":      "@ref:SELF",
+	}
+	g := WithReferences(Merge(FirstPresent, env, file))
+	tests := map[string]string{
+		"DB_HOST": "db2",
+		"PRIMARY": "db2",
+		// REPLICA is in the file, but PRIMARY resolves through the composite, so env's DB_HOST wins.
+		"REPLICA": "db2",
+		"NOTE":    "see @ref:DB_HOST",
+	}
+	for key, expected := range tests {
+		if v, err := GetErr(g, key); err != nil || v != expected {
+			t.Errorf("%s: expected '%s', got '%s' (%v)", key, expected, v, err)
+		}
+	}
+	if v := g.GetStrings("HOSTS"); !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %q", v)
+	}
+	if v, ok := Lookup(g, "EMPTY_REF"); !ok || v != "" {
+		t.Errorf("A reference to an empty key should be set and empty, got '%s', %v", v, ok)
+	}
+	if _, err := GetErr(g, "DANGLING"); err == nil || !strings.Contains(err.Error(), "DANGLING -> NOWHERE") {
+		t.Errorf("Expected an error naming the chain, got %v", err)
+	}
+	if _, ok := Lookup(g, "DANGLING"); ok {
+		t.Error("An unresolvable reference should not be present")
+	}
+	for key, chain := range map[string]string{"LOOP_A": "LOOP_A -> LOOP_B -> LOOP_A", "SELF": "SELF -> SELF"} {
+		_, err := GetErr(g, key)
+		if !errors.Is(err, errResolveCycle) || !strings.Contains(err.Error(), chain) {
+			t.Errorf("%s: expected a cycle error naming %s, got %v", key, chain, err)
+		}
+	}
+	if _, err := GetErr(g, "UNSET"); !errors.Is(err, ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+	if keys := g.(Lister).Keys(); len(keys) != 12 {
+		t.Errorf("Expected the composite's 12 keys, got %q", keys)
+	}
+}
+
+func TestWithReferencesDepth(t *testing.T) {
+	m := Map{"K0": "end"}
+	for i := 1; i <= 12; i++ {
+		m[fmt.Sprintf("K%d", i)] = fmt.Sprintf("@ref:K%d", i-1)
+	}
+	g := WithReferences(m)
+	if v, err := GetErr(g, "K8"); err != nil || v != "end" {
+		t.Errorf("Expected a chain of 8 references to resolve, got '%s' (%v)", v, err)
+	}
+	if _, err := GetErr(g, "K9"); !errors.Is(err, errResolveCycle) {
+		t.Errorf("Expected a long chain to be an error, got %v", err)
+	}
+}
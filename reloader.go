@@ -0,0 +1,72 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrReloadRejected is returned (wrapped, along with the validation error) when a reloaded config fails validation.
+var ErrReloadRejected = errors.New("config: reload rejected")
+
+// Reloader is a SwappableGetter that only swaps in configs that pass validation. See ValidatingReloader.
+type Reloader struct {
+	*SwappableGetter
+	load     func() (Getter, error)
+	validate func(Getter) error
+	lock     sync.Mutex
+	errs     chan error
+}
+
+// ValidatingReloader : Load and validate the initial config, returning a Getter over it whose Reload method loads a
+// candidate config and swaps it in only if validate passes, so that a bad edit to a file, or a bad push to a remote,
+// doesn't break a running service. An initial config that fails to load or validate is returned as an error.
+//
+// When a reload fails, the config that was active stays active, untouched, and consumers see no change at all (not
+// even a Watch() notification). The failure is returned by Reload, and also sent to the Rejections() channel, for
+// reloads triggered from somewhere the caller doesn't see, like a file watcher. Validation failures wrap
+// ErrReloadRejected and the validation error; load failures wrap the load error. To be able to back out a reload
+// that passes validation but misbehaves once applied, track the Reloader's generations with NewGenerationalGetter.
+func ValidatingReloader(load func() (Getter, error), validate func(Getter) error) (*Reloader, error) {
+	r := &Reloader{load: load, validate: validate, errs: make(chan error, 8)}
+	g, err := r.candidate()
+	if err != nil {
+		return nil, err
+	}
+	r.SwappableGetter = NewSwappable(g)
+	return r, nil
+}
+
+// candidate loads and validates a new config.
+func (r *Reloader) candidate() (Getter, error) {
+	g, err := r.load()
+	if err != nil {
+		return nil, fmt.Errorf("config: reload failed: %w", err)
+	}
+	if err := r.validate(g); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReloadRejected, err)
+	}
+	return g, nil
+}
+
+// Reload : Load and validate a new config, and swap it in if it passes; otherwise keep the current one and return
+// (and report) why. Concurrent reloads are applied one at a time.
+func (r *Reloader) Reload() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	g, err := r.candidate()
+	if err != nil {
+		select {
+		case r.errs <- err:
+		default:
+		}
+		return err
+	}
+	r.Swap(g)
+	return nil
+}
+
+// Rejections : Failed reloads. The channel is buffered; errors are dropped when it's full.
+func (r *Reloader) Rejections() <-chan error {
+	return r.errs
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidatingReloader(t *testing.T) {
+	next := Map{"PORT": "8080"}
+	var loadErr error
+	load := func() (Getter, error) { return next, loadErr }
+	validate := func(g Getter) error {
+		if g.Get("PORT") == "" {
+			return errors.New("PORT is required")
+		}
+		return nil
+	}
+	r, err := ValidatingReloader(load, validate)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	changes := r.Watch()
+
+	next = Map{"PORT": "9090"}
+	if err := r.Reload(); err != nil || r.Get("PORT") != "9090" {
+		t.Errorf("Expected the valid config to be swapped in, got '%s' (%v)", r.Get("PORT"), err)
+	}
+	<-changes
+
+	next = Map{"HOST": "db1"}
+	err = r.Reload()
+	if !errors.Is(err, ErrReloadRejected) || err.Error() != "config: reload rejected: PORT is required" {
+		t.Errorf("Expected a rejection, got %v", err)
+	}
+	if v := r.Get("PORT"); v != "9090" {
+		t.Errorf("Expected the old config to stay active, got '%s'", v)
+	}
+	if rejected := <-r.Rejections(); !errors.Is(rejected, ErrReloadRejected) {
+		t.Errorf("Expected the rejection to be reported, got %v", rejected)
+	}
+	select {
+	case <-changes:
+		t.Error("Expected no notification for a rejected reload")
+	default:
+	}
+
+	loadErr = errors.New("file vanished")
+	if err := r.Reload(); err == nil || errors.Is(err, ErrReloadRejected) {
+		t.Errorf("Expected a load error, got %v", err)
+	}
+
+	next, loadErr = Map{}, nil
+	if _, err := ValidatingReloader(load, validate); !errors.Is(err, ErrReloadRejected) {
+		t.Errorf("Expected an invalid initial config to be an error, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Resolver computes a config value from the remainder of a value after its "prefix:".
+type Resolver func(rest string) (string, error)
+
+// maxResolveDepth bounds how many times a value can be re-resolved by WithResolvers.
+const maxResolveDepth = 8
+
+// DefaultResolvers : Return the built-in resolvers, for use with WithResolvers (add your own to the map):
+//
+//	file:  "file:///path" or "file:path" reads the named file, trimming surrounding whitespace
+//	b64:   "b64:..." decodes standard base64
+//	env:   "env:OTHER" reads the OTHER environment variable
+func DefaultResolvers() map[string]Resolver {
+	return map[string]Resolver{
+		"file": FileResolver,
+		"b64":  Base64Resolver,
+		"env":  EnvResolver,
+	}
+}
+
+// FileResolver reads the file named by rest, which may be written as a file URL path ("//" + path).
+func FileResolver(rest string) (string, error) {
+	data, err := os.ReadFile(strings.TrimPrefix(rest, "//"))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Base64Resolver decodes rest as standard, padded base64.
+func Base64Resolver(rest string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(rest)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// EnvResolver returns the value of the environment variable named by rest.
+func EnvResolver(rest string) (string, error) {
+	return os.Getenv(rest), nil
+}
+
+// WithResolvers : Wrap a Getter so that values of the form "prefix:rest", where prefix is a key in resolvers,
+// are replaced by the result of that resolver called with rest. Resolution is lazy, on every Get. Values without
+// a registered prefix (including "http://..." unless you register "http") pass through unchanged.
+//
+// A resolved value is resolved again if it also has a registered prefix, so env:A can point at a variable holding
+// "b64:...". To protect against cycles (A="env:A") this stops with an error when a value repeats or after
+// 8 rounds. The returned Getter is an ErrorGetter; resolution errors surface through GetErr, and Get returns "".
+// GetStrings resolves each element separately.
+func WithResolvers(g Getter, resolvers map[string]Resolver) Getter {
+	return &resolving{g: g, resolvers: resolvers}
+}
+
+type resolving struct {
+	g         Getter
+	resolvers map[string]Resolver
+}
+
+func (r *resolving) resolve(val string) (string, error) {
+	seen := make(map[string]bool)
+	for depth := 0; ; depth++ {
+		prefix, rest, ok := strings.Cut(val, ":")
+		resolver := r.resolvers[prefix]
+		if !ok || resolver == nil {
+			return val, nil
+		}
+		if seen[val] || depth >= maxResolveDepth {
+			return "", fmt.Errorf("config: resolving %q: %w", val, errResolveCycle)
+		}
+		seen[val] = true
+		next, err := resolver(rest)
+		if err != nil {
+			return "", fmt.Errorf("config: resolving %s value: %w", prefix+":", err)
+		}
+		val = next
+	}
+}
+
+var errResolveCycle = errors.New("resolution cycle or too many levels of indirection")
+
+func (r *resolving) GetErr(key string) (string, error) {
+	val, err := GetErr(r.g, key)
+	if err != nil {
+		return "", err
+	}
+	return r.resolve(val)
+}
+
+func (r *resolving) Get(key string) string {
+	val, _ := r.GetErr(key)
+	return val
+}
+
+func (r *resolving) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(r, key, dflt)
+}
+
+func (r *resolving) GetStrings(key string) []string {
+	vals := r.g.GetStrings(key)
+	rval := make([]string, len(vals))
+	for i, val := range vals {
+		rval[i], _ = r.resolve(val)
+	}
+	return rval
+}
+
+func (r *resolving) MustGet(key string) string {
+	return MustGet(r, key)
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWithResolvers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	os.WriteFile(path, []byte("file-token\n"), 0600)
+	os.Setenv("CONFIG_TEST_INDIRECT", "b64:aGVsbG8=")
+	os.Setenv("CONFIG_TEST_LOOP", "env:CONFIG_TEST_LOOP")
+	defer func() {
+		os.Unsetenv("CONFIG_TEST_INDIRECT")
+		os.Unsetenv("CONFIG_TEST_LOOP")
+	}()
+	c := WithResolvers(Map{
+		"FILE":  "file://" + path,
+		"B64":   "b64:aGVsbG8=",
+		"ENV":   "env:CONFIG_TEST_INDIRECT",
+		"URL":   "https://example.com",
+		"BAD":   "b64:!!!",
+		"CYCLE": "env:CONFIG_TEST_LOOP",
+	}, DefaultResolvers())
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"FILE", "file-token"},
+		{"B64", "hello"},
+		{"ENV", "hello"},
+		{"URL", "https://example.com"},
+	}
+	for _, test := range tests {
+		if v, err := GetErr(c, test.key); err != nil || v != test.expected {
+			t.Errorf("Key %s: expected '%s', got '%s' (%v)", test.key, test.expected, v, err)
+		}
+	}
+	for _, key := range []string{"BAD", "CYCLE"} {
+		if v, err := GetErr(c, key); err == nil {
+			t.Errorf("Key %s: expected an error, got '%s'", key, v)
+		}
+		if v := c.Get(key); v != "" {
+			t.Errorf("Key %s: expected empty Get on error, got '%s'", key, v)
+		}
+	}
+}
+
+func TestWithResolversDoesNotMutate(t *testing.T) {
+	vals := []string{"b64:aGVsbG8="}
+	c := WithResolvers(sharedList{Map: Map{}, vals: vals}, DefaultResolvers())
+	if v := c.GetStrings("KEYS"); !reflect.DeepEqual(v, []string{"hello"}) {
+		t.Errorf("Expected the decoded value, got %q", v)
+	}
+	if !reflect.DeepEqual(vals, []string{"b64:aGVsbG8="}) {
+		t.Errorf("The backend's slice should be left alone, got %q", vals)
+	}
+}
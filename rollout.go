@@ -0,0 +1,38 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// rolloutBuckets is the resolution of EvalRollout: percentages are honored to two decimal places.
+const rolloutBuckets = 10000
+
+// EvalRollout : Decide whether identity (a user or tenant ID, say) is in a gradual rollout whose percentage is the
+// value of flagKey, e.g. NEW_CHECKOUT="25%" (the % sign is optional, and fractions like "0.5%" work to two decimal
+// places). An unset flag is 0%; a value that isn't a percentage from 0 to 100 is an error.
+//
+// The answer is sticky: identity is hashed with SHA-256 over salt + ":" + identity, and the first 8 bytes of the
+// digest, big-endian, modulo 10000 place it in one of 10000 buckets; it's in the rollout if its bucket is below the
+// percentage (in hundredths). Nothing in that depends on the process, the platform or the Go version, so every
+// instance gives the same answer for the same identity, and raising the percentage only adds identities, while
+// lowering it only removes them. The salt is the value of flagKey+"_SALT", or flagKey itself if that's unset, so
+// different flags pick different identities at the same percentage; change the salt to reshuffle who's in.
+func EvalRollout(g Getter, flagKey string, identity string) (bool, error) {
+	raw := strings.TrimSpace(g.Get(flagKey))
+	if raw == "" {
+		return false, nil
+	}
+	pct, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(raw, "%")), 64)
+	if err != nil || math.IsNaN(pct) || pct < 0 || pct > 100 {
+		return false, fmt.Errorf("config: %s value %q is not a rollout percentage", flagKey, raw)
+	}
+	salt := g.GetOrDefault(flagKey+"_SALT", flagKey)
+	sum := sha256.Sum256([]byte(salt + ":" + identity))
+	bucket := binary.BigEndian.Uint64(sum[:8]) % rolloutBuckets
+	return float64(bucket) < math.Round(pct*rolloutBuckets/100), nil
+}
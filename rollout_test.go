@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEvalRollout(t *testing.T) {
+	count := func(g Getter, flag string) (n int) {
+		for i := 0; i < 10000; i++ {
+			if in, err := EvalRollout(g, flag, fmt.Sprintf("user-%d", i)); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			} else if in {
+				n++
+			}
+		}
+		return n
+	}
+	g := Map{"F25": "25%", "F50": "50", "ALL": "100%", "NONE": "0%", "F25_SALT": "v2"}
+	if n := count(g, "F25"); n < 2300 || n > 2700 {
+		t.Errorf("Expected about 2500 identities in a 25%% rollout, got %d", n)
+	}
+	if n := count(g, "ALL"); n != 10000 {
+		t.Errorf("Expected everyone in a 100%% rollout, got %d", n)
+	}
+	if n := count(g, "NONE") + count(g, "UNSET"); n != 0 {
+		t.Errorf("Expected nobody in a 0%% or unset rollout, got %d", n)
+	}
+	for i := 0; i < 1000; i++ {
+		id := fmt.Sprintf("user-%d", i)
+		a, _ := EvalRollout(g, "F25", id)
+		b, _ := EvalRollout(Map{"F25": "50%", "F25_SALT": "v2"}, "F25", id)
+		if a && !b {
+			t.Fatalf("Expected raising the percentage to keep %s in", id)
+		}
+	}
+	// The scheme is fixed, so these answers never change: the buckets are alice 5462, bob 1877, carol 923, dave 3694.
+	for id, expected := range map[string]bool{"alice": false, "bob": true, "carol": true, "dave": true} {
+		if in, _ := EvalRollout(Map{"F": "37%"}, "F", id); in != expected {
+			t.Errorf("Expected %v for %s at 37%%", expected, id)
+		}
+	}
+	if in, _ := EvalRollout(Map{"F": "36.94%"}, "F", "dave"); in {
+		t.Error("Expected dave's bucket to be just outside 36.94%")
+	}
+	for _, bad := range []string{"abc", "101%", "-1"} {
+		if _, err := EvalRollout(Map{"F": bad}, "F", "alice"); err == nil {
+			t.Errorf("Expected an error for '%s'", bad)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rule checks an invariant that spans config keys, returning an error describing any violation.
+type Rule func(Getter) error
+
+// ValidateRules : Check g against all of rules, returning every violation joined into one error (see errors.Join), or nil.
+// Use it at startup to fail fast, with the whole list of problems, on cross-field invariants that per-key
+// validation can't express, like "if TLS_ENABLED then TLS_CERT and TLS_KEY must be set".
+func ValidateRules(g Getter, rules ...Rule) error {
+	errs := make([]error, 0)
+	for _, rule := range rules {
+		if err := rule(g); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RequireIf : A Rule that requires each of thenKeys to be set (non-empty) when conditionKey's value is conditionVal.
+func RequireIf(conditionKey string, conditionVal string, thenKeys ...string) Rule {
+	return func(g Getter) error {
+		if g.Get(conditionKey) != conditionVal {
+			return nil
+		}
+		missing := make([]string, 0)
+		for _, key := range thenKeys {
+			if g.Get(key) == "" {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("config: %s=%s requires %s to be set", conditionKey, conditionVal, strings.Join(missing, ", "))
+		}
+		return nil
+	}
+}
+
+// IntOrdering : A Rule that requires minKey's value to be less than or equal to maxKey's, compared as integers.
+// It passes if either key is unset, and fails if either value isn't an integer.
+func IntOrdering(minKey string, maxKey string) Rule {
+	return func(g Getter) error {
+		minVal, maxVal := g.Get(minKey), g.Get(maxKey)
+		if minVal == "" || maxVal == "" {
+			return nil
+		}
+		lo, err := strconv.Atoi(minVal)
+		if err != nil {
+			return fmt.Errorf("config: %s is not an integer: %w", minKey, err)
+		}
+		hi, err := strconv.Atoi(maxVal)
+		if err != nil {
+			return fmt.Errorf("config: %s is not an integer: %w", maxKey, err)
+		}
+		if lo > hi {
+			return fmt.Errorf("config: %s (%d) must not be greater than %s (%d)", minKey, lo, maxKey, hi)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateRules(t *testing.T) {
+	rules := []Rule{
+		RequireIf("TLS_ENABLED", "true", "TLS_CERT", "TLS_KEY"),
+		IntOrdering("POOL_MIN", "POOL_MAX"),
+	}
+	good := Map{"TLS_ENABLED": "true", "TLS_CERT": "c", "TLS_KEY": "k", "POOL_MIN": "2", "POOL_MAX": "10"}
+	if err := ValidateRules(good, rules...); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := ValidateRules(Map{"TLS_ENABLED": "false", "POOL_MIN": "2"}, rules...); err != nil {
+		t.Errorf("Rules should pass when conditions don't apply: %v", err)
+	}
+	err := ValidateRules(Map{"TLS_ENABLED": "true", "TLS_CERT": "c", "POOL_MIN": "20", "POOL_MAX": "10"}, rules...)
+	if err == nil {
+		t.Fatal("Expected violations")
+	}
+	for _, msg := range []string{"requires TLS_KEY", "POOL_MIN (20) must not be greater than POOL_MAX (10)"} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("Expected the error to include %q, got %v", msg, err)
+		}
+	}
+	if err := ValidateRules(Map{"POOL_MIN": "x", "POOL_MAX": "1"}, rules...); err == nil {
+		t.Error("Expected an error for a non-integer value")
+	}
+}
@@ -0,0 +1,266 @@
+// Package s3config provides a config.Getter over a config file stored as an S3 object, the object-storage analog of
+// reading a config file from disk. It keeps the AWS SDK dependency out of the root package.
+package s3config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/efixler/config"
+)
+
+// DefaultRefreshInterval is how often the object is checked for changes, unless overridden with WithRefreshInterval.
+const DefaultRefreshInterval = time.Minute
+
+// requestTimeout bounds each GetObject call.
+const requestTimeout = 30 * time.Second
+
+// maxObjectSize bounds the size of the config object; a larger one is an error rather than read into memory.
+const maxObjectSize = 32 << 20
+
+// Client is the part of the S3 API the Getter uses. *s3.Client implements it.
+type Client interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+}
+
+// Option configures a Getter.
+type Option func(*Getter)
+
+// WithVersionID : Read the given version of the object rather than the latest, for reproducible deployments.
+// Object versions are immutable, so a Getter pinned to one is read once and never refreshed.
+func WithVersionID(versionID string) Option {
+	return func(g *Getter) {
+		g.versionID = versionID
+	}
+}
+
+// WithRefreshInterval : Check the object for changes every interval instead of every DefaultRefreshInterval.
+// NewS3Getter returns an error if interval isn't positive (unless a version is pinned, since it isn't checked).
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(g *Getter) {
+		g.interval = interval
+	}
+}
+
+type object struct {
+	values       config.Map
+	etag         string
+	versionID    string
+	lastModified time.Time
+}
+
+// Getter holds the values of the latest read of the object. See NewS3Getter.
+type Getter struct {
+	config.Notifier
+	config.ErrorReporter
+	client    Client
+	bucket    string
+	key       string
+	format    string
+	versionID string
+	interval  time.Duration
+	current   atomic.Pointer[object]
+	err       atomic.Pointer[error]
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewS3Getter : Return a Getter over the object at key in bucket, parsed as format (see config.ParseFormat).
+// The object is read before NewS3Getter returns, and a failure (access denied, no such bucket or key, a parse error)
+// is returned. The SDK's error is wrapped, so errors.As with *types.NoSuchKey and the like works on it.
+//
+// Unless the Getter is pinned to a version with WithVersionID, the object is checked again every
+// DefaultRefreshInterval. Checks send the ETag of the last read as If-None-Match, so an unchanged object isn't
+// downloaded again. When a check fails the error goes to the Errors() channel, and the previous values stay in place:
+// Get keeps serving them, while GetErr returns the error until a check succeeds. The Getter is a Watcher, notified
+// when a check finds a new object. Call Close to stop checking.
+func NewS3Getter(client Client, bucket, key, format string, opts ...Option) (*Getter, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &Getter{
+		client:   client,
+		bucket:   bucket,
+		key:      key,
+		format:   format,
+		interval: DefaultRefreshInterval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.versionID == "" && g.interval <= 0 {
+		cancel()
+		return nil, fmt.Errorf("s3config: refresh interval must be positive, got %v", g.interval)
+	}
+	if _, err := g.refresh(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+	if g.versionID != "" {
+		close(g.done)
+		return g, nil
+	}
+	go g.run(ctx)
+	return g, nil
+}
+
+func (g *Getter) run(ctx context.Context) {
+	defer close(g.done)
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		changed, err := g.refresh(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			g.Report(err)
+		} else if changed {
+			g.Notify()
+		}
+	}
+}
+
+// refresh reads the object if it has changed since the last read, reporting whether it had.
+func (g *Getter) refresh(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	input := &s3.GetObjectInput{Bucket: aws.String(g.bucket), Key: aws.String(g.key)}
+	if g.versionID != "" {
+		input.VersionId = aws.String(g.versionID)
+	}
+	prev := g.current.Load()
+	if prev != nil && prev.etag != "" {
+		input.IfNoneMatch = aws.String(prev.etag)
+	}
+	out, err := g.client.GetObject(ctx, input)
+	if err != nil {
+		var re interface{ HTTPStatusCode() int }
+		if prev != nil && errors.As(err, &re) && re.HTTPStatusCode() == http.StatusNotModified {
+			g.err.Store(nil)
+			return false, nil
+		}
+		return false, g.fail(fmt.Errorf("s3config: getting s3://%s/%s: %w", g.bucket, g.key, err))
+	}
+	defer out.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(out.Body, maxObjectSize+1))
+	if err == nil && len(data) > maxObjectSize {
+		err = fmt.Errorf("object is larger than %d bytes", maxObjectSize)
+	}
+	if err != nil {
+		return false, g.fail(fmt.Errorf("s3config: reading s3://%s/%s: %w", g.bucket, g.key, err))
+	}
+	values, err := config.ParseFormat(g.format, data)
+	if err != nil {
+		return false, g.fail(fmt.Errorf("s3config: parsing s3://%s/%s: %w", g.bucket, g.key, err))
+	}
+	g.current.Store(&object{
+		values:       values,
+		etag:         aws.ToString(out.ETag),
+		versionID:    aws.ToString(out.VersionId),
+		lastModified: aws.ToTime(out.LastModified),
+	})
+	g.err.Store(nil)
+	return true, nil
+}
+
+func (g *Getter) fail(err error) error {
+	g.err.Store(&err)
+	return err
+}
+
+// Close : Stop checking the object for changes. The Getter keeps serving the values it has.
+func (g *Getter) Close() error {
+	g.closeOnce.Do(func() {
+		g.cancel()
+		<-g.done
+	})
+	return nil
+}
+
+// ETag : Return the ETag of the object the current values were read from.
+func (g *Getter) ETag() string {
+	return g.current.Load().etag
+}
+
+// VersionID : Return the version ID of the object the current values were read from, or "" if the bucket isn't
+// versioned.
+func (g *Getter) VersionID() string {
+	return g.current.Load().versionID
+}
+
+// ModTime : Return the LastModified time of the object the current values were read from, if key is in them.
+func (g *Getter) ModTime(key string) (time.Time, bool) {
+	obj := g.current.Load()
+	if _, ok := obj.values[key]; !ok || obj.lastModified.IsZero() {
+		return time.Time{}, false
+	}
+	return obj.lastModified, true
+}
+
+func (g *Getter) values() config.Map {
+	return g.current.Load().values
+}
+
+// GetErr : Return the value for key, the error from the latest check if it failed, or an error wrapping
+// config.ErrKeyNotSet.
+func (g *Getter) GetErr(key string) (string, error) {
+	if err := g.err.Load(); err != nil {
+		return "", *err
+	}
+	val, ok := g.values()[key]
+	if !ok {
+		return "", fmt.Errorf("s3config: %s: %w", key, config.ErrKeyNotSet)
+	}
+	return val, nil
+}
+
+// Lookup : Return the value for key from the latest successful read, and whether it's set.
+func (g *Getter) Lookup(key string) (string, bool) {
+	return g.values().Lookup(key)
+}
+
+// Keys : Return the keys from the latest successful read.
+func (g *Getter) Keys() []string {
+	return g.values().Keys()
+}
+
+// Snapshot : Return the values of the latest successful read, which won't change as the object is checked again.
+func (g *Getter) Snapshot() config.Getter {
+	return g.values()
+}
+
+// Get : Return the value for key from the latest successful read.
+func (g *Getter) Get(key string) string {
+	return g.values().Get(key)
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (g *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(g, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (g *Getter) GetStrings(key string) []string {
+	return config.SplitStrings(g.Get(key))
+}
+
+// MustGet will panic if the key is not present or empty.
+func (g *Getter) MustGet(key string) string {
+	return config.MustGet(g, key)
+}
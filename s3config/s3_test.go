@@ -0,0 +1,173 @@
+package s3config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	"github.com/efixler/config"
+)
+
+// fakeBucket is a versioned bucket holding one object, behaving like S3 for conditional and versioned GETs.
+type fakeBucket struct {
+	lock      sync.Mutex
+	versions  []string
+	downloads int
+	denied    bool
+}
+
+func (b *fakeBucket) put(data string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.versions = append(b.versions, data)
+}
+
+func (b *fakeBucket) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.denied {
+		return nil, errors.New("api error AccessDenied: Access Denied")
+	}
+	if aws.ToString(params.Key) != "app.json" || len(b.versions) == 0 {
+		return nil, &types.NoSuchKey{Message: aws.String("The specified key does not exist.")}
+	}
+	n := len(b.versions)
+	if params.VersionId != nil {
+		if _, err := fmt.Sscanf(*params.VersionId, "v%d", &n); err != nil || n < 1 || n > len(b.versions) {
+			return nil, errors.New("api error InvalidArgument: Invalid version id specified")
+		}
+	}
+	etag := fmt.Sprintf(`"etag-%d"`, n)
+	if aws.ToString(params.IfNoneMatch) == etag {
+		return nil, &awshttp.ResponseError{ResponseError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusNotModified}},
+			Err:      errors.New("not modified"),
+		}}
+	}
+	b.downloads++
+	return &s3.GetObjectOutput{
+		Body:         io.NopCloser(strings.NewReader(b.versions[n-1])),
+		ETag:         aws.String(etag),
+		VersionId:    aws.String(fmt.Sprintf("v%d", n)),
+		LastModified: aws.Time(time.Date(2026, 1, n, 0, 0, 0, 0, time.UTC)),
+	}, nil
+}
+
+func (b *fakeBucket) stats() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.downloads
+}
+
+func TestS3Getter(t *testing.T) {
+	bucket := &fakeBucket{}
+	bucket.put(`{"db": {"host": "db1"}, "hosts": ["a", "b"]}`)
+	g, err := NewS3Getter(bucket, "configs", "app.json", "json", WithRefreshInterval(5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	if v := g.Get("db.host"); v != "db1" {
+		t.Errorf("Expected 'db1', got '%s'", v)
+	}
+	if v := g.GetStrings("hosts"); len(v) != 2 {
+		t.Errorf("Expected [a b], got %q", v)
+	}
+	if g.ETag() != `"etag-1"` || g.VersionID() != "v1" {
+		t.Errorf("Unexpected object identity %s %s", g.ETag(), g.VersionID())
+	}
+	if mt, ok := config.ModTime(g, "db.host"); !ok || !mt.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the object's LastModified, got %v, %t", mt, ok)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if n := bucket.stats(); n != 1 {
+		t.Errorf("An unchanged object should not be downloaded again; got %d downloads", n)
+	}
+
+	changes := g.Watch()
+	bucket.put(`{"db": {"host": "db2"}}`)
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a change notification")
+	}
+	if v, err := g.GetErr("db.host"); err != nil || v != "db2" {
+		t.Errorf("Expected 'db2', got '%s' (%v)", v, err)
+	}
+	if g.VersionID() != "v2" {
+		t.Errorf("Expected version v2, got %s", g.VersionID())
+	}
+
+	bucket.lock.Lock()
+	bucket.denied = true
+	bucket.lock.Unlock()
+	select {
+	case err := <-g.Errors():
+		if err == nil {
+			t.Error("Expected a refresh error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a refresh error")
+	}
+	if _, err := g.GetErr("db.host"); err == nil {
+		t.Error("Expected GetErr to report the failed refresh")
+	}
+	if v := g.Get("db.host"); v != "db2" {
+		t.Errorf("Expected the previous value to be served, got '%s'", v)
+	}
+}
+
+func TestS3GetterVersionID(t *testing.T) {
+	bucket := &fakeBucket{}
+	bucket.put("HOST=db1")
+	bucket.put("HOST=db2")
+	g, err := NewS3Getter(bucket, "configs", "app.json", "env", WithVersionID("v1"), WithRefreshInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	if v := g.Get("HOST"); v != "db1" {
+		t.Errorf("Expected the pinned version's 'db1', got '%s'", v)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := bucket.stats(); n != 1 {
+		t.Errorf("A pinned version should be read once; got %d downloads", n)
+	}
+}
+
+func TestS3GetterErrors(t *testing.T) {
+	bucket := &fakeBucket{}
+	bucket.put("HOST=db1")
+	_, err := NewS3Getter(bucket, "configs", "missing.json", "json")
+	var noSuchKey *types.NoSuchKey
+	if !errors.As(err, &noSuchKey) {
+		t.Errorf("Expected a NoSuchKey error, got %v", err)
+	}
+	if _, err := NewS3Getter(bucket, "configs", "app.json", "json"); err == nil {
+		t.Error("Expected a parse error")
+	}
+	if _, err := NewS3Getter(bucket, "configs", "app.json", "env", WithRefreshInterval(0)); err == nil {
+		t.Error("Expected an error for a zero refresh interval")
+	}
+	g, err := NewS3Getter(bucket, "configs", "app.json", "env")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	if _, err := g.GetErr("PORT"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+}
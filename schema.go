@@ -0,0 +1,193 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Kind is the type of a SchemaField's value.
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindInt
+	KindBool
+	KindFloat
+	KindDuration
+	// KindStrings is a comma-delimited list, as read by GetStrings.
+	KindStrings
+)
+
+var kindNames = map[Kind]string{
+	KindString:   "string",
+	KindInt:      "int",
+	KindBool:     "bool",
+	KindFloat:    "float",
+	KindDuration: "duration",
+	KindStrings:  "list",
+}
+
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// SchemaField declares one config key for NewFromSchema.
+type SchemaField struct {
+	Key      string
+	Kind     Kind
+	Required bool
+	// Default is used when the key is unset. It's validated and normalized like a configured value.
+	Default string
+	// Normalize, if set, runs after the value is converted to its Kind's canonical form, and may reject it with an error.
+	// It must be idempotent (normalizing a normalized value changes nothing).
+	Normalize func(string) (string, error)
+	// Description documents the key.
+	Description string
+}
+
+// Schema declares the keys a program reads. See NewFromSchema.
+type Schema []SchemaField
+
+// NewFromSchema : Validate, default and normalize all of schema's keys from g in one pass at startup, returning a
+// Getter that serves the results, or an error listing every problem (see errors.Join).
+//
+// For each field, an explicit value from g beats the field's Default. A required field with neither is an error.
+// Values are then parsed according to the field's Kind and rewritten in canonical form: ints in plain decimal
+// ("+007" is "7"), bools as "true" or "false", floats without exponents, durations as time.Duration.String() ("90s" is
+// "1m30s") and lists with elements trimmed, empties dropped, and joined with plain commas. Strings are left as they
+// are. Then Normalize runs, if it's set. Since every canonical form parses back to itself, normalization is idempotent.
+//
+// Keys that aren't in the schema pass through to g, un-validated.
+func NewFromSchema(g Getter, schema Schema) (Getter, error) {
+	values := make(Map, len(schema))
+	errs := make([]error, 0)
+	for _, field := range schema {
+		val, err := field.resolve(g)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if val != "" {
+			values[field.Key] = val
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return Merge(FirstPresent, values, g), nil
+}
+
+// Validate : Check g against the schema as NewFromSchema does, returning an error listing every problem, or nil.
+func (s Schema) Validate(g Getter) error {
+	_, err := NewFromSchema(g, s)
+	return err
+}
+
+// FieldDoc is the documentation of one SchemaField, as emitted by Schema.DescribeJSON.
+type FieldDoc struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required"`
+	Default     string `json:"default"`
+	Description string `json:"description"`
+}
+
+// Docs : Return the documentation of each field, sorted by key.
+func (s Schema) Docs() []FieldDoc {
+	rval := make([]FieldDoc, 0, len(s))
+	for _, field := range s {
+		rval = append(rval, FieldDoc{
+			Name:        field.Key,
+			Type:        field.Kind.String(),
+			Required:    field.Required,
+			Default:     field.Default,
+			Description: field.Description,
+		})
+	}
+	sort.SliceStable(rval, func(i, j int) bool { return rval[i].Name < rval[j].Name })
+	return rval
+}
+
+// Describe : Return a table of the schema's keys, sorted, with each one's type, whether it's required, its default
+// and its description, for operator docs or a --config-help flag:
+//
+//	NAME     TYPE      REQUIRED  DEFAULT  DESCRIPTION
+//	PORT     int       yes       -        Port to listen on
+//	TIMEOUT  duration  no        30s      Request timeout
+func (s Schema) Describe() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tREQUIRED\tDEFAULT\tDESCRIPTION")
+	for _, doc := range s.Docs() {
+		required, dflt := "no", doc.Default
+		if doc.Required {
+			required = "yes"
+		}
+		if dflt == "" {
+			dflt = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", doc.Name, doc.Type, required, dflt, doc.Description)
+	}
+	w.Flush()
+	return buf.String()
+}
+
+// DescribeJSON : Return the Docs as a JSON array, for tooling that generates documentation from the schema.
+func (s Schema) DescribeJSON() ([]byte, error) {
+	return json.MarshalIndent(s.Docs(), "", "  ")
+}
+
+// resolve returns the field's validated, defaulted and normalized value.
+func (f SchemaField) resolve(g Getter) (string, error) {
+	val := g.Get(f.Key)
+	if val == "" {
+		val = f.Default
+	}
+	if val == "" {
+		if f.Required {
+			return "", fmt.Errorf("config: %s is required", f.Key)
+		}
+		return "", nil
+	}
+	val, err := canonical(f.Kind, val)
+	if err != nil {
+		return "", fmt.Errorf("config: %s is not a valid %s", f.Key, f.Kind)
+	}
+	if f.Normalize != nil {
+		if val, err = f.Normalize(val); err != nil {
+			return "", fmt.Errorf("config: %s: %w", f.Key, err)
+		}
+	}
+	return val, nil
+}
+
+// canonical parses val as kind and formats it back in canonical form.
+func canonical(kind Kind, val string) (string, error) {
+	switch kind {
+	case KindInt:
+		i, err := strconv.Atoi(strings.TrimSpace(val))
+		return strconv.Itoa(i), err
+	case KindBool:
+		b, err := strconv.ParseBool(strings.TrimSpace(val))
+		return strconv.FormatBool(b), err
+	case KindFloat:
+		f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		return strconv.FormatFloat(f, 'f', -1, 64), err
+	case KindDuration:
+		d, err := time.ParseDuration(strings.TrimSpace(val))
+		return d.String(), err
+	case KindStrings:
+		return strings.Join(splitTrimSpace(val), ","), nil
+	}
+	return val, nil
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewFromSchema(t *testing.T) {
+	schema := Schema{
+		{Key: "PORT", Kind: KindInt, Required: true},
+		{Key: "DEBUG", Kind: KindBool, Default: "0"},
+		{Key: "TIMEOUT", Kind: KindDuration, Default: "90s"},
+		{Key: "RATIO", Kind: KindFloat},
+		{Key: "HOSTS", Kind: KindStrings},
+		{Key: "REGION", Kind: KindString, Normalize: func(s string) (string, error) { return strings.ToLower(s), nil }},
+	}
+	g, err := NewFromSchema(Map{"PORT": "+0080", "RATIO": "1e3", "HOSTS": " a, ,b ", "REGION": "US-East", "EXTRA": "x"}, schema)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"PORT":    "80",
+		"DEBUG":   "false",
+		"TIMEOUT": "1m30s",
+		"RATIO":   "1000",
+		"HOSTS":   "a,b",
+		"REGION":  "us-east",
+		"EXTRA":   "x",
+	}
+	for key, val := range expected {
+		if v := g.Get(key); v != val {
+			t.Errorf("%s: expected '%s', got '%s'", key, val, v)
+		}
+	}
+
+	// Normalizing normalized values changes nothing.
+	again, err := NewFromSchema(g, schema)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for key, val := range expected {
+		if v := again.Get(key); v != val {
+			t.Errorf("%s: expected a second pass to keep '%s', got '%s'", key, val, v)
+		}
+	}
+
+	bad := Schema{
+		{Key: "PORT", Kind: KindInt, Required: true},
+		{Key: "DEBUG", Kind: KindBool},
+		{Key: "REGION", Normalize: func(string) (string, error) { return "", errors.New("unknown region") }},
+	}
+	_, err = NewFromSchema(Map{"DEBUG": "maybe", "REGION": "mars"}, bad)
+	for _, msg := range []string{"PORT is required", "DEBUG is not a valid bool", "REGION: unknown region"} {
+		if err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("Expected an error containing '%s', got %v", msg, err)
+		}
+	}
+}
+
+func TestSchemaDescribe(t *testing.T) {
+	schema := Schema{
+		{Key: "TIMEOUT", Kind: KindDuration, Default: "30s", Description: "Request timeout"},
+		{Key: "PORT", Kind: KindInt, Required: true, Description: "Port to listen on"},
+		{Key: "HOSTS", Kind: KindStrings},
+	}
+	expected := `NAME     TYPE      REQUIRED  DEFAULT  DESCRIPTION
+HOSTS    list      no        -        
+PORT     int       yes       -        Port to listen on
+TIMEOUT  duration  no        30s      Request timeout
+`
+	if v := schema.Describe(); v != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, v)
+	}
+
+	data, err := schema.DescribeJSON()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var docs []FieldDoc
+	if err := json.Unmarshal(data, &docs); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(docs, schema.Docs()) || docs[1] != (FieldDoc{Name: "PORT", Type: "int", Required: true, Description: "Port to listen on"}) {
+		t.Errorf("Unexpected JSON docs %s", data)
+	}
+	if !strings.Contains(string(data), `"name": "HOSTS"`) {
+		t.Errorf("Expected lowercase JSON field names, got %s", data)
+	}
+
+	if err := schema.Validate(Map{"PORT": "x"}); err == nil || !strings.Contains(err.Error(), "PORT is not a valid int") {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+	if err := schema.Validate(Map{"PORT": "80"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
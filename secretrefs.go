@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultSecretScheme is the reference scheme WithSecretRefs uses when it's passed an empty scheme.
+const DefaultSecretScheme = "secret"
+
+// WithSecretRefs : Wrap a Getter so that values of the form scheme://path, e.g. DB_PASSWORD="secret://db/password",
+// are replaced by looking up path in a separate resolver Getter (a Vault getter, say). This keeps secrets out of
+// the main config while letting it reference them by name. If scheme is empty, DefaultSecretScheme is used.
+//
+// The returned Getter is an ErrorGetter: GetErr returns an error (wrapping ErrKeyNotSet if the resolver had no
+// value) when a reference can't be resolved, and Get returns "" rather than the literal reference.
+// GetStrings resolves each element separately.
+func WithSecretRefs(g Getter, resolver Getter, scheme string) Getter {
+	if scheme == "" {
+		scheme = DefaultSecretScheme
+	}
+	return &secretRefs{g: g, resolver: resolver, prefix: scheme + "://"}
+}
+
+type secretRefs struct {
+	g        Getter
+	resolver Getter
+	prefix   string
+}
+
+func (s *secretRefs) resolve(val string) (string, error) {
+	path, ok := strings.CutPrefix(val, s.prefix)
+	if !ok {
+		return val, nil
+	}
+	secret, err := GetErr(s.resolver, path)
+	if err != nil {
+		return "", fmt.Errorf("config: resolving %s%s: %w", s.prefix, path, err)
+	}
+	if secret == "" {
+		return "", fmt.Errorf("config: resolving %s%s: %w", s.prefix, path, ErrKeyNotSet)
+	}
+	return secret, nil
+}
+
+func (s *secretRefs) GetErr(key string) (string, error) {
+	val, err := GetErr(s.g, key)
+	if err != nil {
+		return "", err
+	}
+	return s.resolve(val)
+}
+
+func (s *secretRefs) Get(key string) string {
+	val, _ := s.GetErr(key)
+	return val
+}
+
+func (s *secretRefs) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(s, key, dflt)
+}
+
+func (s *secretRefs) GetStrings(key string) []string {
+	vals := s.g.GetStrings(key)
+	rval := make([]string, len(vals))
+	for i, val := range vals {
+		rval[i], _ = s.resolve(val)
+	}
+	return rval
+}
+
+func (s *secretRefs) MustGet(key string) string {
+	return MustGet(s, key)
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWithSecretRefs(t *testing.T) {
+	secrets := Map{"db/password": "hunter2", "api/key": "k1"}
+	c := WithSecretRefs(Map{
+		"DB_PASSWORD": "secret://db/password",
+		"DB_HOST":     "localhost",
+		"MISSING":     "secret://nope",
+		"KEYS":        "secret://api/key, plain",
+	}, secrets, "")
+	if v := c.Get("DB_PASSWORD"); v != "hunter2" {
+		t.Errorf("Expected 'hunter2', got '%s'", v)
+	}
+	if v := c.Get("DB_HOST"); v != "localhost" {
+		t.Errorf("Expected plain values to pass through, got '%s'", v)
+	}
+	if v := c.Get("MISSING"); v != "" {
+		t.Errorf("Expected unresolvable reference to be empty, got '%s'", v)
+	}
+	if _, err := GetErr(c, "MISSING"); !errors.Is(err, ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+	if v := c.GetStrings("KEYS"); !reflect.DeepEqual(v, []string{"k1", "plain"}) {
+		t.Errorf("Expected [k1 plain], got %q", v)
+	}
+}
+
+func TestWithSecretRefsCustomScheme(t *testing.T) {
+	c := WithSecretRefs(Map{"TOKEN": "vault://token", "OTHER": "secret://token"}, Map{"token": "t"}, "vault")
+	if v := c.Get("TOKEN"); v != "t" {
+		t.Errorf("Expected 't', got '%s'", v)
+	}
+	if v := c.Get("OTHER"); v != "secret://token" {
+		t.Errorf("Expected other schemes to pass through, got '%s'", v)
+	}
+}
+
+func TestWithSecretRefsDoesNotMutate(t *testing.T) {
+	vals := []string{"secret://db/password"}
+	c := WithSecretRefs(sharedList{Map: Map{}, vals: vals}, Map{"db/password": "hunter2"}, "")
+	if v := c.GetStrings("PASSWORDS"); !reflect.DeepEqual(v, []string{"hunter2"}) {
+		t.Errorf("Expected the resolved secret, got %q", v)
+	}
+	if !reflect.DeepEqual(vals, []string{"secret://db/password"}) {
+		t.Errorf("The backend's slice should be left alone, got %q", vals)
+	}
+}
@@ -0,0 +1,59 @@
+// Package semverconfig reads config values as semantic versions and version constraints, for version-gated behavior
+// and compatibility checks, using github.com/Masterminds/semver.
+package semverconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/efixler/config"
+)
+
+// GetSemver : Parse the value for key as a semantic version, e.g. "1.4.2", "v2.0.0-rc.1" or "1.4.2+build.7".
+// A leading "v" is optional, and a version missing its minor or patch number ("1", "1.4") is read as if they were
+// zero. Anything else that isn't semver 2.0 is an error naming the key; an unset key wraps config.ErrKeyNotSet.
+func GetSemver(g config.Getter, key string) (*semver.Version, error) {
+	raw, err := get(g, key)
+	if err != nil {
+		return nil, err
+	}
+	v, err := semver.NewVersion(raw)
+	if err != nil {
+		return nil, fmt.Errorf("semverconfig: %s value %q is not a semantic version: %w", key, raw, err)
+	}
+	return v, nil
+}
+
+// GetSemverConstraint : Parse the value for key as a version range, e.g. ">=1.2.0 <2.0.0". Comparisons (=, !=, >,
+// <, >=, <=) separated by spaces or commas must all hold, and || separates alternatives. ~1.2 allows patch
+// releases, ^1.2 anything below the next major version, 1.2.x (or 1.2.*) is a wildcard, and 1.2 - 1.4 is an
+// inclusive range. Versions in a constraint may have a "v" prefix. A malformed range is an error naming the key;
+// an unset key wraps config.ErrKeyNotSet.
+//
+// Note that, per semver, a range only matches pre-release versions when one of its comparisons names a
+// pre-release itself.
+func GetSemverConstraint(g config.Getter, key string) (semver.Constraints, error) {
+	raw, err := get(g, key)
+	if err != nil {
+		return semver.Constraints{}, err
+	}
+	c, err := semver.NewConstraint(raw)
+	if err != nil {
+		return semver.Constraints{}, fmt.Errorf("semverconfig: %s value %q is not a version constraint: %w", key, raw, err)
+	}
+	return *c, nil
+}
+
+func get(g config.Getter, key string) (string, error) {
+	raw, err := config.GetErr(g, key)
+	if err != nil {
+		return "", fmt.Errorf("semverconfig: %s: %w", key, err)
+	}
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("semverconfig: %s: %w", key, config.ErrKeyNotSet)
+	}
+	return raw, nil
+}
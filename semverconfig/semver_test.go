@@ -0,0 +1,78 @@
+package semverconfig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/efixler/config"
+)
+
+var testValues = config.Map{
+	"MIN_VERSION":    "1.4.2",
+	"V_PREFIX":       " v2.0.0-rc.1 ",
+	"SHORT":          "1.4",
+	"BAD_VERSION":    "one.two",
+	"RANGE":          ">=1.2.0 <2.0.0",
+	"CARET":          "^1.4 || ~3.1",
+	"BAD_CONSTRAINT": ">>1.0",
+}
+
+func TestGetSemver(t *testing.T) {
+	tests := map[string]string{
+		"MIN_VERSION": "1.4.2",
+		"V_PREFIX":    "2.0.0-rc.1",
+		"SHORT":       "1.4.0",
+	}
+	for key, expected := range tests {
+		v, err := GetSemver(testValues, key)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", key, err)
+			continue
+		}
+		if v.String() != expected {
+			t.Errorf("%s: expected %s, got %s", key, expected, v)
+		}
+	}
+	if v, _ := GetSemver(testValues, "MIN_VERSION"); !v.LessThan(semver.MustParse("1.10.0")) {
+		t.Error("Versions should compare numerically, not as strings")
+	}
+	if _, err := GetSemver(testValues, "BAD_VERSION"); err == nil || errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+	if _, err := GetSemver(testValues, "UNSET"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+}
+
+func TestGetSemverConstraint(t *testing.T) {
+	tests := []struct {
+		key, version string
+		expected     bool
+	}{
+		{"RANGE", "1.2.0", true},
+		{"RANGE", "1.9.9", true},
+		{"RANGE", "2.0.0", false},
+		{"RANGE", "1.1.9", false},
+		{"CARET", "1.9.0", true},
+		{"CARET", "2.0.0", false},
+		{"CARET", "3.1.7", true},
+		{"CARET", "3.2.0", false},
+	}
+	for _, test := range tests {
+		c, err := GetSemverConstraint(testValues, test.key)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.key, err)
+		}
+		if ok := c.Check(semver.MustParse(test.version)); ok != test.expected {
+			t.Errorf("%s (%s) with %s: expected %v, got %v", test.key, c, test.version, test.expected, ok)
+		}
+	}
+	if _, err := GetSemverConstraint(testValues, "BAD_CONSTRAINT"); err == nil || errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+	if _, err := GetSemverConstraint(testValues, "UNSET"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+}
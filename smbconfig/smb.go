@@ -0,0 +1,260 @@
+// Package smbconfig provides a config.Getter over a config file on an SMB/CIFS share, for Windows-centric and
+// cross-platform services that keep config on existing file-share infrastructure, without mounting the share at the
+// OS level. The SMB client is supplied by the caller (see Dialer), so this package adds no dependencies.
+package smbconfig
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/efixler/config"
+)
+
+// maxFileSize bounds the size of the config file, as for the file getters in the config package.
+const maxFileSize = 32 << 20
+
+// DefaultRefreshInterval is how often the file is read again, unless overridden with WithRefreshInterval.
+const DefaultRefreshInterval = time.Minute
+
+// SMBAuth holds the NTLM credentials for the share.
+type SMBAuth struct {
+	User     string
+	Password string
+	Domain   string
+}
+
+// Share is a mounted SMB share. *smb2.Share from github.com/hirochachacha/go-smb2 implements it.
+// If a Share is also an io.Closer, it's closed when it's replaced or the Getter is closed.
+type Share interface {
+	ReadFile(name string) ([]byte, error)
+}
+
+// Dialer connects to the server named in share (`\\server\share`, or server/share) with auth, and mounts the share.
+// With go-smb2 it looks something like:
+//
+//	func(share string, auth smbconfig.SMBAuth) (smbconfig.Share, error) {
+//		server, name, _ := strings.Cut(strings.TrimLeft(strings.ReplaceAll(share, `\`, "/"), "/"), "/")
+//		conn, err := net.Dial("tcp", server+":445")
+//		if err != nil {
+//			return nil, err
+//		}
+//		d := &smb2.Dialer{Initiator: &smb2.NTLMInitiator{User: auth.User, Password: auth.Password, Domain: auth.Domain}}
+//		session, err := d.Dial(conn)
+//		if err != nil {
+//			conn.Close()
+//			return nil, err
+//		}
+//		return session.Mount(name)
+//	}
+type Dialer func(share string, auth SMBAuth) (Share, error)
+
+// Option configures a Getter.
+type Option func(*Getter)
+
+// WithRefreshInterval : Read the file every interval instead of every DefaultRefreshInterval. NewSMBGetter returns
+// an error if interval isn't positive.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(g *Getter) {
+		g.interval = interval
+	}
+}
+
+// Getter holds the values of the latest read of the file. See NewSMBGetter.
+type Getter struct {
+	config.Notifier
+	config.ErrorReporter
+	dial      Dialer
+	share     string
+	path      string
+	format    string
+	auth      SMBAuth
+	interval  time.Duration
+	lock      sync.Mutex
+	conn      Share
+	data      []byte
+	values    atomic.Pointer[config.Map]
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewSMBGetter : Connect to share with dial and auth, and return a Getter over the file at path on it, parsed as
+// format (see config.ParseFormat). The file is read before NewSMBGetter returns, and a failure (connection, auth,
+// a missing file, a file larger than 32MB or a parse error) is returned.
+//
+// After that the file is read again every DefaultRefreshInterval. A read that fails on the existing connection is
+// retried once on a new one, since sessions drop. When a refresh fails the error goes to the Errors() channel, and
+// the previous values stay in place and keep being served, so an outage of the share doesn't break readers.
+// The Getter is a Watcher, notified when a refresh finds the file changed. Call Close to stop refreshing.
+func NewSMBGetter(dial Dialer, share, path, format string, auth SMBAuth, opts ...Option) (*Getter, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &Getter{
+		dial:     dial,
+		share:    share,
+		path:     path,
+		format:   format,
+		auth:     auth,
+		interval: DefaultRefreshInterval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.interval <= 0 {
+		cancel()
+		return nil, fmt.Errorf("smbconfig: refresh interval must be positive, got %v", g.interval)
+	}
+	if _, err := g.refresh(); err != nil {
+		cancel()
+		g.disconnect()
+		return nil, err
+	}
+	go g.run(ctx)
+	return g, nil
+}
+
+func (g *Getter) run(ctx context.Context) {
+	defer close(g.done)
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		changed, err := g.refresh()
+		if err != nil {
+			g.Report(err)
+		} else if changed {
+			g.Notify()
+		}
+	}
+}
+
+// refresh reads and parses the file, reporting whether its contents changed.
+func (g *Getter) refresh() (bool, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	data, err := g.read()
+	if err == nil && g.values.Load() != nil && bytes.Equal(data, g.data) {
+		return false, nil
+	}
+	var values config.Map
+	if err == nil {
+		if values, err = config.ParseFormat(g.format, data); err != nil {
+			err = fmt.Errorf("smbconfig: parsing %s: %w", g.path, err)
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	g.data = data
+	g.values.Store(&values)
+	return true, nil
+}
+
+// read reads the file, dialing first if there's no connection and once more if the read fails. The lock must be held.
+func (g *Getter) read() ([]byte, error) {
+	if g.conn != nil {
+		if data, err := g.conn.ReadFile(g.path); err == nil {
+			return g.checkSize(data)
+		}
+		g.disconnectLocked()
+	}
+	conn, err := g.dial(g.share, g.auth)
+	if err != nil {
+		return nil, fmt.Errorf("smbconfig: connecting to %s: %w", g.share, err)
+	}
+	g.conn = conn
+	data, err := conn.ReadFile(g.path)
+	if err != nil {
+		return nil, fmt.Errorf("smbconfig: reading %s from %s: %w", g.path, g.share, err)
+	}
+	return g.checkSize(data)
+}
+
+// checkSize rejects a file larger than maxFileSize.
+func (g *Getter) checkSize(data []byte) ([]byte, error) {
+	if len(data) > maxFileSize {
+		return nil, fmt.Errorf("smbconfig: %s on %s is larger than %d bytes", g.path, g.share, maxFileSize)
+	}
+	return data, nil
+}
+
+func (g *Getter) disconnect() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.disconnectLocked()
+}
+
+func (g *Getter) disconnectLocked() {
+	if c, ok := g.conn.(io.Closer); ok {
+		c.Close()
+	}
+	g.conn = nil
+}
+
+// Close : Stop refreshing and disconnect. The Getter keeps serving the values it has.
+func (g *Getter) Close() error {
+	g.closeOnce.Do(func() {
+		g.cancel()
+		<-g.done
+		g.disconnect()
+	})
+	return nil
+}
+
+func (g *Getter) current() config.Map {
+	return *g.values.Load()
+}
+
+// GetErr : Return the value for key from the latest successful read, or an error wrapping config.ErrKeyNotSet.
+func (g *Getter) GetErr(key string) (string, error) {
+	val, ok := g.current()[key]
+	if !ok {
+		return "", fmt.Errorf("smbconfig: %s: %w", key, config.ErrKeyNotSet)
+	}
+	return val, nil
+}
+
+// Lookup : Return the value for key from the latest successful read, and whether it's set.
+func (g *Getter) Lookup(key string) (string, bool) {
+	return g.current().Lookup(key)
+}
+
+// Keys : Return the keys from the latest successful read.
+func (g *Getter) Keys() []string {
+	return g.current().Keys()
+}
+
+// Snapshot : Return the values of the latest successful read, which won't change as the file is read again.
+func (g *Getter) Snapshot() config.Getter {
+	return g.current()
+}
+
+// Get : Return the value for key from the latest successful read.
+func (g *Getter) Get(key string) string {
+	return g.current().Get(key)
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (g *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(g, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (g *Getter) GetStrings(key string) []string {
+	return config.SplitStrings(g.Get(key))
+}
+
+// MustGet will panic if the key is not present or empty.
+func (g *Getter) MustGet(key string) string {
+	return config.MustGet(g, key)
+}
@@ -0,0 +1,131 @@
+package smbconfig
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/efixler/config"
+)
+
+type fakeShare struct {
+	server *fakeServer
+	closed bool
+}
+
+func (s *fakeShare) ReadFile(name string) ([]byte, error) {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	if s.closed || s.server.dropped {
+		return nil, errors.New("connection reset")
+	}
+	data, ok := s.server.files[name]
+	if !ok {
+		return nil, errors.New("file not found")
+	}
+	return []byte(data), nil
+}
+
+func (s *fakeShare) Close() error {
+	s.closed = true
+	return nil
+}
+
+type fakeServer struct {
+	lock    sync.Mutex
+	files   map[string]string
+	dropped bool
+	down    bool
+	dials   int
+}
+
+func (s *fakeServer) dial(share string, auth SMBAuth) (Share, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.dials++
+	if auth.Password != "secret" {
+		return nil, errors.New("STATUS_LOGON_FAILURE")
+	} else if s.down {
+		return nil, errors.New("connection refused")
+	}
+	s.dropped = false
+	return &fakeShare{server: s}, nil
+}
+
+func (s *fakeServer) update(f func()) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	f()
+}
+
+func TestSMBGetter(t *testing.T) {
+	server := &fakeServer{files: map[string]string{`config\app.json`: `{"db": {"host": "db1"}, "hosts": ["a", "b"]}`}}
+	auth := SMBAuth{User: "svc", Password: "secret", Domain: "CORP"}
+	g, err := NewSMBGetter(server.dial, `\\fs1\config`, `config\app.json`, "json", auth, WithRefreshInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	if v := g.Get("db.host"); v != "db1" {
+		t.Errorf("Expected 'db1', got '%s'", v)
+	}
+	if v := g.GetStrings("hosts"); len(v) != 2 {
+		t.Errorf("Expected [a b], got %q", v)
+	}
+
+	changes := g.Watch()
+	server.update(func() {
+		server.files[`config\app.json`] = `{"db": {"host": "db2"}}`
+		server.dropped = true
+	})
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a change notification")
+	}
+	if v, err := g.GetErr("db.host"); err != nil || v != "db2" {
+		t.Errorf("Expected 'db2' after reconnecting, got '%s' (%v)", v, err)
+	}
+
+	server.update(func() { server.dropped, server.down = true, true })
+	select {
+	case err := <-g.Errors():
+		if err == nil {
+			t.Error("Expected a refresh error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a refresh error")
+	}
+	if v, err := g.GetErr("db.host"); err != nil || v != "db2" {
+		t.Errorf("Expected the previous value to be served, got '%s' (%v)", v, err)
+	}
+	if v := g.MustGet("db.host"); v != "db2" {
+		t.Errorf("Expected MustGet to keep working during the outage, got '%s'", v)
+	}
+}
+
+func TestSMBGetterErrors(t *testing.T) {
+	server := &fakeServer{files: map[string]string{"app.env": "A=1", "big.env": "A=" + strings.Repeat("x", maxFileSize)}}
+	if _, err := NewSMBGetter(server.dial, "fs1/config", "app.env", "env", SMBAuth{User: "svc"}); err == nil {
+		t.Error("Expected an auth error")
+	}
+	if _, err := NewSMBGetter(server.dial, "fs1/config", "missing.env", "env", SMBAuth{Password: "secret"}); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+	if _, err := NewSMBGetter(server.dial, "fs1/config", "big.env", "env", SMBAuth{Password: "secret"}); err == nil {
+		t.Error("Expected an error for a file over the size cap")
+	}
+	if _, err := NewSMBGetter(server.dial, "fs1/config", "app.env", "env", SMBAuth{Password: "secret"}, WithRefreshInterval(0)); err == nil {
+		t.Error("Expected an error for a zero refresh interval")
+	}
+	g, err := NewSMBGetter(server.dial, "fs1/config", "app.env", "env", SMBAuth{Password: "secret"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	if _, err := g.GetErr("B"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// socketCacheTTL is how long NewUnixSocketGetter remembers values (and misses).
+const socketCacheTTL = time.Second
+
+// socketTimeout bounds each request/response exchange with the agent.
+const socketTimeout = 5 * time.Second
+
+// NewUnixSocketGetter : Return a Getter that asks a config agent listening on the Unix domain socket at socketPath
+// for each value, e.g. a sidecar. The connection is opened here (so a missing agent is reported right away) and kept
+// open; if an exchange fails the Getter reconnects and retries once. Values are cached for a second.
+//
+// The wire protocol is line-based. For each lookup the client writes
+//
+//	GET <key>\n
+//
+// and the agent replies with the value followed by a newline. An empty line means the key is not set, so values can't
+// be empty or contain newlines. Keys never contain whitespace. Requests on a connection are strictly sequential, and
+// the agent may close the connection at any time.
+//
+// The returned Getter is an ErrorGetter: IO errors are returned by GetErr, and logged (returning "") by Get. A key
+// that isn't set is an error wrapping ErrKeyNotSet.
+func NewUnixSocketGetter(socketPath string) (Getter, error) {
+	s := &unixSocketGetter{path: socketPath, cache: make(map[string]cacheEntry[string]), now: time.Now}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+type unixSocketGetter struct {
+	path  string
+	lock  sync.Mutex
+	conn  net.Conn
+	r     *bufio.Reader
+	cache map[string]cacheEntry[string]
+	now   func() time.Time
+}
+
+func (s *unixSocketGetter) connect() error {
+	conn, err := net.DialTimeout("unix", s.path, socketTimeout)
+	if err != nil {
+		return fmt.Errorf("config: connecting to %s: %w", s.path, err)
+	}
+	s.conn, s.r = conn, bufio.NewReader(conn)
+	return nil
+}
+
+func (s *unixSocketGetter) disconnect() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn, s.r = nil, nil
+	}
+}
+
+// request does one GET exchange on the current connection, connecting first if needed.
+func (s *unixSocketGetter) request(key string) (string, error) {
+	if s.conn == nil {
+		if err := s.connect(); err != nil {
+			return "", err
+		}
+	}
+	s.conn.SetDeadline(time.Now().Add(socketTimeout))
+	if _, err := fmt.Fprintf(s.conn, "GET %s\n", key); err != nil {
+		return "", fmt.Errorf("config: writing to %s: %w", s.path, err)
+	}
+	line, err := s.r.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("config: reading from %s: %w", s.path, err)
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), nil
+}
+
+// GetErr : Ask the agent for key, unless it's cached.
+func (s *unixSocketGetter) GetErr(key string) (string, error) {
+	if key == "" || strings.ContainsAny(key, " \t\r\n") {
+		return "", fmt.Errorf("config: invalid key %q for %s", key, s.path)
+	}
+	val, err := s.lookup(key)
+	if err == nil && val == "" {
+		err = fmt.Errorf("config: %s: %w", key, ErrKeyNotSet)
+	}
+	return val, err
+}
+
+// lookup returns the cached value for key, or asks the agent for it.
+func (s *unixSocketGetter) lookup(key string) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	now := s.now()
+	if entry, ok := s.cache[key]; ok && now.Before(entry.expires) {
+		return entry.val, nil
+	}
+	val, err := s.request(key)
+	if err != nil {
+		// The connection may have gone stale (e.g. the agent restarted), so try once more on a fresh one.
+		s.disconnect()
+		if val, err = s.request(key); err != nil {
+			s.disconnect()
+			return "", err
+		}
+	}
+	s.cache[key] = cacheEntry[string]{val: val, expires: now.Add(socketCacheTTL)}
+	return val, nil
+}
+
+// Get : Return the value for key, or "" if it's not set or the agent can't be reached.
+func (s *unixSocketGetter) Get(key string) string {
+	val, err := s.GetErr(key)
+	if err != nil && !errors.Is(err, ErrKeyNotSet) {
+		log.Print(err)
+	}
+	return val
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (s *unixSocketGetter) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(s, key, dflt)
+}
+
+// GetStrings : Split the value for key like Env.GetStrings.
+func (s *unixSocketGetter) GetStrings(key string) []string {
+	return SplitStrings(s.Get(key))
+}
+
+// MustGet will panic if the key is not present or empty.
+func (s *unixSocketGetter) MustGet(key string) string {
+	return MustGet(s, key)
+}
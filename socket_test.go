@@ -0,0 +1,72 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// serveSocketAgent answers GETs from values, closing each connection after maxRequests exchanges.
+func serveSocketAgent(t *testing.T, values map[string]string, maxRequests int) (string, *atomic.Int32) {
+	path := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	requests := new(atomic.Int32)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			r := bufio.NewReader(conn)
+			for i := 0; i < maxRequests; i++ {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					break
+				}
+				requests.Add(1)
+				conn.Write([]byte(values[strings.TrimPrefix(strings.TrimSpace(line), "GET ")] + "\n"))
+			}
+			conn.Close()
+		}
+	}()
+	return path, requests
+}
+
+func TestUnixSocketGetter(t *testing.T) {
+	path, requests := serveSocketAgent(t, map[string]string{"HOST": "db1", "PORTS": "1, 2"}, 1)
+	g, err := NewUnixSocketGetter(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := g.Get("HOST"); v != "db1" {
+		t.Errorf("Expected 'db1', got '%s'", v)
+	}
+	// The agent dropped the connection after the first request, so this one has to reconnect.
+	if v := g.GetStrings("PORTS"); len(v) != 2 || v[1] != "2" {
+		t.Errorf("Expected [1 2], got %q", v)
+	}
+	if v := g.GetOrDefault("MISSING", "x"); v != "x" {
+		t.Errorf("Expected the default, got '%s'", v)
+	}
+	if _, err := GetErr(g, "MISSING"); !errors.Is(err, ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+	g.Get("HOST")
+	if n := requests.Load(); n != 3 {
+		t.Errorf("Expected 3 requests with caching, got %d", n)
+	}
+	if _, err := GetErr(g, "BAD KEY"); err == nil {
+		t.Error("Expected an error for a key with whitespace")
+	}
+	if _, err := NewUnixSocketGetter(filepath.Join(t.TempDir(), "none.sock")); err == nil {
+		t.Error("Expected an error for a missing socket")
+	}
+}
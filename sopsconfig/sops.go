@@ -0,0 +1,87 @@
+// Package sopsconfig provides a config.Getter over a sops-encrypted config file, so config that's committed encrypted
+// can be read without a separate decrypt step in deployment. It keeps the sops dependency (and the cloud KMS SDKs it
+// brings with it) out of the root package.
+package sopsconfig
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/getsops/sops/v3/decrypt"
+
+	"github.com/efixler/config"
+	_ "github.com/efixler/config/yamlconfig" // registers the yaml format
+)
+
+// innerFormats maps sops file formats to the config formats their cleartext is parsed with.
+var innerFormats = map[formats.Format]string{
+	formats.Json:   "json",
+	formats.Yaml:   "yaml",
+	formats.Dotenv: "env",
+	formats.Ini:    "ini",
+}
+
+// NewSopsGetter : Decrypt the sops file at path and return a Getter over its values. The file is read once.
+//
+// The format is chosen by extension as sops chooses it: .json, .yaml or .yml, .env, and .ini; the cleartext is then
+// parsed as the config format of the same name (see config.ParseFormat). Other files are sops "binary" files,
+// which aren't supported, since they have no keys. The sops metadata isn't part of the values.
+//
+// Keys are resolved the way the sops command resolves them: age keys from SOPS_AGE_KEY or SOPS_AGE_KEY_FILE (or the
+// default keys.txt), PGP keys from the GPG agent, and AWS KMS, GCP KMS, Azure Key Vault and Vault transit keys with
+// the ambient credentials of each. A file that can't be decrypted, or that fails its MAC check, is an error
+// naming the file.
+//
+// Every value that was encrypted in the file is a secret to the Getter's IsSecret, so config's redaction helpers
+// (MustGet panics, audit logs and the like) mask it. Values that sops left in cleartext, for instance through
+// unencrypted_suffix, aren't secrets.
+func NewSopsGetter(path string) (config.Getter, error) {
+	format, ok := innerFormats[formats.FormatForPath(path)]
+	if !ok {
+		return nil, fmt.Errorf("sopsconfig: %s: not a JSON, YAML, dotenv or INI file", path)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("sopsconfig: %w", err)
+	}
+	cleartext, err := decrypt.DataWithFormat(raw, formats.FormatForPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("sopsconfig: decrypting %s: %w", path, err)
+	}
+	values, err := config.ParseFormat(format, cleartext)
+	if err != nil {
+		return nil, fmt.Errorf("sopsconfig: %s: %w", path, err)
+	}
+	// In the encrypted file the same keys hold ENC[...] values, which is how sops marks what it encrypted.
+	encrypted, err := config.ParseFormat(format, raw)
+	if err != nil {
+		return nil, fmt.Errorf("sopsconfig: %s: %w", path, err)
+	}
+	secrets := make(map[string]bool)
+	for key := range values {
+		if strings.HasPrefix(encrypted[key], "ENC[") {
+			secrets[key] = true
+		}
+	}
+	return &sopsGetter{Map: values, secrets: secrets}, nil
+}
+
+type sopsGetter struct {
+	config.Map
+	secrets map[string]bool
+}
+
+// IsSecret : Report whether the value of key was encrypted in the file.
+func (g *sopsGetter) IsSecret(key string) bool {
+	return g.secrets[key]
+}
+
+func (g *sopsGetter) GetErr(key string) (string, error) {
+	val, ok := g.Map[key]
+	if !ok {
+		return "", fmt.Errorf("sopsconfig: %s: %w", key, config.ErrKeyNotSet)
+	}
+	return val, nil
+}
@@ -0,0 +1,120 @@
+package sopsconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/getsops/sops/v3"
+	"github.com/getsops/sops/v3/aes"
+	sopsage "github.com/getsops/sops/v3/age"
+	"github.com/getsops/sops/v3/cmd/sops/common"
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	sopsconfig "github.com/getsops/sops/v3/config"
+	"github.com/getsops/sops/v3/keys"
+	"github.com/getsops/sops/v3/keyservice"
+
+	"github.com/efixler/config"
+)
+
+// encryptFile writes plaintext to name in a temporary directory, encrypted with sops for recipient, as
+// `sops --encrypt --age RECIPIENT --unencrypted-suffix _unencrypted` would.
+func encryptFile(t *testing.T, name, plaintext, recipient string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	store := common.StoreForFormat(formats.FormatForPath(path), sopsconfig.NewStoresConfig())
+	branches, err := store.LoadPlainFile([]byte(plaintext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := sopsage.MasterKeyFromRecipient(recipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := sops.Tree{Branches: branches, Metadata: sops.Metadata{
+		KeyGroups:         []sops.KeyGroup{{keys.MasterKey(key)}},
+		UnencryptedSuffix: "_unencrypted",
+		Version:           "3.13.3",
+	}}
+	dataKey, errs := tree.GenerateDataKeyWithKeyServices([]keyservice.KeyServiceClient{keyservice.NewLocalClient()})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if err := common.EncryptTree(common.EncryptTreeOpts{DataKey: dataKey, Tree: &tree, Cipher: aes.NewCipher()}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := store.EmitEncryptedFile(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newIdentity(t *testing.T) *age.X25519Identity {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return identity
+}
+
+func TestSopsGetter(t *testing.T) {
+	identity := newIdentity(t)
+	t.Setenv("SOPS_AGE_KEY", identity.String())
+	tests := map[string]string{
+		"app.yaml": "db:\n  host: db1\n  password: hunter2\nregion_unencrypted: us-east\n",
+		"app.json": `{"db": {"host": "db1", "password": "hunter2"}, "region_unencrypted": "us-east"}`,
+		"app.env":  "db.host=db1\ndb.password=hunter2\nregion_unencrypted=us-east\n",
+	}
+	for name, plaintext := range tests {
+		path := encryptFile(t, name, plaintext, identity.Recipient().String())
+		if data, _ := os.ReadFile(path); strings.Contains(string(data), "hunter2") {
+			t.Fatalf("%s: expected the fixture to be encrypted", name)
+		}
+		g, err := NewSopsGetter(path)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if v := g.Get("db.password"); v != "hunter2" {
+			t.Errorf("%s: expected 'hunter2', got '%s'", name, v)
+		}
+		if v := g.Get("region_unencrypted"); v != "us-east" {
+			t.Errorf("%s: expected 'us-east', got '%s'", name, v)
+		}
+		if !config.IsSecret(g, "db.password") || !config.IsSecret(g, "db.host") {
+			t.Errorf("%s: encrypted values should be secrets", name)
+		}
+		if config.IsSecret(g, "region_unencrypted") {
+			t.Errorf("%s: cleartext values should not be secrets", name)
+		}
+		for _, key := range g.(config.Lister).Keys() {
+			if strings.HasPrefix(key, "sops") {
+				t.Errorf("%s: sops metadata should not be a key: %s", name, key)
+			}
+		}
+		if _, err := config.GetErr(g, "missing"); !errors.Is(err, config.ErrKeyNotSet) {
+			t.Errorf("%s: expected ErrKeyNotSet, got %v", name, err)
+		}
+	}
+}
+
+func TestSopsGetterErrors(t *testing.T) {
+	path := encryptFile(t, "app.yaml", "a: 1\n", newIdentity(t).Recipient().String())
+	t.Setenv("SOPS_AGE_KEY", newIdentity(t).String())
+	if _, err := NewSopsGetter(path); err == nil || !strings.Contains(err.Error(), "decrypting") {
+		t.Errorf("Expected a decryption error with the wrong key, got %v", err)
+	}
+	if _, err := NewSopsGetter(filepath.Join(t.TempDir(), "secret.bin")); err == nil {
+		t.Error("Expected an error for a binary file")
+	}
+	if _, err := NewSopsGetter(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// NewStdinGetter : Read all of stdin, once, as KEY=VALUE lines (in the dotenv style NewExecGetter also
+// uses) and return a Getter holding them. This supports `some-secret-provider | app` bootstrapping.
+// Note that this consumes stdin: it reads to EOF and nothing else in the process can read it afterwards.
+//
+// To avoid hanging on an interactive stdin this returns an error if stdin is a terminal. To read a
+// terminal anyway, force it with NewKeyValueGetter(os.Stdin).
+func NewStdinGetter() (Getter, error) {
+	if isTerminal(os.Stdin) {
+		return nil, errors.New("config: stdin is a terminal, not a KEY=VALUE stream")
+	}
+	return NewKeyValueGetter(os.Stdin)
+}
+
+// NewKeyValueGetter : Read r to EOF as KEY=VALUE lines and return a Getter holding them.
+func NewKeyValueGetter(r io.Reader) (Getter, error) {
+	values, err := parseKeyValues(r)
+	if err != nil {
+		return nil, fmt.Errorf("config: parsing KEY=VALUE input: %w", err)
+	}
+	return Map(values), nil
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
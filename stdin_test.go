@@ -0,0 +1,31 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNewKeyValueGetter(t *testing.T) {
+	c, err := NewKeyValueGetter(strings.NewReader("A=1\n# comment\nB='two words'\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.Get("A") != "1" || c.Get("B") != "two words" {
+		t.Errorf("Unexpected values A='%s' B='%s'", c.Get("A"), c.Get("B"))
+	}
+	if _, err := NewKeyValueGetter(strings.NewReader("A=1\nnonsense\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected a parse error naming line 2, got %v", err)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if isTerminal(f) {
+		t.Error("A regular file should not be reported as a terminal")
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StringerOption changes how GetStringer matches values.
+type StringerOption func(*stringerOptions)
+
+type stringerOptions struct {
+	ignoreCase bool
+}
+
+// IgnoreCase : Make GetStringer match values case-insensitively. An exact match still wins, and a value that
+// matches more than one of the choices without regard to case is an error.
+func IgnoreCase() StringerOption {
+	return func(o *stringerOptions) {
+		o.ignoreCase = true
+	}
+}
+
+// GetStringer : Return the element of values whose String() is key's value, so that a config enum is checked
+// against the Go enum type itself instead of a separate list of allowed strings that can drift from it:
+//
+//	level, err := config.GetStringer(g, "LOG_LEVEL", []Level{Debug, Info, Warn})
+//
+// Matching is exact unless IgnoreCase() is passed. A value that matches none of them is an error listing the
+// valid ones. An unset (or empty) key returns the zero T and an error wrapping ErrKeyNotSet, which callers
+// with a default can check for with errors.Is.
+func GetStringer[T fmt.Stringer](g Getter, key string, values []T, opts ...StringerOption) (T, error) {
+	var o stringerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var zero T
+	raw, err := GetErr(g, key)
+	if err != nil {
+		return zero, err
+	} else if raw == "" {
+		return zero, fmt.Errorf("config: %s: %w", key, ErrKeyNotSet)
+	}
+	names := make([]string, len(values))
+	var folded []T
+	for i, v := range values {
+		names[i] = v.String()
+		if names[i] == raw {
+			return v, nil
+		} else if o.ignoreCase && strings.EqualFold(names[i], raw) {
+			folded = append(folded, v)
+		}
+	}
+	switch len(folded) {
+	case 1:
+		return folded[0], nil
+	case 0:
+		return zero, fmt.Errorf("config: %s value %s is not one of %s", key, displayValue(g, key, raw), strings.Join(names, ", "))
+	default:
+		return zero, fmt.Errorf("config: %s value %s matches more than one of %s", key, displayValue(g, key, raw), strings.Join(names, ", "))
+	}
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testLevel int
+
+func (l testLevel) String() string {
+	return [...]string{"debug", "info", "warn", "Warn"}[l]
+}
+
+func TestGetStringer(t *testing.T) {
+	levels := []testLevel{0, 1, 2}
+	g := Map{"LEVEL": "info", "UPPER": "INFO", "BAD": "loud", "WARN": "WARN"}
+	if v, err := GetStringer(g, "LEVEL", levels); err != nil || v != 1 {
+		t.Errorf("Expected info, got %v (%v)", v, err)
+	}
+	if _, err := GetStringer(g, "UPPER", levels); err == nil {
+		t.Error("Expected matching to be case-sensitive by default")
+	}
+	if v, err := GetStringer(g, "UPPER", levels, IgnoreCase()); err != nil || v != 1 {
+		t.Errorf("Expected a case-insensitive match, got %v (%v)", v, err)
+	}
+	_, err := GetStringer(g, "BAD", levels)
+	if err == nil || !strings.Contains(err.Error(), "debug, info, warn") {
+		t.Errorf("Expected the error to list the valid values, got %v", err)
+	}
+	if _, err := GetStringer(g, "MISSING", levels); !errors.Is(err, ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+	if _, err := GetStringer(g, "WARN", []testLevel{2, 3}, IgnoreCase()); err == nil || !strings.Contains(err.Error(), "more than one") {
+		t.Errorf("Expected an ambiguous match error, got %v", err)
+	}
+	if v, err := GetStringer(Map{"L": "Warn"}, "L", []testLevel{2, 3}, IgnoreCase()); err != nil || v != 3 {
+		t.Errorf("Expected the exact match to win, got %v (%v)", v, err)
+	}
+}
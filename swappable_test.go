@@ -0,0 +1,53 @@
+package config
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSwappableGetter(t *testing.T) {
+	s := NewSwappable(Map{"HOST": "db1", "PORT": "5432"})
+	watch := s.Watch()
+	snap := s.Snapshot()
+	old := s.Swap(Map{"HOST": "db2", "PORT": "6432"})
+	if v := old.Get("HOST"); v != "db1" {
+		t.Errorf("Expected the previous getter back, got '%s'", v)
+	}
+	if v := s.Get("HOST"); v != "db2" {
+		t.Errorf("Expected 'db2' after the swap, got '%s'", v)
+	}
+	if v := snap.Get("HOST"); v != "db1" {
+		t.Errorf("Expected the snapshot to keep 'db1', got '%s'", v)
+	}
+	if keys := s.Keys(); len(keys) != 2 {
+		t.Errorf("Unexpected keys %q", keys)
+	}
+	select {
+	case <-watch:
+	default:
+		t.Error("Expected a notification")
+	}
+
+	// Readers racing with swaps see one getter or the other, never a mix.
+	a, b := Map{"HOST": "a", "PORT": "a"}, Map{"HOST": "b", "PORT": "b"}
+	s.Swap(a)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			if i%2 == 0 {
+				s.Swap(a)
+			} else {
+				s.Swap(b)
+			}
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		batch := Consistent(s).GetBatch("HOST", "PORT")
+		if batch["HOST"] != batch["PORT"] {
+			t.Fatalf("Torn read: %v", batch)
+		}
+	}
+	wg.Wait()
+}
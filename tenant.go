@@ -0,0 +1,64 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoTenant is returned (possibly wrapped) by a TenantScoped() Getter when a lookup has no tenant to scope it to.
+var ErrNoTenant = errors.New("config: no tenant in context")
+
+// tenantSep separates the tenant from the key in the shared backend.
+const tenantSep = "."
+
+// TenantScoped : Wrap a Getter shared by many tenants, each with its own values stored under "TENANT.KEY", so that
+// lookups read only the current tenant's values. The tenant comes from the request context, via tenantKeyFunc:
+//
+//	g := config.TenantScoped(shared, func(ctx context.Context) string { return auth.TenantID(ctx) })
+//	timeout, err := config.GetContext(ctx, g, "TIMEOUT") // reads "acme.TIMEOUT" for tenant acme
+//
+// Lookups never fall back to the unscoped (global) values, so that one tenant can't read config meant for everyone
+// else or another tenant. GetContext fails with an error wrapping ErrNoTenant when tenantKeyFunc returns "" for the
+// Context, or a tenant containing ".", which would let tenant "a" reach tenant "a.b"'s values. Lookups without a
+// Context have no tenant either: GetErr returns ErrNoTenant, Get and GetStrings return empty values, GetOrDefault
+// the default, and MustGet panics. Compose tenant-independent config separately.
+func TenantScoped(g Getter, tenantKeyFunc func(ctx context.Context) string) Getter {
+	return &tenantScoped{g: g, tenant: tenantKeyFunc}
+}
+
+type tenantScoped struct {
+	g      Getter
+	tenant func(context.Context) string
+}
+
+func (t *tenantScoped) GetContext(ctx context.Context, key string) (string, error) {
+	tenant := t.tenant(ctx)
+	if tenant == "" {
+		return "", fmt.Errorf("config: %s: %w", key, ErrNoTenant)
+	} else if strings.Contains(tenant, tenantSep) {
+		return "", fmt.Errorf("config: %s: invalid tenant %q: %w", key, tenant, ErrNoTenant)
+	}
+	return GetContext(ctx, t.g, tenant+tenantSep+key)
+}
+
+func (t *tenantScoped) GetErr(key string) (string, error) {
+	return "", fmt.Errorf("config: %s: %w", key, ErrNoTenant)
+}
+
+func (t *tenantScoped) Get(key string) string {
+	return ""
+}
+
+func (t *tenantScoped) GetOrDefault(key string, dflt string) string {
+	return dflt
+}
+
+func (t *tenantScoped) GetStrings(key string) []string {
+	return SplitStrings("")
+}
+
+func (t *tenantScoped) MustGet(key string) string {
+	return MustGet(t, key)
+}
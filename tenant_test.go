@@ -0,0 +1,39 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type tenantCtxKey struct{}
+
+func TestTenantScoped(t *testing.T) {
+	g := TenantScoped(Map{
+		"TIMEOUT":      "global",
+		"acme.TIMEOUT": "5s",
+		"beta.TIMEOUT": "10s",
+		"a.b.TIMEOUT":  "leak",
+	}, func(ctx context.Context) string {
+		tenant, _ := ctx.Value(tenantCtxKey{}).(string)
+		return tenant
+	})
+	for tenant, expected := range map[string]string{"acme": "5s", "beta": "10s", "gamma": ""} {
+		ctx := context.WithValue(context.Background(), tenantCtxKey{}, tenant)
+		if v, err := GetContext(ctx, g, "TIMEOUT"); err != nil || v != expected {
+			t.Errorf("Expected '%s' for tenant %s, got '%s' (%v)", expected, tenant, v, err)
+		}
+	}
+	if _, err := GetContext(context.Background(), g, "TIMEOUT"); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("Expected ErrNoTenant without a tenant, got %v", err)
+	}
+	if _, err := GetContext(context.WithValue(context.Background(), tenantCtxKey{}, "a.b"), g, "TIMEOUT"); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("Expected an invalid tenant error, got %v", err)
+	}
+	if _, err := GetErr(g, "TIMEOUT"); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("Expected ErrNoTenant from GetErr, got %v", err)
+	}
+	if v := g.GetOrDefault("TIMEOUT", "1s"); v != "1s" {
+		t.Errorf("Expected the default rather than the global value, got '%s'", v)
+	}
+}
@@ -0,0 +1,75 @@
+// Package tfc provides a config.Getter over the variables of a Terraform Cloud (or Terraform Enterprise)
+// workspace, so that apps can read the same variables their infrastructure uses.
+//
+// The Terraform Cloud API client is abstracted behind TFCClient, so this package doesn't depend on any particular
+// client library; a few lines wrapping go-tfe's Variables.List (following its pagination) will do.
+package tfc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/efixler/config"
+)
+
+// Variable categories, as reported by the Terraform Cloud API.
+const (
+	CategoryTerraform = "terraform"
+	CategoryEnv       = "env"
+)
+
+// Variable is a workspace variable.
+type Variable struct {
+	Key       string
+	Value     string
+	Category  string
+	Sensitive bool
+}
+
+// TFCClient lists the variables of a workspace.
+type TFCClient interface {
+	ListVariables(ctx context.Context, workspaceID string) ([]Variable, error)
+}
+
+// NewTFCVarsGetter : Fetch the variables of a workspace once and return a Getter exposing each one as a config key.
+// If categories are passed (CategoryTerraform, CategoryEnv), only variables in those categories are included.
+// API errors are returned from here.
+//
+// Sensitive variables are marked as secrets for the redaction helpers (see config.SecretMarker). Keep in mind
+// that Terraform Cloud never returns the values of sensitive variables, so they're present with an empty value.
+func NewTFCVarsGetter(client TFCClient, workspaceID string, categories ...string) (config.Getter, error) {
+	return NewTFCVarsGetterContext(context.Background(), client, workspaceID, categories...)
+}
+
+// NewTFCVarsGetterContext : NewTFCVarsGetter, with a Context for the API calls.
+func NewTFCVarsGetterContext(ctx context.Context, client TFCClient, workspaceID string, categories ...string) (config.Getter, error) {
+	vars, err := client.ListVariables(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("tfc: listing variables of workspace %s: %w", workspaceID, err)
+	}
+	wanted := make(map[string]bool, len(categories))
+	for _, c := range categories {
+		wanted[c] = true
+	}
+	g := &varsGetter{Map: make(config.Map), sensitive: make(map[string]bool)}
+	for _, v := range vars {
+		if len(wanted) > 0 && !wanted[v.Category] {
+			continue
+		}
+		g.Map[v.Key] = v.Value
+		if v.Sensitive {
+			g.sensitive[v.Key] = true
+		}
+	}
+	return g, nil
+}
+
+type varsGetter struct {
+	config.Map
+	sensitive map[string]bool
+}
+
+// IsSecret : Report whether key is a sensitive workspace variable.
+func (g *varsGetter) IsSecret(key string) bool {
+	return g.sensitive[key]
+}
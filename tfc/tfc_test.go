@@ -0,0 +1,42 @@
+package tfc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/efixler/config"
+)
+
+type fakeClient []Variable
+
+func (f fakeClient) ListVariables(context.Context, string) ([]Variable, error) {
+	if f == nil {
+		return nil, errors.New("unauthorized")
+	}
+	return f, nil
+}
+
+func TestTFCVarsGetter(t *testing.T) {
+	client := fakeClient{
+		{Key: "region", Value: "us-east-1", Category: CategoryTerraform},
+		{Key: "LOG_LEVEL", Value: "debug", Category: CategoryEnv},
+		{Key: "DB_PASSWORD", Category: CategoryEnv, Sensitive: true},
+	}
+	c, err := NewTFCVarsGetter(client, "ws-123", CategoryEnv)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := c.Get("LOG_LEVEL"); v != "debug" {
+		t.Errorf("Expected 'debug', got '%s'", v)
+	}
+	if v := c.Get("region"); v != "" {
+		t.Errorf("Terraform variables should be filtered out, got '%s'", v)
+	}
+	if !config.IsSecret(c, "DB_PASSWORD") || config.IsSecret(c, "LOG_LEVEL") {
+		t.Error("Expected only DB_PASSWORD to be marked secret")
+	}
+	if _, err := NewTFCVarsGetter(fakeClient(nil), "ws-123"); err == nil {
+		t.Error("Expected API errors to be returned")
+	}
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ValidateTLSKeyPair : Load the certificate (chain) in certKey and the private key in keyKey, and check that they're
+// well-formed PEM and belong together, so broken TLS material fails at startup instead of on the first connection.
+//
+// Each value is taken as inline PEM if it contains a "-----BEGIN" line, and as the path of a PEM file otherwise.
+// If a key is unset, its key+"_FILE" is used as well (see WithFileIndirection), so CERT_FILE=/run/secrets/cert works
+// too. Errors name the keys, never the key material.
+func ValidateTLSKeyPair(g Getter, certKey, keyKey string) (tls.Certificate, error) {
+	g = WithFileIndirection(g)
+	certPEM, err := tlsMaterial(g, certKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyPEM, err := tlsMaterial(g, keyKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("config: %s and %s are not a valid key pair: %w", certKey, keyKey, err)
+	}
+	return cert, nil
+}
+
+// tlsMaterial returns the PEM for key, reading it from a file if the value is a path.
+func tlsMaterial(g Getter, key string) ([]byte, error) {
+	val, err := GetErr(g, key)
+	if err != nil {
+		return nil, err
+	}
+	if val == "" {
+		return nil, fmt.Errorf("config: %s: %w", key, ErrKeyNotSet)
+	}
+	if strings.Contains(val, "-----BEGIN") {
+		return []byte(val), nil
+	}
+	data, err := os.ReadFile(val)
+	if err != nil {
+		return nil, fmt.Errorf("config: %s: %w", key, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// selfSigned returns a PEM certificate and key for a throwaway self-signed cert.
+func selfSigned(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "config test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestValidateTLSKeyPair(t *testing.T) {
+	cert, key := selfSigned(t)
+	_, otherKey := selfSigned(t)
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "key.pem")
+	os.WriteFile(keyPath, []byte(key), 0600)
+
+	g := Map{
+		"TLS_CERT":       cert,
+		"TLS_KEY":        keyPath,
+		"TLS_OTHER_KEY":  otherKey,
+		"TLS_CERT2_FILE": filepath.Join(dir, "cert.pem"),
+		"TLS_BAD":        "-----BEGIN CERTIFICATE-----\nnope\n-----END CERTIFICATE-----\n",
+	}
+	os.WriteFile(filepath.Join(dir, "cert.pem"), []byte(cert), 0600)
+	for _, certKey := range []string{"TLS_CERT", "TLS_CERT2"} {
+		if c, err := ValidateTLSKeyPair(g, certKey, "TLS_KEY"); err != nil || len(c.Certificate) != 1 {
+			t.Errorf("%s: unexpected result %v", certKey, err)
+		}
+	}
+	_, err := ValidateTLSKeyPair(g, "TLS_CERT", "TLS_OTHER_KEY")
+	if err == nil || !strings.Contains(err.Error(), "TLS_OTHER_KEY") || strings.Contains(err.Error(), "PRIVATE") {
+		t.Errorf("Expected a mismatch error naming the key, got %v", err)
+	}
+	if _, err := ValidateTLSKeyPair(g, "TLS_BAD", "TLS_KEY"); err == nil {
+		t.Error("Expected an error for malformed PEM")
+	}
+	if _, err := ValidateTLSKeyPair(g, "TLS_MISSING", "TLS_KEY"); !errors.Is(err, ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+}
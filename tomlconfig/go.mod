@@ -0,0 +1,10 @@
+module github.com/efixler/config/tomlconfig
+
+go 1.22
+
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/efixler/config v0.0.0-00010101000000-000000000000
+)
+
+replace github.com/efixler/config => ../
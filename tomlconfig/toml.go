@@ -0,0 +1,69 @@
+// Package tomlconfig provides a config Getter over TOML documents. Importing it registers the "toml" format
+// (see config.RegisterFormat), so the getters that parse documents by format name, and config.NewFileGetter for
+// .toml files, can read TOML too. It's a separate package to keep the TOML dependency optional.
+package tomlconfig
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/efixler/config"
+)
+
+func init() {
+	config.RegisterFormat("toml", func(data []byte) (map[string]string, error) {
+		return ParseTOML(data)
+	})
+}
+
+// NewTOMLGetter : Return a Getter over the TOML document at path, flattened with config.FlattenValue: tables
+// become dot-separated keys ("[db] host" is "db.host"), arrays of scalars are comma-joined, and arrays of tables
+// are keyed by index ("servers.0.host"). The file is read once.
+func NewTOMLGetter(path string) (config.Getter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("tomlconfig: %w", err)
+	}
+	values, err := ParseTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("tomlconfig: %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// ParseTOML : Parse TOML data as described for NewTOMLGetter. Dates and times are formatted as RFC 3339.
+func ParseTOML(data []byte) (config.Map, error) {
+	var doc map[string]any
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	rval := make(config.Map)
+	config.FlattenValue(rval, "", normalize(doc))
+	return rval, nil
+}
+
+// normalize formats TOML datetimes, which FlattenValue would otherwise stringify with time.Time.String().
+func normalize(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, val := range v {
+			v[key] = normalize(val)
+		}
+	case []map[string]any:
+		rval := make([]any, len(v))
+		for i, val := range v {
+			rval[i] = normalize(val)
+		}
+		return rval
+	case []any:
+		for i, val := range v {
+			v[i] = normalize(val)
+		}
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return v
+}
@@ -0,0 +1,69 @@
+package tomlconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/efixler/config"
+)
+
+const testTOML = `
+title = "app"
+debug = true
+
+[db]
+host = "db1"
+port = 5432
+ratio = 0.25
+started = 1979-05-27T07:32:00Z
+hosts = ["a", "b"]
+
+[[servers]]
+name = "alpha"
+
+[[servers]]
+name = "beta"
+`
+
+func TestParseTOML(t *testing.T) {
+	m, err := ParseTOML([]byte(testTOML))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := config.Map{
+		"title":          "app",
+		"debug":          "true",
+		"db.host":        "db1",
+		"db.port":        "5432",
+		"db.ratio":       "0.25",
+		"db.started":     "1979-05-27T07:32:00Z",
+		"db.hosts":       "a,b",
+		"servers.0.name": "alpha",
+		"servers.1.name": "beta",
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("Expected %v, got %v", expected, m)
+	}
+	if _, err := ParseTOML([]byte("title = ")); err == nil {
+		t.Error("Expected a parse error")
+	}
+}
+
+func TestNewTOMLGetter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.toml")
+	if err := os.WriteFile(path, []byte(testTOML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	g, err := NewTOMLGetter(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := g.GetStrings("db.hosts"); !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %q", v)
+	}
+	if m, err := config.ParseFormat("toml", []byte(testTOML)); err != nil || m.Get("db.host") != "db1" {
+		t.Errorf("Expected the toml format to be registered, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+// Package tracing provides a config.Getter decorator that records remote config lookups as OpenTelemetry spans,
+// so that slow config backends show up in the request timeline.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/efixler/config"
+)
+
+// SpanName is the name of the spans started by WithTracing.
+const SpanName = "config.Get"
+
+// KeyAttribute is the span attribute holding the config key.
+const KeyAttribute = attribute.Key("config.key")
+
+// WithTracing : Wrap g so that lookups through config.GetContext() start a span (named SpanName, with the key
+// as KeyAttribute) under the Context's current span, recording any error on it.
+//
+// Only getters that are config.ContextGetters, i.e. remote backends, are traced; lookups on local getters
+// (Env, Map, files) are passed through without creating spans to avoid the noise. The Context-free accessors
+// (Get, GetStrings, ...) are never traced, since they have no span to attach to.
+func WithTracing(g config.Getter, tracer trace.Tracer) config.Getter {
+	return &traced{Getter: g, tracer: tracer}
+}
+
+type traced struct {
+	config.Getter
+	tracer trace.Tracer
+}
+
+// GetContext : Look up key in a span, if the wrapped Getter is a config.ContextGetter.
+func (t *traced) GetContext(ctx context.Context, key string) (string, error) {
+	cg, ok := t.Getter.(config.ContextGetter)
+	if !ok {
+		return config.GetErr(t.Getter, key)
+	}
+	ctx, span := t.tracer.Start(ctx, SpanName, trace.WithAttributes(KeyAttribute.String(key)))
+	defer span.End()
+	val, err := cg.GetContext(ctx, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return val, err
+}
+
+// GetErr : Passed through to the wrapped Getter, untraced.
+func (t *traced) GetErr(key string) (string, error) {
+	return config.GetErr(t.Getter, key)
+}
@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/efixler/config"
+)
+
+type remote struct {
+	config.Map
+}
+
+func (r remote) GetContext(_ context.Context, key string) (string, error) {
+	if v, ok := r.Lookup(key); ok {
+		return v, nil
+	}
+	return "", config.ErrKeyNotSet
+}
+
+func TestWithTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	ctx := context.Background()
+
+	c := WithTracing(remote{config.Map{"HOST": "db"}}, tracer)
+	if v, err := config.GetContext(ctx, c, "HOST"); err != nil || v != "db" {
+		t.Errorf("Expected 'db', got '%s' (%v)", v, err)
+	}
+	if _, err := config.GetContext(ctx, c, "MISSING"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	if attrs := spans[0].Attributes(); len(attrs) != 1 || attrs[0].Value.AsString() != "HOST" {
+		t.Errorf("Expected the key as an attribute, got %v", attrs)
+	}
+	if len(spans[1].Events()) != 1 {
+		t.Error("Expected the error to be recorded on the span")
+	}
+
+	local := WithTracing(config.Map{"HOST": "db"}, tracer)
+	config.GetContext(ctx, local, "HOST")
+	if len(recorder.Ended()) != 2 {
+		t.Error("Local getters should not create spans")
+	}
+}
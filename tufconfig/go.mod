@@ -0,0 +1,16 @@
+module github.com/efixler/config/tufconfig
+
+go 1.22
+
+require (
+	github.com/efixler/config v0.0.0-00010101000000-000000000000
+	github.com/theupdateframework/go-tuf v0.7.0
+)
+
+require (
+	github.com/secure-systems-lab/go-securesystemslib v0.7.0 // indirect
+	golang.org/x/crypto v0.16.0 // indirect
+	golang.org/x/sys v0.15.0 // indirect
+)
+
+replace github.com/efixler/config => ../
@@ -0,0 +1,149 @@
+// Package tufconfig provides a config.Getter over a config file distributed through a TUF (The Update Framework)
+// repository, so tampering anywhere between the publisher and the service is detected before the config is used.
+// It's a separate package to keep the TUF dependency out of programs that don't need it.
+package tufconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/theupdateframework/go-tuf/client"
+
+	"github.com/efixler/config"
+)
+
+// Target describes the verified target a Getter was loaded from, for audit logs.
+type Target struct {
+	Path string
+	// Length is the verified size of the target, in bytes.
+	Length int64
+	// Hashes maps hash algorithms (e.g. "sha512") to the target's verified hex digests.
+	Hashes map[string]string
+	// TargetsVersion is the version of the signed targets metadata that listed the target.
+	TargetsVersion int64
+}
+
+// Getter holds the values of a verified target. It's a config.Map with the Target it was loaded from.
+type Getter struct {
+	config.Map
+	Target Target
+}
+
+// NewTUFGetter : Fetch the TUF metadata from metadataURL (root.json, timestamp.json and so on live directly under
+// it), verify it starting from trustedRoot, then download targetPath from under targetsURL, check it against the
+// signed length and hashes, and parse it with the config format named by its extension (e.g. "json" or "ini";
+// see config.RegisterFormat), or as KEY=VALUE lines if it has none.
+//
+// This fails closed: any verification failure (bad signatures, expired or rolled-back metadata, a target that isn't
+// listed, or whose contents don't match) is returned as an error, and no values are used.
+//
+// trustedRoot is the trust bootstrap: the contents of a root.json obtained out of band, typically baked into the
+// image or delivered by the deployment system, never fetched from the same place as the metadata. The client starts
+// from it and follows any root key rotations published in the repository. Everything else is verified against it.
+func NewTUFGetter(metadataURL, targetsURL, targetPath string, trustedRoot []byte) (*Getter, error) {
+	return newTUFGetter(http.DefaultClient, metadataURL, targetsURL, targetPath, trustedRoot)
+}
+
+func newTUFGetter(hc *http.Client, metadataURL, targetsURL, targetPath string, trustedRoot []byte) (*Getter, error) {
+	local := client.MemoryLocalStore()
+	c := client.NewClient(local, &httpRemote{client: hc, metadataURL: metadataURL, targetsURL: targetsURL})
+	if err := c.Init(trustedRoot); err != nil {
+		return nil, fmt.Errorf("tufconfig: loading trusted root: %w", err)
+	}
+	targets, err := c.Update()
+	if err != nil {
+		return nil, fmt.Errorf("tufconfig: updating metadata from %s: %w", metadataURL, err)
+	}
+	meta, ok := targets[targetPath]
+	if !ok {
+		return nil, fmt.Errorf("tufconfig: %s is not a signed target", targetPath)
+	}
+	var buf destination
+	if err := c.Download(targetPath, &buf); err != nil {
+		return nil, fmt.Errorf("tufconfig: downloading %s: %w", targetPath, err)
+	}
+	format := strings.TrimPrefix(path.Ext(targetPath), ".")
+	if format == "" {
+		format = "env"
+	}
+	values, err := config.ParseFormat(format, buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("tufconfig: %s: %w", targetPath, err)
+	}
+	target := Target{Path: targetPath, Length: meta.Length, Hashes: make(map[string]string, len(meta.Hashes))}
+	for alg, digest := range meta.Hashes {
+		target.Hashes[alg] = digest.String()
+	}
+	if target.TargetsVersion, err = targetsVersion(local); err != nil {
+		return nil, err
+	}
+	return &Getter{Map: values, Target: target}, nil
+}
+
+// targetsVersion reads the version of the verified targets metadata from the client's store.
+func targetsVersion(local client.LocalStore) (int64, error) {
+	meta, err := local.GetMeta()
+	if err != nil {
+		return 0, fmt.Errorf("tufconfig: %w", err)
+	}
+	var targets struct {
+		Signed struct {
+			Version int64 `json:"version"`
+		} `json:"signed"`
+	}
+	if err := json.Unmarshal(meta["targets.json"], &targets); err != nil {
+		return 0, fmt.Errorf("tufconfig: reading targets version: %w", err)
+	}
+	return targets.Signed.Version, nil
+}
+
+// destination collects a download in memory. go-tuf calls Delete when verification fails.
+type destination struct {
+	bytes.Buffer
+}
+
+func (d *destination) Delete() error {
+	d.Reset()
+	return nil
+}
+
+// httpRemote is a client.RemoteStore with metadata and targets under separate base URLs.
+type httpRemote struct {
+	client      *http.Client
+	metadataURL string
+	targetsURL  string
+}
+
+func (r *httpRemote) GetMeta(name string) (io.ReadCloser, int64, error) {
+	return r.get(r.metadataURL, name)
+}
+
+func (r *httpRemote) GetTarget(name string) (io.ReadCloser, int64, error) {
+	return r.get(r.targetsURL, name)
+}
+
+func (r *httpRemote) get(base string, name string) (io.ReadCloser, int64, error) {
+	u, err := url.JoinPath(base, name)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := r.client.Get(u)
+	if err != nil {
+		return nil, 0, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, 0, client.ErrNotFound{File: name}
+	case resp.StatusCode != http.StatusOK:
+		resp.Body.Close()
+		return nil, 0, fmt.Errorf("unexpected status %s fetching %s", resp.Status, u)
+	}
+	return resp.Body, resp.ContentLength, nil
+}
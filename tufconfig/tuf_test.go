@@ -0,0 +1,81 @@
+package tufconfig
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tuf "github.com/theupdateframework/go-tuf"
+)
+
+// newRepo builds a signed TUF repository holding files, returning its metadata.
+func newRepo(t *testing.T, files map[string][]byte) map[string]json.RawMessage {
+	meta := make(map[string]json.RawMessage)
+	r, err := tuf.NewRepo(tuf.MemoryStore(meta, files))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := r.Init(false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, role := range []string{"root", "targets", "snapshot", "timestamp"} {
+		if _, err := r.GenKey(role); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	for name := range files {
+		if err := r.AddTarget(name, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	for _, step := range []func() error{r.Snapshot, r.Timestamp, r.Commit} {
+		if err := step(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	return meta
+}
+
+func serve(meta map[string]json.RawMessage, files map[string][]byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if name, ok := strings.CutPrefix(r.URL.Path, "/metadata/"); ok && meta[name] != nil {
+			w.Write(meta[name])
+		} else if name, ok := strings.CutPrefix(r.URL.Path, "/targets/"); ok && files[name] != nil {
+			w.Write(files[name])
+		} else {
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestTUFGetter(t *testing.T) {
+	files := map[string][]byte{"app.json": []byte(`{"db": {"host": "db1"}}`)}
+	meta := newRepo(t, files)
+	srv := serve(meta, files)
+	defer srv.Close()
+
+	g, err := newTUFGetter(srv.Client(), srv.URL+"/metadata", srv.URL+"/targets", "app.json", meta["root.json"])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := g.Get("db.host"); v != "db1" {
+		t.Errorf("Expected 'db1', got '%s'", v)
+	}
+	if g.Target.Length != int64(len(files["app.json"])) || len(g.Target.Hashes["sha512"]) != 128 || g.Target.TargetsVersion != 1 {
+		t.Errorf("Unexpected target %+v", g.Target)
+	}
+	if _, err := newTUFGetter(srv.Client(), srv.URL+"/metadata", srv.URL+"/targets", "other.json", meta["root.json"]); err == nil {
+		t.Error("Expected an error for an unsigned target")
+	}
+
+	files["app.json"] = []byte(`{"db": {"host": "evil"}}`)
+	if _, err := newTUFGetter(srv.Client(), srv.URL+"/metadata", srv.URL+"/targets", "app.json", meta["root.json"]); err == nil {
+		t.Error("Expected an error for a tampered target")
+	}
+	other := newRepo(t, map[string][]byte{"app.json": []byte(`{}`)})
+	if _, err := newTUFGetter(srv.Client(), srv.URL+"/metadata", srv.URL+"/targets", "app.json", other["root.json"]); err == nil {
+		t.Error("Expected an error with an untrusted root")
+	}
+}
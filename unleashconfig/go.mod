@@ -0,0 +1,20 @@
+module github.com/efixler/config/unleashconfig
+
+go 1.22
+
+require (
+	github.com/Unleash/unleash-client-go/v4 v4.5.0
+	github.com/efixler/config v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/Masterminds/semver/v3 v3.3.1 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/stretchr/objx v0.5.2 // indirect
+	github.com/stretchr/testify v1.10.0 // indirect
+	github.com/twmb/murmur3 v1.1.8 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/efixler/config => ../
@@ -0,0 +1,52 @@
+package unleashconfig
+
+import (
+	"fmt"
+
+	"github.com/Unleash/unleash-client-go/v4"
+	unleashcontext "github.com/Unleash/unleash-client-go/v4/context"
+)
+
+// sdkClient adapts an *unleash.Client to Client.
+type sdkClient struct {
+	c *unleash.Client
+}
+
+func (s sdkClient) IsEnabled(toggle string, ec EvalContext) (bool, error) {
+	// The SDK only falls back for a toggle it doesn't have, so the fallback is how an unknown toggle is spotted.
+	unknown := false
+	enabled := s.c.IsEnabled(toggle, unleash.WithContext(toUnleashContext(ec)),
+		unleash.WithFallbackFunc(func(string, *unleashcontext.Context) bool {
+			unknown = true
+			return false
+		}))
+	if unknown {
+		return false, fmt.Errorf("%s: %w", toggle, ErrUnknownToggle)
+	}
+	return enabled, nil
+}
+
+// GetVariant returns the disabled variant for an unknown toggle, which the Getter treats the same way.
+func (s sdkClient) GetVariant(toggle string, ec EvalContext) (Variant, error) {
+	v := s.c.GetVariant(toggle, unleash.WithVariantContext(toUnleashContext(ec)))
+	return Variant{Name: v.Name, Enabled: v.Enabled, Payload: v.Payload.Value}, nil
+}
+
+// toUnleashContext converts ec to the SDK's context, which the client merges with its static fields.
+func toUnleashContext(ec EvalContext) unleashcontext.Context {
+	var properties map[string]string
+	if ec.Properties != nil {
+		properties = make(map[string]string, len(ec.Properties))
+		for key, val := range ec.Properties {
+			properties[key] = val
+		}
+	}
+	return unleashcontext.Context{
+		UserId:        ec.UserID,
+		SessionId:     ec.SessionID,
+		RemoteAddress: ec.RemoteAddress,
+		Environment:   ec.Environment,
+		AppName:       ec.AppName,
+		Properties:    properties,
+	}
+}
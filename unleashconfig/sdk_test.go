@@ -0,0 +1,67 @@
+package unleashconfig
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Unleash/unleash-client-go/v4"
+
+	"github.com/efixler/config"
+)
+
+// toggles rolls "new-checkout" out to user 42, with a single variant carrying a payload.
+const toggles = `{"version": 1, "features": [{
+	"name": "new-checkout",
+	"enabled": true,
+	"strategies": [{"name": "userWithId", "parameters": {"userIds": "42"}}],
+	"variants": [{"name": "eu", "weight": 1000, "weightType": "variable", "stickiness": "default",
+		"payload": {"type": "string", "value": "blue"}}]
+}]}`
+
+func TestUnleashGetterSDK(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/client/features" {
+			w.Write([]byte(toggles))
+		}
+	}))
+	defer server.Close()
+	client, err := unleash.NewClient(
+		unleash.WithUrl(server.URL),
+		unleash.WithAppName("config-test"),
+		unleash.WithBackupPath(t.TempDir()),
+		unleash.WithDisableMetrics(true),
+		unleash.WithRefreshInterval(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+	select {
+	case <-client.Ready():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the client to fetch the toggles")
+	}
+
+	g := NewUnleashGetter(client)
+	ctx := WithEvalContext(context.Background(), EvalContext{UserID: "42"})
+	if on, err := g.GetBool(ctx, "new-checkout", false); err != nil || !on {
+		t.Errorf("Expected the toggle to be on for user 42, got %v (%v)", on, err)
+	}
+	if on, err := g.GetBool(context.Background(), "new-checkout", true); err != nil || on {
+		t.Errorf("Expected the toggle to be off without a user, got %v (%v)", on, err)
+	}
+	if v, err := config.GetContext(ctx, g, "new-checkout"); err != nil || v != "blue" {
+		t.Errorf("Expected variant payload 'blue', got '%s' (%v)", v, err)
+	}
+	if on, err := g.GetBool(ctx, "missing", true); !on || !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected the default and ErrKeyNotSet for an unknown toggle, got %v (%v)", on, err)
+	}
+	if _, err := g.GetErr("missing"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet for an unknown toggle, got %v", err)
+	}
+}
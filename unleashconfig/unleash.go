@@ -0,0 +1,135 @@
+// Package unleashconfig provides a config.Getter over Unleash feature toggles, so feature-gated code reads rollout
+// state the same way it reads the rest of its config. It's a separate module to keep the Unleash SDK optional.
+package unleashconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Unleash/unleash-client-go/v4"
+
+	"github.com/efixler/config"
+)
+
+// ErrUnknownToggle should be returned (possibly wrapped) by a Client for a toggle that doesn't exist.
+// The Getter reports it as an error wrapping config.ErrKeyNotSet.
+var ErrUnknownToggle = errors.New("unleashconfig: unknown toggle")
+
+// EvalContext is the Unleash context that toggles are evaluated in, for targeting and gradual rollouts.
+type EvalContext struct {
+	UserID        string
+	SessionID     string
+	RemoteAddress string
+	Environment   string
+	AppName       string
+	Properties    map[string]string
+}
+
+// Variant is the result of evaluating a toggle's variants.
+type Variant struct {
+	Name string
+	// Enabled is false when the toggle is off or has no variants; Unleash calls that the "disabled" variant.
+	Enabled bool
+	Payload string
+}
+
+// Client evaluates Unleash toggles. NewUnleashGetter adapts an *unleash.Client to it; implement it directly to put
+// something else (a test double, or a client with extra caching) behind the Getter.
+type Client interface {
+	IsEnabled(toggle string, ec EvalContext) (bool, error)
+	GetVariant(toggle string, ec EvalContext) (Variant, error)
+}
+
+type evalContextKey struct{}
+
+// WithEvalContext : Return a copy of ctx carrying ec, for the Getter's context-aware accessors.
+func WithEvalContext(ctx context.Context, ec EvalContext) context.Context {
+	return context.WithValue(ctx, evalContextKey{}, ec)
+}
+
+func evalContextFrom(ctx context.Context) EvalContext {
+	ec, _ := ctx.Value(evalContextKey{}).(EvalContext)
+	return ec
+}
+
+// Getter evaluates toggles as config values. It's a config.ErrorGetter and a config.ContextGetter.
+type Getter struct {
+	client Client
+}
+
+// NewUnleashGetter : Return a Getter that evaluates toggles with client, where Get(key) is the payload of the
+// variant that toggle key evaluates to, and GetBool(ctx, key, dflt) is whether the toggle is enabled.
+//
+// For targeting, use config.GetContext (or GetBool) with a Context from WithEvalContext; the Context-free accessors
+// evaluate with an empty EvalContext (the client's static fields, like the app name, still apply).
+//
+// A toggle that doesn't exist, or one evaluating to the disabled variant, is an error wrapping config.ErrKeyNotSet, so
+// GetOrDefault and friends supply the default. Client errors are returned by GetErr and GetContext; Get returns ""
+// and logs them, and GetBool returns dflt with them. (The Unleash SDK itself doesn't return errors from evaluation:
+// it serves the toggles it last fetched, and reports fetch failures through its listener.)
+func NewUnleashGetter(client *unleash.Client) *Getter {
+	return NewClientGetter(sdkClient{client})
+}
+
+// NewClientGetter : Like NewUnleashGetter, with toggles evaluated by client.
+func NewClientGetter(client Client) *Getter {
+	return &Getter{client: client}
+}
+
+func clientError(key string, err error) error {
+	if errors.Is(err, ErrUnknownToggle) {
+		return fmt.Errorf("unleashconfig: %s: %w", key, config.ErrKeyNotSet)
+	}
+	return fmt.Errorf("unleashconfig: evaluating %s: %w", key, err)
+}
+
+// GetContext : Return the payload of the variant toggle key evaluates to in ctx's EvalContext.
+func (g *Getter) GetContext(ctx context.Context, key string) (string, error) {
+	v, err := g.client.GetVariant(key, evalContextFrom(ctx))
+	if err != nil {
+		return "", clientError(key, err)
+	} else if !v.Enabled {
+		return "", fmt.Errorf("unleashconfig: %s: no enabled variant: %w", key, config.ErrKeyNotSet)
+	}
+	return v.Payload, nil
+}
+
+// GetBool : Return whether toggle key is enabled in ctx's EvalContext, or dflt (and the error) if evaluation fails.
+func (g *Getter) GetBool(ctx context.Context, key string, dflt bool) (bool, error) {
+	enabled, err := g.client.IsEnabled(key, evalContextFrom(ctx))
+	if err != nil {
+		return dflt, clientError(key, err)
+	}
+	return enabled, nil
+}
+
+// GetErr : Return the payload of the variant toggle key evaluates to, without an EvalContext.
+func (g *Getter) GetErr(key string) (string, error) {
+	return g.GetContext(context.Background(), key)
+}
+
+// Get : Return the variant payload for toggle key, or "" if there isn't one or evaluation fails.
+func (g *Getter) Get(key string) string {
+	val, err := g.GetErr(key)
+	if err != nil && !errors.Is(err, config.ErrKeyNotSet) {
+		log.Print(err)
+	}
+	return val
+}
+
+// GetOrDefault : If the requested toggle has no variant payload, return the dflt.
+func (g *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(g, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited variant payload as an []string, stripping whitespace around the commas.
+func (g *Getter) GetStrings(key string) []string {
+	return config.SplitStrings(g.Get(key))
+}
+
+// MustGet will panic if the toggle has no variant payload.
+func (g *Getter) MustGet(key string) string {
+	return config.MustGet(g, key)
+}
@@ -0,0 +1,60 @@
+package unleashconfig
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/efixler/config"
+)
+
+// fakeClient rolls "new-checkout" out to user 42 only, with a variant payload.
+type fakeClient struct {
+	down bool
+}
+
+func (f fakeClient) IsEnabled(toggle string, ec EvalContext) (bool, error) {
+	switch {
+	case f.down:
+		return false, errors.New("unleash unreachable")
+	case toggle != "new-checkout":
+		return false, ErrUnknownToggle
+	}
+	return ec.UserID == "42", nil
+}
+
+func (f fakeClient) GetVariant(toggle string, ec EvalContext) (Variant, error) {
+	enabled, err := f.IsEnabled(toggle, ec)
+	if err != nil || !enabled {
+		return Variant{}, err
+	}
+	return Variant{Name: ec.Properties["region"], Enabled: true, Payload: "blue"}, nil
+}
+
+func TestUnleashGetter(t *testing.T) {
+	g := NewClientGetter(fakeClient{})
+	ctx := WithEvalContext(context.Background(), EvalContext{UserID: "42", Properties: map[string]string{"region": "eu"}})
+	if on, err := g.GetBool(ctx, "new-checkout", false); err != nil || !on {
+		t.Errorf("Expected the toggle to be on for user 42, got %v (%v)", on, err)
+	}
+	if on, _ := g.GetBool(context.Background(), "new-checkout", true); on {
+		t.Error("Expected the toggle to be off without a user")
+	}
+	if v, err := config.GetContext(ctx, g, "new-checkout"); err != nil || v != "blue" {
+		t.Errorf("Expected variant payload 'blue', got '%s' (%v)", v, err)
+	}
+	if v := g.GetOrDefault("new-checkout", "red"); v != "red" {
+		t.Errorf("Expected the default for the disabled variant, got '%s'", v)
+	}
+	if _, err := g.GetErr("missing"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet for an unknown toggle, got %v", err)
+	}
+
+	down := NewClientGetter(fakeClient{down: true})
+	if on, err := down.GetBool(ctx, "new-checkout", true); err == nil || !on {
+		t.Errorf("Expected the default and an error, got %v (%v)", on, err)
+	}
+	if _, err := down.GetErr("new-checkout"); err == nil || errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected a client error, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"net/url"
+)
+
+// WithURLDecode : Wrap a Getter so that the values it returns are percent-decoded. Use this when config arrives
+// through a URL-encoding layer, e.g. a password delivered as "p%40ss".
+//
+// Values are decoded with url.PathUnescape rather than url.QueryUnescape, so a "+" is left as it is instead of
+// being read as a space: "+" is common in secrets and base64 values, and turning it into a space would corrupt
+// them. A space that went through the encoding layer arrives as "%20" and a literal plus as "%2B", and both
+// decode as expected; form-encoded values that use "+" for spaces aren't supported.
+//
+// Decoding never fails: a value that isn't validly encoded is returned raw, so plain values pass
+// through unchanged. GetStrings splits the raw value first and then decodes each element, so an
+// encoded comma ("%2C") stays inside its element.
+func WithURLDecode(g Getter) Getter {
+	return &urlDecoder{g: g}
+}
+
+type urlDecoder struct {
+	g Getter
+}
+
+func (u *urlDecoder) Get(key string) string {
+	return unescape(u.g.Get(key))
+}
+
+func (u *urlDecoder) GetOrDefault(key string, dflt string) string {
+	return GetOrDefault(u, key, dflt)
+}
+
+func (u *urlDecoder) GetStrings(key string) []string {
+	vals := u.g.GetStrings(key)
+	rval := make([]string, len(vals))
+	for i, val := range vals {
+		rval[i] = unescape(val)
+	}
+	return rval
+}
+
+func (u *urlDecoder) MustGet(key string) string {
+	return MustGet(u, key)
+}
+
+func unescape(val string) string {
+	if decoded, err := url.PathUnescape(val); err == nil {
+		return decoded
+	}
+	return val
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestWithURLDecode(t *testing.T) {
+	os.Setenv("CONFIG_TEST_ENCODED", "p%40ss+1")
+	os.Setenv("CONFIG_TEST_BAD_ENCODING", "100%")
+	os.Setenv("CONFIG_TEST_ENCODED_LIST", "a%2Cb, c%20d,e")
+	defer func() {
+		os.Unsetenv("CONFIG_TEST_ENCODED")
+		os.Unsetenv("CONFIG_TEST_BAD_ENCODING")
+		os.Unsetenv("CONFIG_TEST_ENCODED_LIST")
+	}()
+	c := WithURLDecode(Environment())
+	if v := c.Get("CONFIG_TEST_ENCODED"); v != "p@ss+1" {
+		t.Errorf("Expected 'p@ss+1', got '%s'", v)
+	}
+	if v := c.Get("CONFIG_TEST_BAD_ENCODING"); v != "100%" {
+		t.Errorf("Invalid encoding should pass through; expected '100%%', got '%s'", v)
+	}
+	if v := c.GetOrDefault("CONFIG_TEST_UNSET", "x%3Dy"); v != "x%3Dy" {
+		t.Errorf("Defaults should not be decoded; expected 'x%%3Dy', got '%s'", v)
+	}
+	expected := []string{"a,b", "c d", "e"}
+	if v := c.GetStrings("CONFIG_TEST_ENCODED_LIST"); !reflect.DeepEqual(v, expected) {
+		t.Errorf("Expected %q, got %q", expected, v)
+	}
+}
+
+func TestWithURLDecodePlus(t *testing.T) {
+	c := WithURLDecode(Map{"KEY": "aGk+Zm9v/YmE=", "SPACE": "a%20b+c", "PLUS": "a%2Bb"})
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"KEY", "aGk+Zm9v/YmE="},
+		{"SPACE", "a b+c"},
+		{"PLUS", "a+b"},
+	}
+	for _, test := range tests {
+		if v := c.Get(test.key); v != test.expected {
+			t.Errorf("Key %s: expected '%s', got '%s'", test.key, test.expected, v)
+		}
+	}
+}
+
+// sharedList is a Getter whose GetStrings returns the same slice every time, as a caching Getter might.
+type sharedList struct {
+	Map
+	vals []string
+}
+
+func (s sharedList) GetStrings(string) []string {
+	return s.vals
+}
+
+func TestWithURLDecodeDoesNotMutate(t *testing.T) {
+	backend := sharedList{Map: Map{}, vals: []string{"a%2540b"}}
+	c := WithURLDecode(backend)
+	c.GetStrings("KEY")
+	if v := c.GetStrings("KEY"); !reflect.DeepEqual(v, []string{"a%40b"}) {
+		t.Errorf("Expected a single decode of the backend's value, got %q", v)
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSigned(t *testing.T, dir, data string, key ed25519.PrivateKey, encode bool) (string, string) {
+	configPath, sigPath := filepath.Join(dir, "app.json"), filepath.Join(dir, "app.json.sig")
+	sig := ed25519.Sign(key, []byte(data))
+	if encode {
+		sig = []byte(base64.StdEncoding.EncodeToString(sig) + "\n")
+	}
+	if err := os.WriteFile(configPath, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(sigPath, sig, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return configPath, sigPath
+}
+
+func TestNewVerifiedFileGetter(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	configPath, sigPath := writeSigned(t, dir, `{"db": {"host": "db1"}}`, priv, false)
+	g, err := NewVerifiedFileGetter(configPath, sigPath, pub)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := g.Get("db.host"); v != "db1" {
+		t.Errorf("Expected 'db1', got '%s'", v)
+	}
+
+	changes := g.Watch()
+	writeSigned(t, dir, `{"db": {"host": "db2"}}`, priv, true)
+	if err := g.Reload(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	select {
+	case <-changes:
+	default:
+		t.Error("Expected a change notification")
+	}
+	if v := g.Get("db.host"); v != "db2" {
+		t.Errorf("Expected 'db2' with a base64 signature, got '%s'", v)
+	}
+
+	// Tampering with the file is rejected, and the verified values stay.
+	if err := os.WriteFile(configPath, []byte(`{"db": {"host": "evil"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Reload(); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("Expected ErrSignatureInvalid, got %v", err)
+	}
+	if v := g.Get("db.host"); v != "db2" {
+		t.Errorf("Expected the verified 'db2' to stay, got '%s'", v)
+	}
+	if err := <-g.Rejections(); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("Expected the rejection to be reported, got %v", err)
+	}
+}
+
+func TestNewVerifiedFileGetterErrors(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	other, _, _ := ed25519.GenerateKey(rand.Reader)
+	configPath, sigPath := writeSigned(t, t.TempDir(), `{"a": "1"}`, priv, false)
+	if _, err := NewVerifiedFileGetter(configPath, sigPath, other); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("Expected ErrSignatureInvalid for the wrong key, got %v", err)
+	}
+	if err := os.WriteFile(sigPath, []byte("not a signature"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewVerifiedFileGetter(configPath, sigPath, pub); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("Expected ErrSignatureInvalid for a malformed signature, got %v", err)
+	}
+	if _, err := NewVerifiedFileGetter(configPath, sigPath+".missing", pub); err == nil {
+		t.Error("Expected an error for a missing signature")
+	}
+	if _, err := NewVerifiedFileGetter(configPath, sigPath, pub[:16]); err == nil {
+		t.Error("Expected an error for a short public key")
+	}
+}
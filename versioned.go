@@ -0,0 +1,71 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrVersionNotFound is returned by a VersionStore asked for a version it doesn't have.
+var ErrVersionNotFound = errors.New("config: version not found")
+
+// VersionStore abstracts a versioned key/value backend (an embedded database shipped with the app, for instance).
+//
+// The contract: a published version is immutable, so Load(v) returns the same values every time it's called; Load
+// returns all the keys and values of that version, in a map the caller may keep; and Load returns an error wrapping
+// ErrVersionNotFound for a version that doesn't exist.
+type VersionStore interface {
+	Load(version int) (map[string]string, error)
+}
+
+// NewVersionedGetter : Return a Getter pinned to one version of the config in store, so that reads are
+// reproducible, e.g. for blue/green rollouts where each deployment pins a known config version.
+// The version is loaded once, up front.
+func NewVersionedGetter(store VersionStore, version int) (Getter, error) {
+	values, err := store.Load(version)
+	if err != nil {
+		return nil, fmt.Errorf("config: loading version %d: %w", version, err)
+	}
+	return Map(values), nil
+}
+
+// MemoryVersionStore is an in-memory VersionStore, mostly for tests.
+type MemoryVersionStore struct {
+	lock     sync.RWMutex
+	versions map[int]map[string]string
+}
+
+// NewMemoryVersionStore : Return an empty MemoryVersionStore.
+func NewMemoryVersionStore() *MemoryVersionStore {
+	return &MemoryVersionStore{versions: make(map[int]map[string]string)}
+}
+
+// Publish : Store values as version. Versions are immutable, so publishing an existing version is an error.
+func (s *MemoryVersionStore) Publish(version int, values map[string]string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.versions[version]; ok {
+		return fmt.Errorf("config: version %d already published", version)
+	}
+	s.versions[version] = copyValues(values)
+	return nil
+}
+
+// Load : Return a copy of the values of version.
+func (s *MemoryVersionStore) Load(version int) (map[string]string, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	values, ok := s.versions[version]
+	if !ok {
+		return nil, fmt.Errorf("config: version %d: %w", version, ErrVersionNotFound)
+	}
+	return copyValues(values), nil
+}
+
+func copyValues(values map[string]string) map[string]string {
+	rval := make(map[string]string, len(values))
+	for k, v := range values {
+		rval[k] = v
+	}
+	return rval
+}
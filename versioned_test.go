@@ -0,0 +1,30 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVersionedGetter(t *testing.T) {
+	store := NewMemoryVersionStore()
+	v1 := map[string]string{"POOL_SIZE": "10"}
+	if err := store.Publish(1, v1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	store.Publish(2, map[string]string{"POOL_SIZE": "20"})
+	v1["POOL_SIZE"] = "mutated"
+
+	c, err := NewVersionedGetter(store, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := c.Get("POOL_SIZE"); v != "10" {
+		t.Errorf("Expected version 1 value '10', got '%s'", v)
+	}
+	if err := store.Publish(1, map[string]string{}); err == nil {
+		t.Error("Expected an error re-publishing a version")
+	}
+	if _, err := NewVersionedGetter(store, 3); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("Expected ErrVersionNotFound, got %v", err)
+	}
+}
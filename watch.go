@@ -0,0 +1,67 @@
+package config
+
+import (
+	"sync"
+)
+
+// Watcher is implemented by Getters whose values change while the program runs (they reload files, or follow
+// a remote source). Each call to Watch returns a new channel that receives a value after every change; changes
+// are coalesced, so a slow consumer sees at least one notification after the latest change, not one per change.
+// Call Watch once per consumer and re-read the values you care about on each notification.
+type Watcher interface {
+	Watch() <-chan struct{}
+}
+
+// Notifier implements Watcher. Embed it in a Getter and call Notify after each change.
+// The zero value is ready to use.
+type Notifier struct {
+	lock sync.Mutex
+	subs []chan struct{}
+}
+
+// Watch : Return a channel that receives a value after each change.
+func (n *Notifier) Watch() <-chan struct{} {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	ch := make(chan struct{}, 1)
+	n.subs = append(n.subs, ch)
+	return ch
+}
+
+// Notify : Signal every watcher, without blocking on any of them.
+func (n *Notifier) Notify() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	for _, ch := range n.subs {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ErrorReporter implements the Errors() channel of Getters that can fail in the background (refreshes, watches,
+// streams). Embed it in a Getter and call Report with each failure. The zero value is ready to use.
+type ErrorReporter struct {
+	once sync.Once
+	errs chan error
+}
+
+func (r *ErrorReporter) init() {
+	r.once.Do(func() { r.errs = make(chan error, 8) })
+}
+
+// Report : Send err to the Errors() channel, dropping it if nobody is keeping up.
+func (r *ErrorReporter) Report(err error) {
+	r.init()
+	select {
+	case r.errs <- err:
+	default:
+	}
+}
+
+// Errors : Background failures. The channel is buffered; errors are dropped when it's full.
+func (r *ErrorReporter) Errors() <-chan error {
+	r.init()
+	return r.errs
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNotifier(t *testing.T) {
+	var n Notifier
+	a, b := n.Watch(), n.Watch()
+	n.Notify()
+	n.Notify()
+	for name, ch := range map[string]<-chan struct{}{"a": a, "b": b} {
+		select {
+		case <-ch:
+		default:
+			t.Errorf("Watcher %s was not notified", name)
+		}
+		select {
+		case <-ch:
+			t.Errorf("Watcher %s: notifications should be coalesced", name)
+		default:
+		}
+	}
+}
+
+func TestErrorReporter(t *testing.T) {
+	var r ErrorReporter
+	for i := 0; i < 20; i++ {
+		r.Report(fmt.Errorf("failure %d", i))
+	}
+	if got := len(r.Errors()); got != cap(r.Errors()) {
+		t.Errorf("Expected a full buffer of %d errors, got %d", cap(r.Errors()), got)
+	}
+	if err := <-r.Errors(); err.Error() != "failure 0" {
+		t.Errorf("Expected the first error first, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package yamlconfig
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"github.com/efixler/config"
+)
+
+// AnsibleVars holds the values of an Ansible vars file. It's a config.Map, plus the keys that kept Jinja2 expressions.
+type AnsibleVars struct {
+	config.Map
+	unresolved []string
+}
+
+// Unresolved : Return the keys whose values still contain Jinja2 expressions, sorted, so a program can refuse to start
+// (or warn) rather than use a literal "{{ ... }}".
+func (a *AnsibleVars) Unresolved() []string {
+	return append([]string(nil), a.unresolved...)
+}
+
+var (
+	jinjaExpr = regexp.MustCompile(`\{\{(.*?)\}\}`)
+	// jinjaSafe matches the supported subset: a variable reference, optionally with a default filter.
+	jinjaSafe = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z0-9_]+)*)\s*(\|\s*default\(\s*(?:'([^']*)'|"([^"]*)"|(-?[0-9.]+))\s*\)\s*)?$`)
+)
+
+// NewAnsibleVarsGetter : Return a Getter over an Ansible vars file (group_vars, host_vars and the like), parsed and
+// flattened like NewYAMLGetter with merge keys expanded, so nested maps become dotted keys.
+//
+// Jinja2 expressions in values are resolved for a safe subset, and otherwise left as they are:
+//
+//	{{ name }}, {{ name.sub }}             another variable from the same file (nested ones by dotted path)
+//	{{ name | default('x') }}              the same, with a fallback for an unset variable (quoted or a number)
+//
+// A value can mix text and expressions ("{{ app }}-{{ env }}.example.com"), and a referenced value's own expressions
+// are resolved first. Anything else (other filters, tests, facts and hostvars, indexing, loops, an unset variable
+// with no default, a reference cycle) leaves the whole value literal, and its key is reported by Unresolved.
+// Vault-encrypted values (!vault) are passed through as their ciphertext.
+func NewAnsibleVarsGetter(path string) (*AnsibleVars, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("yamlconfig: %w", err)
+	}
+	values, err := ParseYAML(data, ExpandMerges)
+	if err != nil {
+		return nil, fmt.Errorf("yamlconfig: %s: %w", path, err)
+	}
+	r := &jinjaResolver{raw: values, resolved: make(map[string]string), failed: make(map[string]bool), active: make(map[string]bool)}
+	rval := &AnsibleVars{Map: make(config.Map, len(values))}
+	for key := range values {
+		val, ok := r.resolve(key)
+		if !ok {
+			val = values[key]
+			rval.unresolved = append(rval.unresolved, key)
+		}
+		rval.Map[key] = val
+	}
+	sort.Strings(rval.unresolved)
+	return rval, nil
+}
+
+// jinjaResolver resolves the safe subset of Jinja2 across a flattened vars map, memoizing results.
+type jinjaResolver struct {
+	raw      map[string]string
+	resolved map[string]string
+	failed   map[string]bool
+	active   map[string]bool
+}
+
+// resolve returns key's value with its expressions resolved, or false if any of them can't be.
+func (r *jinjaResolver) resolve(key string) (string, bool) {
+	if val, ok := r.resolved[key]; ok {
+		return val, true
+	}
+	if r.failed[key] || r.active[key] {
+		return "", false
+	}
+	r.active[key] = true
+	defer delete(r.active, key)
+	ok := true
+	val := jinjaExpr.ReplaceAllStringFunc(r.raw[key], func(expr string) string {
+		m := jinjaSafe.FindStringSubmatch(jinjaExpr.FindStringSubmatch(expr)[1])
+		if m == nil {
+			ok = false
+			return expr
+		}
+		if _, exists := r.raw[m[1]]; !exists {
+			if m[2] != "" {
+				return m[3] + m[4] + m[5]
+			}
+			ok = false
+			return expr
+		}
+		ref, refOK := r.resolve(m[1])
+		ok = ok && refOK
+		return ref
+	})
+	if !ok {
+		r.failed[key] = true
+		return "", false
+	}
+	r.resolved[key] = val
+	return val, true
+}
@@ -0,0 +1,53 @@
+package yamlconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const varsFile = `
+app: shop
+env: prod
+fqdn: "{{ app }}-{{ env }}.example.com"
+db:
+  host: "db.{{ fqdn }}"
+  port: 5432
+  url: "postgres://{{ db.host }}:{{ db.port }}"
+log_level: "{{ level | default('info') }}"
+workers: "{{ cpus | default(4) }}"
+upper: "{{ app | upper }}"
+fact: "{{ ansible_hostname }}"
+loop_a: "{{ loop_b }}"
+loop_b: "{{ loop_a }}"
+secret: !vault |
+  $ANSIBLE_VAULT;1.1;AES256
+  6162
+`
+
+func TestNewAnsibleVarsGetter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "all.yml")
+	os.WriteFile(path, []byte(varsFile), 0600)
+	g, err := NewAnsibleVarsGetter(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"fqdn":      "shop-prod.example.com",
+		"db.url":    "postgres://db.shop-prod.example.com:5432",
+		"log_level": "info",
+		"workers":   "4",
+		"upper":     "{{ app | upper }}",
+		"fact":      "{{ ansible_hostname }}",
+		"secret":    "$ANSIBLE_VAULT;1.1;AES256\n6162\n",
+	}
+	for key, val := range expected {
+		if v := g.Get(key); v != val {
+			t.Errorf("%s: expected '%s', got '%s'", key, val, v)
+		}
+	}
+	if got := g.Unresolved(); !reflect.DeepEqual(got, []string{"fact", "loop_a", "loop_b", "upper"}) {
+		t.Errorf("Unexpected unresolved keys %q", got)
+	}
+}
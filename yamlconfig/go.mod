@@ -0,0 +1,10 @@
+module github.com/efixler/config/yamlconfig
+
+go 1.22
+
+require (
+	github.com/efixler/config v0.0.0-00010101000000-000000000000
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+replace github.com/efixler/config => ../
@@ -0,0 +1,157 @@
+package yamlconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/efixler/config"
+)
+
+// NewValuesGetter : Return a Getter over the effective values of a Helm-style chart: base (the chart's values.yaml)
+// deep-merged with each of overrides in turn, so later documents win, as with helm's repeated -f flags. The merged
+// result is flattened with config.FlattenValue, so "image.tag" reads image: {tag: ...}. Use SetValues to add
+// --set overrides as the last layer:
+//
+//	set, err := yamlconfig.SetValues("image.tag=1.2.3,replicas=3")
+//	g, err := yamlconfig.NewValuesGetter(chartValues, envValues, set)
+//
+// The merge rules are Helm's: where both documents have a mapping the mappings are merged key by key; anything else
+// (scalars and sequences alike) in the later document replaces what the earlier one had, so lists are never
+// appended to; and a null in a later document deletes the key, mapping and all. Anchors and merge keys are
+// expanded within each document (see ExpandMerges) before documents are merged.
+func NewValuesGetter(base []byte, overrides ...[]byte) (config.Getter, error) {
+	merged := make(map[string]any)
+	for i, data := range append([][]byte{base}, overrides...) {
+		doc, err := decodeYAML(data, ExpandMerges)
+		if err != nil {
+			return nil, fmt.Errorf("yamlconfig: values document %d: %w", i, err)
+		}
+		mergeValues(merged, doc)
+	}
+	rval := make(config.Map)
+	config.FlattenValue(rval, "", merged)
+	return rval, nil
+}
+
+// mergeValues deep-merges src into dst with Helm's rules.
+func mergeValues(dst map[string]any, src map[string]any) {
+	for key, val := range src {
+		if val == nil {
+			delete(dst, key)
+			continue
+		}
+		srcMap, srcIsMap := val.(map[string]any)
+		dstMap, dstIsMap := dst[key].(map[string]any)
+		if srcIsMap && dstIsMap {
+			mergeValues(dstMap, srcMap)
+		} else {
+			dst[key] = val
+		}
+	}
+}
+
+// SetValues : Convert helm --set style assignments into a values document, for the last argument to
+// NewValuesGetter. Each argument is one --set flag: comma-separated assignments of the form path=value, where the
+// path is dot-separated keys ("image.tag"). A value in braces is a list ("hosts={a,b}"), "null" deletes the key, and
+// a backslash makes the next character literal, for commas and dots in keys or values ("nodeSelector.kubernetes\.io/os=linux").
+// Values are strings (or lists of strings); the flattened Getter would stringify them anyway. List indexes in
+// paths ("a[0]=x") aren't supported and are an error.
+func SetValues(sets ...string) ([]byte, error) {
+	root := make(map[string]any)
+	for _, set := range sets {
+		for _, assignment := range splitEscaped(set, ',', true) {
+			if assignment == "" {
+				continue
+			}
+			path, val, ok := cutEscaped(assignment, '=')
+			if !ok {
+				return nil, fmt.Errorf("yamlconfig: --set %q: expected path=value", assignment)
+			}
+			keys := splitEscaped(path, '.', false)
+			node := root
+			for i, key := range keys {
+				key = unescape(key)
+				if key == "" || strings.ContainsAny(key, "[]") {
+					return nil, fmt.Errorf("yamlconfig: --set %q: invalid path %q", assignment, path)
+				}
+				if i == len(keys)-1 {
+					node[key] = setValue(val)
+					break
+				}
+				next, ok := node[key].(map[string]any)
+				if !ok {
+					next = make(map[string]any)
+					node[key] = next
+				}
+				node = next
+			}
+		}
+	}
+	return yaml.Marshal(root)
+}
+
+func setValue(val string) any {
+	if val == "null" {
+		return nil
+	}
+	if strings.HasPrefix(val, "{") && strings.HasSuffix(val, "}") {
+		items := splitEscaped(val[1:len(val)-1], ',', false)
+		rval := make([]any, len(items))
+		for i, item := range items {
+			rval[i] = unescape(item)
+		}
+		return rval
+	}
+	return unescape(val)
+}
+
+// splitEscaped splits s on sep, except where sep is escaped with a backslash, keeping the escapes. With braces set,
+// separators inside {...} don't split either.
+func splitEscaped(s string, sep byte, braces bool) []string {
+	var rval []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			i++
+		case braces && c == '{':
+			depth++
+		case braces && c == '}' && depth > 0:
+			depth--
+		case c == sep && depth == 0:
+			rval = append(rval, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(rval, s[start:])
+}
+
+// cutEscaped is strings.Cut on the first unescaped sep.
+func cutEscaped(s string, sep byte) (string, string, bool) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+// unescape drops the backslashes that make the following characters literal.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
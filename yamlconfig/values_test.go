@@ -0,0 +1,67 @@
+package yamlconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewValuesGetter(t *testing.T) {
+	base := []byte(`
+image:
+  repository: app
+  tag: "1.0"
+replicas: 1
+hosts: [a, b]
+resources:
+  limits: {cpu: 500m}
+debug:
+  enabled: true
+`)
+	prod := []byte(`
+image:
+  tag: "1.1"
+hosts: [c]
+resources: ~
+`)
+	set, err := SetValues(`image.tag=1.2.3,replicas=3`, `debug=null`, `nodeSelector.kubernetes\.io/os=linux`, `extra={x,y,z}`, `note=a\,b`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g, err := NewValuesGetter(base, prod, set)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"image.repository":              "app",
+		"image.tag":                     "1.2.3",
+		"replicas":                      "3",
+		"hosts":                         "c",
+		"nodeSelector.kubernetes.io/os": "linux",
+		"extra":                         "x,y,z",
+		"note":                          "a,b",
+	}
+	for key, val := range expected {
+		if v := g.Get(key); v != val {
+			t.Errorf("Expected %s='%s', got '%s'", key, val, v)
+		}
+	}
+	for _, key := range []string{"resources.limits.cpu", "debug.enabled"} {
+		if v := g.Get(key); v != "" {
+			t.Errorf("Expected %s to be deleted by null, got '%s'", key, v)
+		}
+	}
+	if v := g.GetStrings("extra"); !reflect.DeepEqual(v, []string{"x", "y", "z"}) {
+		t.Errorf("Unexpected list %q", v)
+	}
+}
+
+func TestSetValuesErrors(t *testing.T) {
+	for _, set := range []string{"novalue", "a[0]=x", "a..b=x"} {
+		if _, err := SetValues(set); err == nil {
+			t.Errorf("Expected an error for %q", set)
+		}
+	}
+	if _, err := NewValuesGetter([]byte("- not a mapping")); err == nil {
+		t.Error("Expected an error for a document that isn't a mapping")
+	}
+}
@@ -0,0 +1,191 @@
+// Package yamlconfig provides config Getters over YAML documents. Importing it registers the "yaml" format
+// (see config.RegisterFormat), so the getters that parse documents by format name can read YAML too.
+// It's a separate package to keep the YAML dependency optional.
+package yamlconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/efixler/config"
+)
+
+// MergeMode says what to do with YAML merge keys ("<<").
+type MergeMode int
+
+const (
+	// ExpandMerges applies merge keys: the keys of the merged mapping(s) are added to the mapping holding the "<<",
+	// except where it sets them itself. With a sequence of mappings (<<: [*a, *b]) earlier mappings win over later
+	// ones. This is the default.
+	ExpandMerges MergeMode = iota
+	// LiteralMerges treats "<<" as an ordinary key, so a merged block is flattened under "<<" instead of expanded,
+	// for tools that need to see the document as written.
+	LiteralMerges
+)
+
+func init() {
+	config.RegisterFormat("yaml", func(data []byte) (map[string]string, error) {
+		return ParseYAML(data, ExpandMerges)
+	})
+}
+
+// NewYAMLGetter : Return a Getter over the YAML document at path, flattened with config.FlattenValue, with merge
+// keys handled per mode.
+//
+// Anchors and aliases are resolved before flattening: an alias yields the anchored node's value at the alias's
+// position, whether it's a scalar, a mapping (whose keys are flattened under the alias's key) or a sequence (a
+// comma-joined value, or indexed keys for sequences of mappings, as usual).
+func NewYAMLGetter(path string, mode MergeMode) (config.Getter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("yamlconfig: %w", err)
+	}
+	values, err := ParseYAML(data, mode)
+	if err != nil {
+		return nil, fmt.Errorf("yamlconfig: %s: %w", path, err)
+	}
+	return config.Map(values), nil
+}
+
+// ParseYAML : Parse and flatten a YAML document as described for NewYAMLGetter. At the top level the document
+// must be a mapping (or empty). A document that expands to more than about a million nodes once its aliases are
+// resolved is an error.
+func ParseYAML(data []byte, mode MergeMode) (map[string]string, error) {
+	obj, err := decodeYAML(data, mode)
+	if err != nil {
+		return nil, err
+	}
+	rval := make(map[string]string)
+	config.FlattenValue(rval, "", obj)
+	return rval, nil
+}
+
+// decodeYAML decodes a document whose top level is a mapping (or empty), resolving aliases and merges per mode.
+func decodeYAML(data []byte, mode MergeMode) (map[string]any, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return map[string]any{}, nil
+	}
+	d := &decoder{mode: mode, visiting: make(map[*yaml.Node]bool)}
+	v, err := d.value(doc.Content[0])
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a mapping at the top level, got %T", v)
+	}
+	return obj, nil
+}
+
+// maxNodes bounds the number of nodes a document can expand to once aliases are resolved, so that a small document
+// of nested aliases (a "billion laughs") can't exhaust memory.
+const maxNodes = 1 << 20
+
+// decoder converts yaml.Nodes to the map[string]any / []any / scalar trees that FlattenValue takes.
+type decoder struct {
+	mode     MergeMode
+	visiting map[*yaml.Node]bool
+	nodes    int
+}
+
+var (
+	errAliasCycle = errors.New("alias refers to itself")
+	errTooLarge   = fmt.Errorf("document expands to more than %d nodes", maxNodes)
+)
+
+func (d *decoder) value(n *yaml.Node) (any, error) {
+	if d.nodes++; d.nodes > maxNodes {
+		return nil, fmt.Errorf("line %d: %w", n.Line, errTooLarge)
+	}
+	switch n.Kind {
+	case yaml.AliasNode:
+		if d.visiting[n.Alias] {
+			return nil, fmt.Errorf("line %d: *%s: %w", n.Line, n.Value, errAliasCycle)
+		}
+		d.visiting[n.Alias] = true
+		defer delete(d.visiting, n.Alias)
+		return d.value(n.Alias)
+	case yaml.SequenceNode:
+		rval := make([]any, 0, len(n.Content))
+		for _, item := range n.Content {
+			v, err := d.value(item)
+			if err != nil {
+				return nil, err
+			}
+			rval = append(rval, v)
+		}
+		return rval, nil
+	case yaml.MappingNode:
+		return d.mapping(n)
+	case yaml.ScalarNode:
+		var rval any
+		if err := n.Decode(&rval); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n.Line, err)
+		}
+		return rval, nil
+	}
+	return nil, fmt.Errorf("line %d: unexpected YAML node", n.Line)
+}
+
+func (d *decoder) mapping(n *yaml.Node) (map[string]any, error) {
+	rval := make(map[string]any, len(n.Content)/2)
+	var merges []map[string]any
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		keyNode, valNode := n.Content[i], n.Content[i+1]
+		if d.mode == ExpandMerges && keyNode.Kind == yaml.ScalarNode && keyNode.ShortTag() == "!!merge" {
+			m, err := d.merges(valNode)
+			if err != nil {
+				return nil, err
+			}
+			merges = append(merges, m...)
+			continue
+		}
+		key, err := d.value(keyNode)
+		if err != nil {
+			return nil, err
+		}
+		val, err := d.value(valNode)
+		if err != nil {
+			return nil, err
+		}
+		rval[fmt.Sprint(key)] = val
+	}
+	for _, m := range merges {
+		for key, val := range m {
+			if _, ok := rval[key]; !ok {
+				rval[key] = val
+			}
+		}
+	}
+	return rval, nil
+}
+
+// merges returns the mappings to merge from the value of a "<<" key, in order of precedence.
+func (d *decoder) merges(n *yaml.Node) ([]map[string]any, error) {
+	v, err := d.value(n)
+	if err != nil {
+		return nil, err
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		return []map[string]any{v}, nil
+	case []any:
+		rval := make([]map[string]any, 0, len(v))
+		for _, item := range v {
+			m, ok := item.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("line %d: merge sequence entries must be mappings", n.Line)
+			}
+			rval = append(rval, m)
+		}
+		return rval, nil
+	}
+	return nil, fmt.Errorf("line %d: merge value must be a mapping or a sequence of mappings", n.Line)
+}
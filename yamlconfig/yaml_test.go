@@ -0,0 +1,111 @@
+package yamlconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/efixler/config"
+)
+
+const anchoredDoc = `
+defaults: &defaults
+  adapter: postgres
+  pool: 5
+  hosts: &hosts [db1, db2]
+timeouts: &timeouts
+  connect: 2s
+  pool: 10
+development:
+  <<: *defaults
+  database: dev
+production:
+  <<: [*defaults, *timeouts]
+  pool: 20
+  replicas: *hosts
+`
+
+func TestParseYAMLAnchors(t *testing.T) {
+	values, err := ParseYAML([]byte(anchoredDoc), ExpandMerges)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"development.adapter":  "postgres",
+		"development.pool":     "5",
+		"development.hosts":    "db1,db2",
+		"development.database": "dev",
+		"production.adapter":   "postgres",
+		"production.pool":      "20",
+		"production.connect":   "2s",
+		"production.hosts":     "db1,db2",
+		"production.replicas":  "db1,db2",
+	}
+	for key, val := range expected {
+		if values[key] != val {
+			t.Errorf("%s: expected '%s', got '%s'", key, val, values[key])
+		}
+	}
+	if _, ok := values["development.<<.adapter"]; ok {
+		t.Error("Merge keys should be expanded")
+	}
+}
+
+func TestParseYAMLMergePrecedence(t *testing.T) {
+	doc := "a: &a {x: 1, y: 1}\nb: &b {x: 2, z: 2}\nc:\n  <<: [*a, *b]\n"
+	values, err := ParseYAML([]byte(doc), ExpandMerges)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if values["c.x"] != "1" || values["c.z"] != "2" {
+		t.Errorf("Expected earlier merges to win, got %v", values)
+	}
+	values, err = ParseYAML([]byte(doc), LiteralMerges)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if values["c.<<.0.x"] != "1" || values["c.x"] != "" {
+		t.Errorf("Expected the merge key to be kept literally, got %v", values)
+	}
+}
+
+func TestNewYAMLGetter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte(anchoredDoc), 0600)
+	g, err := NewYAMLGetter(path, ExpandMerges)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := g.GetStrings("production.replicas"); !reflect.DeepEqual(v, []string{"db1", "db2"}) {
+		t.Errorf("Expected [db1 db2], got %q", v)
+	}
+	m, err := config.ParseFormat("yaml", []byte("a:\n  b: c\n"))
+	if err != nil || m.Get("a.b") != "c" {
+		t.Errorf("Expected the yaml format to be registered, got %v (%v)", m, err)
+	}
+	for _, doc := range []string{"- a\n- b\n", "a: [\n", "a:\n  <<: 5\n"} {
+		if _, err := ParseYAML([]byte(doc), ExpandMerges); err == nil {
+			t.Errorf("%q: expected an error", doc)
+		}
+	}
+}
+
+const laughsDoc = `
+a: &a ["lol","lol","lol","lol","lol","lol","lol","lol","lol"]
+b: &b [*a,*a,*a,*a,*a,*a,*a,*a,*a]
+c: &c [*b,*b,*b,*b,*b,*b,*b,*b,*b]
+d: &d [*c,*c,*c,*c,*c,*c,*c,*c,*c]
+e: &e [*d,*d,*d,*d,*d,*d,*d,*d,*d]
+f: &f [*e,*e,*e,*e,*e,*e,*e,*e,*e]
+g: &g [*f,*f,*f,*f,*f,*f,*f,*f,*f]
+h: &h [*g,*g,*g,*g,*g,*g,*g,*g,*g]
+i: &i [*h,*h,*h,*h,*h,*h,*h,*h,*h]
+`
+
+func TestParseYAMLBillionLaughs(t *testing.T) {
+	if _, err := ParseYAML([]byte(laughsDoc), ExpandMerges); !errors.Is(err, errTooLarge) {
+		t.Errorf("Expected errTooLarge, got %v", err)
+	}
+}
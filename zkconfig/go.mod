@@ -0,0 +1,10 @@
+module github.com/efixler/config/zkconfig
+
+go 1.22
+
+require (
+	github.com/efixler/config v0.0.0-00010101000000-000000000000
+	github.com/go-zookeeper/zk v1.0.4
+)
+
+replace github.com/efixler/config => ../
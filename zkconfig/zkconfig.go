@@ -0,0 +1,261 @@
+// Package zkconfig provides a config.Getter over the children of a ZooKeeper znode, for services in ecosystems
+// (Hadoop, Kafka and the like) that already coordinate their config through ZooKeeper.
+package zkconfig
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+
+	"github.com/efixler/config"
+)
+
+// Conn is the part of *zk.Conn the Getter uses.
+type Conn interface {
+	Get(path string) ([]byte, *zk.Stat, error)
+	GetW(path string) ([]byte, *zk.Stat, <-chan zk.Event, error)
+	ChildrenW(path string) ([]string, *zk.Stat, <-chan zk.Event, error)
+}
+
+// retryInterval is how long the Getter waits before trying again to re-establish its watches.
+const retryInterval = time.Second
+
+// Getter serves the children of a base znode from an in-memory cache that ZooKeeper watches keep current.
+// It is a config.ErrorGetter, a config.Lister and a config.Watcher.
+type Getter struct {
+	config.Notifier
+	conn      Conn
+	basePath  string
+	lock      sync.RWMutex
+	values    map[string]string
+	lost      map[string]bool
+	live      bool
+	resync    chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewZkGetter : Load the children of basePath and return a Getter where Get(key) is the data of the znode at
+// basePath+"/"+key. GetStrings splits values on commas, like the other getters.
+//
+// A watch on each znode, and one on basePath's children, keeps the values current; Watch() channels are notified
+// after each change. When watches are lost (the session expires, for instance) the Getter keeps trying to set them
+// again, every second, and then catches up with whatever changed in the meantime. Until then the affected keys stay
+// in Keys(), GetErr reads them from ZooKeeper so that connection errors surface, and Get falls back to the last
+// values seen. A znode that doesn't exist is an error wrapping config.ErrKeyNotSet. Call Close when you're done with
+// the Getter.
+func NewZkGetter(conn Conn, basePath string) (*Getter, error) {
+	g := &Getter{
+		conn:     conn,
+		basePath: path.Clean("/" + basePath),
+		values:   make(map[string]string),
+		lost:     make(map[string]bool),
+		resync:   make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	events, err := g.sync()
+	if err != nil {
+		g.Close()
+		return nil, err
+	}
+	go g.followChildren(events)
+	return g, nil
+}
+
+func (g *Getter) path(key string) string {
+	return g.basePath + "/" + key
+}
+
+// sync lists basePath's children with a new watch, and loads (and watches) the ones that are new to the cache or
+// whose watches were lost. Keys whose znodes are gone are dropped.
+func (g *Getter) sync() (<-chan zk.Event, error) {
+	children, _, events, err := g.conn.ChildrenW(g.basePath)
+	if err != nil {
+		return nil, fmt.Errorf("zkconfig: listing %s: %w", g.basePath, err)
+	}
+	present := make(map[string]bool, len(children))
+	for _, key := range children {
+		present[key] = true
+		g.lock.RLock()
+		_, ok := g.values[key]
+		watched := ok && !g.lost[key]
+		g.lock.RUnlock()
+		if watched {
+			continue
+		}
+		if err := g.load(key); errors.Is(err, zk.ErrNoNode) {
+			present[key] = false
+		} else if err != nil {
+			return nil, fmt.Errorf("zkconfig: reading %s: %w", g.path(key), err)
+		}
+	}
+	g.lock.Lock()
+	for key := range g.values {
+		if !present[key] {
+			delete(g.values, key)
+			delete(g.lost, key)
+		}
+	}
+	g.live = true
+	g.lock.Unlock()
+	return events, nil
+}
+
+// load reads key's znode and sets a watch on it.
+func (g *Getter) load(key string) error {
+	data, _, events, err := g.conn.GetW(g.path(key))
+	if err != nil {
+		return err
+	}
+	g.lock.Lock()
+	g.values[key] = string(data)
+	delete(g.lost, key)
+	g.lock.Unlock()
+	go g.followNode(key, events)
+	return nil
+}
+
+func (g *Getter) followNode(key string, events <-chan zk.Event) {
+	var ev zk.Event
+	select {
+	case ev = <-events:
+	case <-g.done:
+		return
+	}
+	switch ev.Type {
+	case zk.EventNodeDeleted:
+		g.drop(key)
+	case zk.EventNodeDataChanged:
+		if err := g.load(key); errors.Is(err, zk.ErrNoNode) {
+			g.drop(key)
+		} else if err != nil {
+			g.loseKey(key, fmt.Errorf("zkconfig: reading %s: %w", g.path(key), err))
+		}
+	default:
+		g.loseKey(key, fmt.Errorf("zkconfig: watch of %s ended: %v", g.path(key), ev.Err))
+	}
+	g.Notify()
+}
+
+func (g *Getter) drop(key string) {
+	g.lock.Lock()
+	delete(g.values, key)
+	delete(g.lost, key)
+	g.lock.Unlock()
+}
+
+// loseKey marks key's watch as lost, keeping its last value, and asks followChildren to set the watch again.
+func (g *Getter) loseKey(key string, err error) {
+	g.lock.Lock()
+	g.lost[key] = true
+	g.lock.Unlock()
+	log.Print(err)
+	select {
+	case g.resync <- struct{}{}:
+	default:
+	}
+}
+
+// followChildren re-syncs the cache when basePath's children change or a watch is lost, retrying until ZooKeeper
+// can be reached again.
+func (g *Getter) followChildren(events <-chan zk.Event) {
+	for {
+		select {
+		case <-events:
+		case <-g.resync:
+		case <-g.done:
+			return
+		}
+		next, err := g.sync()
+		for err != nil {
+			g.lock.Lock()
+			wasLive := g.live
+			g.live = false
+			g.lock.Unlock()
+			if wasLive {
+				log.Printf("zkconfig: watch of %s ended, retrying: %v", g.basePath, err)
+			}
+			select {
+			case <-time.After(retryInterval):
+			case <-g.done:
+				return
+			}
+			next, err = g.sync()
+		}
+		g.Notify()
+		events = next
+	}
+}
+
+// Close : Stop following the watches. The Getter keeps serving the values it has.
+func (g *Getter) Close() error {
+	g.closeOnce.Do(func() { close(g.done) })
+	return nil
+}
+
+// GetErr : Return the latest value for key. A znode that doesn't exist is an error wrapping config.ErrKeyNotSet.
+func (g *Getter) GetErr(key string) (string, error) {
+	g.lock.RLock()
+	val, ok := g.values[key]
+	direct := g.lost[key] || (!ok && !g.live)
+	g.lock.RUnlock()
+	if !direct {
+		if !ok {
+			return "", fmt.Errorf("zkconfig: %s: %w", g.path(key), config.ErrKeyNotSet)
+		}
+		return val, nil
+	}
+	data, _, err := g.conn.Get(g.path(key))
+	if errors.Is(err, zk.ErrNoNode) {
+		return "", fmt.Errorf("zkconfig: %s: %w", g.path(key), config.ErrKeyNotSet)
+	} else if err != nil {
+		return "", fmt.Errorf("zkconfig: reading %s: %w", g.path(key), err)
+	}
+	return string(data), nil
+}
+
+// Get : Return the latest value for key, or "" if it's missing. If key's watch is lost and ZooKeeper can't be
+// reached, the last value seen is returned.
+func (g *Getter) Get(key string) string {
+	val, err := g.GetErr(key)
+	if err != nil && !errors.Is(err, config.ErrKeyNotSet) {
+		log.Print(err)
+		g.lock.RLock()
+		val = g.values[key]
+		g.lock.RUnlock()
+	}
+	return val
+}
+
+// GetOrDefault : If the requested key is not present or empty, return the dflt.
+func (g *Getter) GetOrDefault(key string, dflt string) string {
+	return config.GetOrDefault(g, key, dflt)
+}
+
+// GetStrings will treat a comma-delimited config value as an []string, stripping whitespace around the commas.
+func (g *Getter) GetStrings(key string) []string {
+	return config.SplitStrings(g.Get(key))
+}
+
+// MustGet will panic if the key is not present or empty.
+func (g *Getter) MustGet(key string) string {
+	return config.MustGet(g, key)
+}
+
+// Keys : Return the keys currently in the cache, including those whose watches are being re-established, sorted.
+func (g *Getter) Keys() []string {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	rval := make([]string, 0, len(g.values))
+	for key := range g.values {
+		rval = append(rval, key)
+	}
+	sort.Strings(rval)
+	return rval
+}
@@ -0,0 +1,188 @@
+package zkconfig
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+
+	"github.com/efixler/config"
+)
+
+type fakeConn struct {
+	lock     sync.Mutex
+	nodes    map[string]string
+	watches  map[string]chan zk.Event
+	children chan zk.Event
+	down     bool
+}
+
+func newFakeConn(nodes map[string]string) *fakeConn {
+	return &fakeConn{nodes: nodes, watches: make(map[string]chan zk.Event)}
+}
+
+func (c *fakeConn) Get(p string) ([]byte, *zk.Stat, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.down {
+		return nil, nil, zk.ErrConnectionClosed
+	}
+	v, ok := c.nodes[p]
+	if !ok {
+		return nil, nil, zk.ErrNoNode
+	}
+	return []byte(v), &zk.Stat{}, nil
+}
+
+func (c *fakeConn) GetW(p string) ([]byte, *zk.Stat, <-chan zk.Event, error) {
+	data, stat, err := c.Get(p)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	ch := make(chan zk.Event, 1)
+	c.watches[p] = ch
+	return data, stat, ch, nil
+}
+
+func (c *fakeConn) ChildrenW(p string) ([]string, *zk.Stat, <-chan zk.Event, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.down {
+		return nil, nil, nil, zk.ErrConnectionClosed
+	}
+	var rval []string
+	for node := range c.nodes {
+		if len(node) > len(p)+1 && node[:len(p)+1] == p+"/" {
+			rval = append(rval, node[len(p)+1:])
+		}
+	}
+	c.children = make(chan zk.Event, 1)
+	return rval, &zk.Stat{}, c.children, nil
+}
+
+func (c *fakeConn) set(p string, v string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, existed := c.nodes[p]
+	c.nodes[p] = v
+	if existed {
+		c.watches[p] <- zk.Event{Type: zk.EventNodeDataChanged, Path: p}
+	} else {
+		c.children <- zk.Event{Type: zk.EventNodeChildrenChanged}
+	}
+}
+
+func (c *fakeConn) delete(p string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.nodes, p)
+	c.watches[p] <- zk.Event{Type: zk.EventNodeDeleted, Path: p}
+}
+
+func waitFor(t *testing.T, changes <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a change notification")
+	}
+}
+
+func TestZkGetter(t *testing.T) {
+	conn := newFakeConn(map[string]string{"/app/HOSTS": "a, b", "/app/MODE": "blue", "/other/X": "1"})
+	g, err := NewZkGetter(conn, "/app")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	if v := g.GetStrings("HOSTS"); !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %q", v)
+	}
+	if v := g.Keys(); !reflect.DeepEqual(v, []string{"HOSTS", "MODE"}) {
+		t.Errorf("Expected keys [HOSTS MODE], got %q", v)
+	}
+	if _, err := g.GetErr("X"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected ErrKeyNotSet, got %v", err)
+	}
+
+	changes := g.Watch()
+	conn.set("/app/MODE", "green")
+	waitFor(t, changes)
+	if v := g.Get("MODE"); v != "green" {
+		t.Errorf("Expected updated value 'green', got '%s'", v)
+	}
+	conn.set("/app/NEW", "yes")
+	waitFor(t, changes)
+	if v := g.Get("NEW"); v != "yes" {
+		t.Errorf("Expected new key value 'yes', got '%s'", v)
+	}
+	conn.delete("/app/HOSTS")
+	waitFor(t, changes)
+	if _, err := g.GetErr("HOSTS"); !errors.Is(err, config.ErrKeyNotSet) {
+		t.Errorf("Expected deleted key to be ErrKeyNotSet, got %v", err)
+	}
+}
+
+func TestZkGetterLostWatch(t *testing.T) {
+	conn := newFakeConn(map[string]string{"/app/MODE": "blue"})
+	g, err := NewZkGetter(conn, "app")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer g.Close()
+	changes := g.Watch()
+	conn.lock.Lock()
+	conn.down = true
+	conn.watches["/app/MODE"] <- zk.Event{Type: zk.EventNotWatching, Err: zk.ErrSessionExpired}
+	conn.lock.Unlock()
+	waitFor(t, changes)
+	if _, err := g.GetErr("MODE"); !errors.Is(err, zk.ErrConnectionClosed) {
+		t.Errorf("Expected a connection error once the watch is lost, got %v", err)
+	}
+	if v := g.Get("MODE"); v != "blue" {
+		t.Errorf("Expected the last value 'blue' while ZooKeeper is down, got '%s'", v)
+	}
+	if v := g.Keys(); !reflect.DeepEqual(v, []string{"MODE"}) {
+		t.Errorf("Expected a lost key to stay in Keys, got %q", v)
+	}
+	if _, err := NewZkGetter(conn, "/app"); !errors.Is(err, zk.ErrConnectionClosed) {
+		t.Errorf("Expected a connection error from NewZkGetter, got %v", err)
+	}
+
+	// The session comes back, with a key that was created in the meantime.
+	conn.lock.Lock()
+	conn.down = false
+	conn.nodes["/app/NEW"] = "yes"
+	conn.lock.Unlock()
+	select {
+	case <-changes:
+	case <-time.After(3 * retryInterval):
+		t.Fatal("Expected a change notification once the watches are set again")
+	}
+	if v := g.Keys(); !reflect.DeepEqual(v, []string{"MODE", "NEW"}) {
+		t.Errorf("Expected keys [MODE NEW] after re-syncing, got %q", v)
+	}
+	conn.set("/app/MODE", "green")
+	waitFor(t, changes)
+	if v := g.Get("MODE"); v != "green" {
+		t.Errorf("Expected the re-established watch to deliver 'green', got '%s'", v)
+	}
+}
+
+func TestZkGetterNoNode(t *testing.T) {
+	conn := newFakeConn(map[string]string{})
+	if _, err := NewZkGetter(&noNodeConn{conn}, "/missing"); !errors.Is(err, zk.ErrNoNode) {
+		t.Errorf("Expected ErrNoNode for a missing base path, got %v", err)
+	}
+}
+
+type noNodeConn struct{ *fakeConn }
+
+func (c *noNodeConn) ChildrenW(string) ([]string, *zk.Stat, <-chan zk.Event, error) {
+	return nil, nil, nil, zk.ErrNoNode
+}